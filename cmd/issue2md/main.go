@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
 
+	"github.com/wangyulu/issue2md2/internal/assets"
 	"github.com/wangyulu/issue2md2/internal/cli"
 	"github.com/wangyulu/issue2md2/internal/config"
 	"github.com/wangyulu/issue2md2/internal/converter"
+	"github.com/wangyulu/issue2md2/internal/export"
 	"github.com/wangyulu/issue2md2/internal/github"
 	"github.com/wangyulu/issue2md2/internal/parser"
 )
@@ -15,85 +21,224 @@ func main() {
 	// 解析命令行参数
 	flags, args, err := cli.ParseArgs(os.Args[1:])
 	if err != nil {
+		// 检查是否需要显示帮助
+		if err.Error() == cli.ErrHelpDisplayed {
+			os.Exit(0)
+		}
 		cli.PrintError(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	// 检查是否需要显示帮助
-	if err != nil && err.Error() == cli.ErrHelpDisplayed {
-		os.Exit(0)
+	hosts := config.GetGitHubHosts()
+	opts := &converter.Options{
+		Format:            converter.Format(flags.Format),
+		Frontmatter:       converter.FrontmatterFormat(flags.Frontmatter),
+		FrontmatterFields: flags.FrontmatterFields,
+		AnchorMode:        converter.AnchorMode(flags.Anchor),
+		AnchorContext:     flags.AnchorContext,
+		Minimized:         converter.MinimizedMode(flags.Minimized),
+		EditHistory:       converter.EditHistoryMode(flags.EditHistory),
+		MaxDiffLines:      flags.MaxDiffLines,
+		EnableReactions:   flags.EnableReactions,
+		EnableUserLinks:   flags.EnableUserLinks,
 	}
 
-	// 解析 URL
-	resource, err := parser.ParseURL(args.URL)
-	if err != nil {
-		cli.PrintError(os.Stderr, err)
-		os.Exit(1)
+	// 自定义模板：先解析一次，尽早报告语法错误
+	if flags.Template != "" {
+		opts.Template, err = loadTemplate(flags.Template, opts)
+		if err != nil {
+			cli.PrintError(os.Stderr, err)
+			os.Exit(1)
+		}
 	}
 
-	// 获取 token（可选）
-	token := config.GetGitHubToken()
+	// Ctrl-C 时取消进行中的请求
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	// 创建 GitHub 客户端
-	client := github.NewClient()
-	_ = token // 避免未使用变量警告
-
-	// 根据资源类型获取数据
-	var markdown []byte
-	switch resource.Type {
-	case parser.ResourceTypeIssue:
-		issue, err := client.FetchIssue(resource.Owner, resource.Repo, resource.Number)
+	// 批量模式：从文件或 stdin 读取 URL 列表
+	if flags.BatchFile != "" {
+		results, err := exportBatch(ctx, flags, hosts, args.OutputFile, opts)
 		if err != nil {
-			cli.PrintError(os.Stderr, fmt.Errorf("failed to fetch issue: %w", err))
+			cli.PrintError(os.Stderr, err)
+			os.Exit(1)
+		}
+		if cli.PrintSummary(os.Stderr, results) > 0 {
 			os.Exit(1)
 		}
-		markdown, err = converter.ToMarkdown(issue, &converter.Options{
-			EnableReactions: flags.EnableReactions,
-			EnableUserLinks: flags.EnableUserLinks,
-		})
+		return
+	}
 
-	case parser.ResourceTypePullRequest:
-		pr, err := client.FetchPullRequest(resource.Owner, resource.Repo, resource.Number)
+	// 仓库 URL：导出仓库中的全部资源
+	if repo, err := parser.ParseRepositoryURL(args.URL, hosts...); err == nil {
+		results, err := exportRepository(ctx, flags, repo, args.OutputFile, opts)
 		if err != nil {
-			cli.PrintError(os.Stderr, fmt.Errorf("failed to fetch pull request: %w", err))
+			cli.PrintError(os.Stderr, err)
 			os.Exit(1)
 		}
-		markdown, err = converter.ToMarkdownPR(pr, &converter.Options{
-			EnableReactions: flags.EnableReactions,
-			EnableUserLinks: flags.EnableUserLinks,
-		})
+		if cli.PrintSummary(os.Stderr, results) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 解析 URL
+	resource, err := parser.ParseURL(args.URL, hosts...)
+	if err != nil {
+		cli.PrintError(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	case parser.ResourceTypeDiscussion:
-		discussion, err := client.FetchDiscussion(resource.Owner, resource.Repo, resource.Number)
+	// 写入文件
+	if args.OutputFile != "" {
+		err := export.WriteFile(ctx, newClient(flags, resource.Host), resource, args.OutputFile, newDownloader(flags), opts)
 		if err != nil {
-			cli.PrintError(os.Stderr, fmt.Errorf("failed to fetch discussion: %w", err))
+			cli.PrintError(os.Stderr, err)
 			os.Exit(1)
 		}
-		markdown, err = converter.ToMarkdownDiscussion(discussion, &converter.Options{
-			EnableReactions: flags.EnableReactions,
-			EnableUserLinks: flags.EnableUserLinks,
-		})
+		return
+	}
 
-	default:
-		cli.PrintError(os.Stderr, fmt.Errorf("unsupported resource type: %s", resource.Type))
+	// 获取数据并转换为输出格式，输出到 stdout
+	output, err := export.Render(ctx, newClient(flags, resource.Host), resource, opts)
+	if err != nil {
+		cli.PrintError(os.Stderr, err)
 		os.Exit(1)
 	}
+	fmt.Print(string(output))
+}
 
+// loadTemplate 读取并校验模板文件，返回模板内容
+func loadTemplate(path string, opts *converter.Options) (string, error) {
+	content, err := os.ReadFile(path)
 	if err != nil {
-		cli.PrintError(os.Stderr, fmt.Errorf("failed to convert to markdown: %w", err))
-		os.Exit(1)
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+	if _, err := converter.ParseTemplate(string(content), opts); err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// newClient 根据命令行标志创建访问 host 的 GitHub 客户端
+func newClient(flags *cli.Flags, host string) *github.Client {
+	endpoint := flags.APIURL
+	if endpoint == "" {
+		endpoint = github.GraphQLEndpoint(host)
+	}
+
+	return github.NewClient(
+		github.WithToken(config.GetGitHubToken()),
+		github.WithEndpoint(endpoint),
+		github.WithTimeout(flags.Timeout),
+		github.WithRetry(flags.MaxRetries, flags.MaxRetryWait),
+		github.WithPageSize(flags.PageSize),
+		github.WithMaxComments(flags.MaxComments),
+		github.WithTimeline(flags.EnableTimeline),
+		github.WithFiles(flags.EnableFiles),
+		github.WithDiff(flags.EnableDiff),
+		github.WithEditHistory(flags.EditHistory != "none"),
+	)
+}
+
+// newDownloader 根据命令行标志创建图片和附件下载器，未指定 -download-assets 时返回 nil
+func newDownloader(flags *cli.Flags) *assets.Downloader {
+	if !flags.DownloadAssets {
+		return nil
+	}
+	return assets.NewDownloader(
+		assets.WithToken(config.GetGitHubToken()),
+		assets.WithHTTPClient(&http.Client{Timeout: flags.Timeout}),
+	)
+}
+
+// clientCache 返回按主机名复用 GitHub 客户端的 ClientFunc，可并发调用
+// 同一主机的所有 worker 共用一个客户端，从而共享限流状态
+func clientCache(flags *cli.Flags) export.ClientFunc {
+	var mu sync.Mutex
+	clients := make(map[string]*github.Client)
+	return func(host string) *github.Client {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if c, ok := clients[host]; ok {
+			return c
+		}
+		c := newClient(flags, host)
+		clients[host] = c
+		return c
+	}
+}
+
+// exportRepository 将仓库中满足筛选条件的资源导出到 outputDir，每个资源一个文件
+func exportRepository(ctx context.Context, flags *cli.Flags, repo *parser.Repository, outputDir string, opts *converter.Options) ([]export.Result, error) {
+	if outputDir == "" {
+		return nil, fmt.Errorf(cli.ErrMissingRequiredArg, "output_dir")
+	}
+
+	filter := &export.Filter{
+		States:        flags.States,
+		Labels:        flags.Labels,
+		Author:        flags.Author,
+		CreatedAfter:  flags.CreatedAfter,
+		CreatedBefore: flags.CreatedBefore,
+		UpdatedAfter:  flags.UpdatedAfter,
+		UpdatedBefore: flags.UpdatedBefore,
+	}
+
+	clientFor := clientCache(flags)
+	resources, err := export.ListRepository(ctx, clientFor(repo.Host), repo, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository: %w", err)
+	}
+
+	pattern := flags.NamePattern
+	if pattern == "" {
+		pattern = export.DefaultFileNamePattern
 	}
 
-	// 输出结果
-	if args.OutputFile == "" {
-		// 输出到 stdout
-		fmt.Print(string(markdown))
-	} else {
-		// 写入文件
-		err = os.WriteFile(args.OutputFile, markdown, 0644)
+	return export.ToDir(ctx, clientFor, resources, outputDir, pattern, flags.Concurrency, newDownloader(flags), opts), nil
+}
+
+// exportBatch 读取 URL 列表并逐个导出到 outputDir，结果与列表顺序一致
+// 无法解析的 URL 记为失败，不影响其余 URL
+func exportBatch(ctx context.Context, flags *cli.Flags, hosts []string, outputDir string, opts *converter.Options) ([]export.Result, error) {
+	input := os.Stdin
+	if flags.BatchFile != "-" {
+		f, err := os.Open(flags.BatchFile)
 		if err != nil {
-			cli.PrintError(os.Stderr, fmt.Errorf("failed to write file: %w", err))
-			os.Exit(1)
+			return nil, fmt.Errorf("failed to open URL list: %w", err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	urls, err := export.ReadURLList(input)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]export.Result, len(urls))
+	var resources []*parser.Resource
+	var indexes []int
+	for i, u := range urls {
+		resource, err := parser.ParseURL(u, hosts...)
+		if err != nil {
+			results[i] = export.Result{URL: u, Err: err}
+			continue
 		}
+		resources = append(resources, resource)
+		indexes = append(indexes, i)
+	}
+
+	pattern := flags.NamePattern
+	if pattern == "" {
+		pattern = export.DefaultBatchFileNamePattern
+	}
+
+	for j, result := range export.ToDir(ctx, clientCache(flags), resources, outputDir, pattern, flags.Concurrency, newDownloader(flags), opts) {
+		results[indexes[j]] = result
 	}
+
+	return results, nil
 }
@@ -2,9 +2,13 @@ module github.com/wangyulu/issue2md2
 
 go 1.25.1
 
+require (
+	github.com/shurcooL/githubv4 v0.0.0-20240727222349-48295856cce7
+	github.com/yuin/goldmark v1.7.13
+)
+
 require (
 	github.com/google/go-github/v68 v68.0.0 // indirect
-	github.com/shurcooL/githubv4 v0.0.0-20240727222349-48295856cce7 // indirect
 	github.com/shurcooL/graphql v0.0.0-20230722043721-ed46e5a46466 // indirect
 	golang.org/x/oauth2 v0.34.0 // indirect
 )
@@ -0,0 +1,328 @@
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DirName 资源文件目录名，位于输出文件所在的目录下
+const DirName = "assets"
+
+// MaxSize 单个资源文件的大小上限，超过时不下载
+const MaxSize = 100 << 20
+
+// urlPattern 匹配正文中直接出现的 URL（如 GitHub 上传视频时插入的裸链接），不含括号
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+
+// attrPattern 匹配 HTML 的 src 和 href 属性，第一或第二个分组为属性值
+var attrPattern = regexp.MustCompile(`(?i)\b(?:src|href)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+// Downloader 下载正文中引用的图片和附件，按内容哈希去重保存，可被多个 goroutine 同时使用
+type Downloader struct {
+	httpClient *http.Client
+	token      string
+	warnings   io.Writer // 下载失败时的警告输出
+
+	mu    sync.Mutex
+	names map[string]string // 保存目录和 URL -> 已保存的文件名
+}
+
+// Option Downloader 配置选项
+type Option func(*Downloader)
+
+// WithToken 设置访问私有仓库附件使用的 Token，只发送给资源所在的 GitHub 主机
+func WithToken(token string) Option {
+	return func(d *Downloader) {
+		d.token = token
+	}
+}
+
+// WithHTTPClient 设置下载使用的 HTTP 客户端
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(d *Downloader) {
+		d.httpClient = httpClient
+	}
+}
+
+// WithWarnings 设置下载失败时警告的输出位置，默认为 os.Stderr
+func WithWarnings(w io.Writer) Option {
+	return func(d *Downloader) {
+		d.warnings = w
+	}
+}
+
+// NewDownloader 创建 Downloader
+func NewDownloader(opts ...Option) *Downloader {
+	d := &Downloader{
+		httpClient: http.DefaultClient,
+		warnings:   os.Stderr,
+		names:      make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Localize 下载 body 中引用的 GitHub 图片和附件到 dir 下的 assets 目录，
+// 并将链接改写为相对于 dir 的路径，如 assets/3f2a...png
+// host 为资源所在的 GitHub 主机名，用于识别附件链接和发送 Token
+// 单个文件下载失败（如 404、签名链接过期）时保留原链接并输出警告，只有 ctx 被取消时返回错误
+func (d *Downloader) Localize(ctx context.Context, body, host, dir string) (string, error) {
+	names := make(map[string]string) // URL -> 保存的文件名，下载失败时为空
+	var b strings.Builder
+	last := 0
+
+	for _, loc := range findURLs(body) {
+		raw := body[loc[0]:loc[1]]
+		name, ok := names[raw]
+		if !ok {
+			u, err := url.Parse(raw)
+			if err == nil && IsAssetURL(u, host) {
+				name, err = d.download(ctx, u, host, filepath.Join(dir, DirName))
+				if err != nil {
+					if ctx.Err() != nil {
+						return "", fmt.Errorf("failed to download asset %s: %w", raw, err)
+					}
+					fmt.Fprintf(d.warnings, "warning: failed to download asset %s: %v (keeping the original URL)\n", raw, err)
+				}
+			}
+			names[raw] = name
+		}
+		if name == "" {
+			continue
+		}
+		b.WriteString(body[last:loc[0]])
+		b.WriteString(DirName + "/" + name)
+		last = loc[1]
+	}
+
+	if last == 0 {
+		return body, nil
+	}
+	b.WriteString(body[last:])
+	return b.String(), nil
+}
+
+// findURLs 返回正文中 URL 的位置 [start, end)，按出现顺序排列且互不重叠
+// 依次查找 Markdown 链接和图片的目标（允许成对的括号，如 screenshot (1).png）、HTML 的 src 和 href 属性，
+// 以及不在以上位置的裸链接（去掉末尾的标点）
+func findURLs(body string) [][2]int {
+	locs := markdownTargets(body)
+	for _, m := range attrPattern.FindAllStringSubmatchIndex(body, -1) {
+		if m[2] >= 0 {
+			locs = append(locs, [2]int{m[2], m[3]})
+		} else {
+			locs = append(locs, [2]int{m[4], m[5]})
+		}
+	}
+	for _, m := range urlPattern.FindAllStringIndex(body, -1) {
+		end := m[0] + len(strings.TrimRight(body[m[0]:m[1]], ".,;:!?"))
+		locs = append(locs, [2]int{m[0], end})
+	}
+
+	sort.SliceStable(locs, func(i, j int) bool { return locs[i][0] < locs[j][0] })
+	var result [][2]int
+	for _, loc := range locs {
+		if len(result) > 0 && loc[0] < result[len(result)-1][1] {
+			continue
+		}
+		if strings.HasPrefix(body[loc[0]:loc[1]], "http://") || strings.HasPrefix(body[loc[0]:loc[1]], "https://") {
+			result = append(result, loc)
+		}
+	}
+	return result
+}
+
+// markdownTargets 返回 Markdown 链接和图片 [text](target "title") 中 target 的位置
+// target 可以用 <> 包裹；未包裹时到空白或不成对的右括号为止
+func markdownTargets(body string) [][2]int {
+	var result [][2]int
+	for i := 0; ; {
+		j := strings.Index(body[i:], "](")
+		if j < 0 {
+			return result
+		}
+		start := i + j + 2
+		i = start
+
+		if strings.HasPrefix(body[start:], "<") {
+			if end := strings.IndexAny(body[start+1:], ">\n"); end >= 0 && body[start+1+end] == '>' {
+				result = append(result, [2]int{start + 1, start + 1 + end})
+			}
+			continue
+		}
+
+		depth, end := 0, start
+	scan:
+		for ; end < len(body); end++ {
+			switch body[end] {
+			case ' ', '\t', '\n', '\r':
+				break scan
+			case '(':
+				depth++
+			case ')':
+				if depth == 0 {
+					break scan
+				}
+				depth--
+			}
+		}
+		if end > start {
+			result = append(result, [2]int{start, end})
+		}
+	}
+}
+
+// IsAssetURL 判断 URL 是否为 GitHub 上传的图片或附件
+//   - https://user-images.githubusercontent.com/...、https://private-user-images.githubusercontent.com/...
+//   - https://HOST/user-attachments/...
+//   - https://HOST/OWNER/REPO/assets/... 和 https://HOST/OWNER/REPO/files/...
+func IsAssetURL(u *url.URL, host string) bool {
+	switch u.Host {
+	case "user-images.githubusercontent.com", "private-user-images.githubusercontent.com":
+		return true
+	case host, "github.com":
+	default:
+		return false
+	}
+
+	if strings.HasPrefix(u.Path, "/user-attachments/") {
+		return true
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	return len(parts) >= 4 && (parts[2] == "assets" || parts[2] == "files")
+}
+
+// download 下载 URL 到 dir，返回文件名；同一 URL 只下载一次，内容相同的文件只保存一份
+func (d *Downloader) download(ctx context.Context, u *url.URL, host, dir string) (string, error) {
+	key := dir + "\n" + u.String()
+
+	d.mu.Lock()
+	name, ok := d.names[key]
+	d.mu.Unlock()
+	if ok {
+		return name, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	// 只向资源所在的主机发送 Token：导出 GitHub Enterprise Server 的资源时正文中也可能引用 github.com 的附件，
+	// 不能把 Enterprise 的 Token 发给 github.com；重定向到其他域名时 net/http 会自动去掉该请求头
+	if d.token != "" && u.Host == host {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, MaxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(content) > MaxSize {
+		return "", fmt.Errorf("file larger than %d MB", MaxSize>>20)
+	}
+
+	sum := sha256.Sum256(content)
+	name = hex.EncodeToString(sum[:])[:16] + extension(u, resp.Header.Get("Content-Type"))
+	if err := writeFile(filepath.Join(dir, name), content); err != nil {
+		return "", err
+	}
+
+	d.mu.Lock()
+	d.names[key] = name
+	d.mu.Unlock()
+	return name, nil
+}
+
+// extension 返回文件扩展名（含点），优先使用 URL 中的扩展名，其次根据 Content-Type 推断
+func extension(u *url.URL, contentType string) string {
+	if ext := path.Ext(u.Path); len(ext) > 1 && len(ext) <= 6 {
+		return strings.ToLower(ext)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	case "video/mp4":
+		return ".mp4"
+	case "video/quicktime":
+		return ".mov"
+	case "application/pdf":
+		return ".pdf"
+	case "application/zip":
+		return ".zip"
+	case "text/plain":
+		return ".txt"
+	}
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// writeFile 写入文件，文件已存在时直接返回
+// 先写入临时文件再重命名，避免并发写入同一文件时产生不完整的内容
+func writeFile(name string, content []byte) error {
+	if _, err := os.Stat(name); err == nil {
+		return nil
+	}
+
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create assets directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return fmt.Errorf("failed to create asset file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write asset file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write asset file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write asset file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("failed to write asset file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIsAssetURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		host     string
+		expected bool
+	}{
+		{name: "user-images", url: "https://user-images.githubusercontent.com/1/abc.png", host: "github.com", expected: true},
+		{name: "private-user-images", url: "https://private-user-images.githubusercontent.com/1/abc.png?jwt=x", host: "github.com", expected: true},
+		{name: "user-attachments", url: "https://github.com/user-attachments/assets/0b6e-4c1f", host: "github.com", expected: true},
+		{name: "仓库 assets", url: "https://github.com/owner/repo/assets/1/0b6e-4c1f", host: "github.com", expected: true},
+		{name: "仓库 files", url: "https://github.com/owner/repo/files/123/log.txt", host: "github.com", expected: true},
+		{name: "Enterprise 附件", url: "https://github.example.com/user-attachments/assets/0b6e", host: "github.example.com", expected: true},
+		{name: "Issue 链接", url: "https://github.com/owner/repo/issues/1", host: "github.com", expected: false},
+		{name: "其他站点", url: "https://example.com/user-attachments/a.png", host: "github.com", expected: false},
+		{name: "raw 文件", url: "https://raw.githubusercontent.com/owner/repo/main/a.png", host: "github.com", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result := IsAssetURL(u, tt.host); result != tt.expected {
+				t.Errorf("IsAssetURL(%q, %q) = %v, want %v", tt.url, tt.host, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	var requests atomic.Int32
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		gotAuth = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/user-attachments/assets/a", "/user-attachments/assets/copy":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("same image"))
+		case "/user-attachments/assets/ab":
+			w.Header().Set("Content-Type", "image/gif")
+			w.Write([]byte("other image"))
+		case "/owner/repo/files/1/log.txt":
+			w.Write([]byte("log"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	body := "![a](" + server.URL + "/user-attachments/assets/a)\n" +
+		"<img src=\"" + server.URL + "/user-attachments/assets/a\">\n" +
+		"Copy: " + server.URL + "/user-attachments/assets/copy.\n" +
+		"Other: " + server.URL + "/user-attachments/assets/ab\n" +
+		"[log](" + server.URL + "/owner/repo/files/1/log.txt)\n" +
+		"[issue](" + server.URL + "/owner/repo/issues/1)"
+
+	dir := t.TempDir()
+	d := NewDownloader(WithToken("secret"))
+
+	result, err := d.Localize(context.Background(), body, host, dir)
+	if err != nil {
+		t.Fatalf("Localize() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, DirName))
+	if err != nil {
+		t.Fatalf("failed to read assets directory: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("assets directory has %d files, want 3 (deduplicated by content)", len(entries))
+	}
+
+	var image, other, log string
+	for _, e := range entries {
+		switch filepath.Ext(e.Name()) {
+		case ".png":
+			image = DirName + "/" + e.Name()
+		case ".gif":
+			other = DirName + "/" + e.Name()
+		case ".txt":
+			log = DirName + "/" + e.Name()
+		}
+	}
+	expected := "![a](" + image + ")\n" +
+		"<img src=\"" + image + "\">\n" +
+		"Copy: " + image + ".\n" +
+		"Other: " + other + "\n" +
+		"[log](" + log + ")\n" +
+		"[issue](" + server.URL + "/owner/repo/issues/1)"
+	if result != expected {
+		t.Errorf("Localize() =\n%s\nwant:\n%s", result, expected)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer secret")
+	}
+
+	// 已下载的 URL 不再重复请求
+	before := requests.Load()
+	if _, err := d.Localize(context.Background(), body, host, dir); err != nil {
+		t.Fatalf("Localize() second call error = %v", err)
+	}
+	if after := requests.Load(); after != before {
+		t.Errorf("second Localize() made %d requests, want 0", after-before)
+	}
+}
+
+// roundTripFunc 将函数用作 http.RoundTripper，用于拦截发往任意主机的请求
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// TestLocalizeTokenHost 测试导出 GitHub Enterprise Server 的资源时，Token 不会发送给 github.com
+func TestLocalizeTokenHost(t *testing.T) {
+	auth := make(map[string]string)
+	httpClient := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		auth[r.URL.Host] = r.Header.Get("Authorization")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"image/png"}},
+			Body:       io.NopCloser(strings.NewReader(r.URL.Host)),
+			Request:    r,
+		}, nil
+	})}
+
+	body := "![ghe](https://github.example.com/user-attachments/assets/a)\n" +
+		"![dotcom](https://github.com/user-attachments/assets/b)"
+	d := NewDownloader(WithToken("ghe-secret"), WithHTTPClient(httpClient))
+	if _, err := d.Localize(context.Background(), body, "github.example.com", t.TempDir()); err != nil {
+		t.Fatalf("Localize() error = %v", err)
+	}
+
+	if got := auth["github.example.com"]; got != "Bearer ghe-secret" {
+		t.Errorf("Authorization for github.example.com = %q, want %q", got, "Bearer ghe-secret")
+	}
+	if got, ok := auth["github.com"]; !ok || got != "" {
+		t.Errorf("Authorization for github.com = %q (requested: %v), want no token", got, ok)
+	}
+}
+
+func TestFindURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{name: "图片", body: "![a](https://github.com/user-attachments/assets/a)", expected: []string{"https://github.com/user-attachments/assets/a"}},
+		{
+			name:     "带括号的文件名",
+			body:     "[log](https://github.com/o/r/files/1/screenshot%20(1).png \"title\")",
+			expected: []string{"https://github.com/o/r/files/1/screenshot%20(1).png"},
+		},
+		{name: "尖括号", body: "[a](<https://github.com/o/r/files/1/a (1).txt>)", expected: []string{"https://github.com/o/r/files/1/a (1).txt"}},
+		{
+			name:     "HTML 属性",
+			body:     `<img src="https://github.com/o/r/assets/1/a(2)" width=10><a href='https://example.com/x'>x</a>`,
+			expected: []string{"https://github.com/o/r/assets/1/a(2)", "https://example.com/x"},
+		},
+		{name: "裸链接", body: "See https://github.com/user-attachments/assets/v.", expected: []string{"https://github.com/user-attachments/assets/v"}},
+		{name: "相对链接", body: "[a](docs/a.md) <img src=\"a.png\">", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, loc := range findURLs(tt.body) {
+				result = append(result, tt.body[loc[0]:loc[1]])
+			}
+			if strings.Join(result, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("findURLs(%q) = %q, want %q", tt.body, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLocalizeParentheses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/owner/repo/files/1/screenshot (1).png" {
+			t.Errorf("unexpected request path %q", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("image"))
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	body := "![shot](" + server.URL + "/owner/repo/files/1/screenshot%20(1).png)"
+
+	result, err := NewDownloader().Localize(context.Background(), body, host, t.TempDir())
+	if err != nil {
+		t.Fatalf("Localize() error = %v", err)
+	}
+	if !strings.HasPrefix(result, "![shot]("+DirName+"/") || !strings.HasSuffix(result, ".png)") {
+		t.Errorf("Localize() = %q, want the whole URL rewritten", result)
+	}
+}
+
+func TestLocalizeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user-attachments/assets/ok" {
+			w.Write([]byte("image"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	gone := server.URL + "/user-attachments/assets/gone"
+	body := "![gone](" + gone + ")\n![ok](" + server.URL + "/user-attachments/assets/ok)"
+
+	var warnings bytes.Buffer
+	result, err := NewDownloader(WithWarnings(&warnings)).Localize(context.Background(), body, host, t.TempDir())
+	if err != nil {
+		t.Fatalf("Localize() error = %v, want nil", err)
+	}
+	if !strings.Contains(result, "![gone]("+gone+")") || strings.Contains(result, "/assets/ok)") {
+		t.Errorf("Localize() = %q, want the failed URL kept and the other one rewritten", result)
+	}
+	if !strings.Contains(warnings.String(), "warning: failed to download asset "+gone) {
+		t.Errorf("warnings = %q, want a warning for %s", warnings.String(), gone)
+	}
+}
+
+func TestLocalizeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	body := "![a](https://github.com/user-attachments/assets/a)"
+	_, err := NewDownloader(WithWarnings(io.Discard)).Localize(ctx, body, "github.com", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "failed to download asset") {
+		t.Errorf("Localize() error = %v, want download failure", err)
+	}
+}
+
+func TestExtension(t *testing.T) {
+	tests := []struct {
+		url         string
+		contentType string
+		expected    string
+	}{
+		{url: "https://github.com/owner/repo/files/1/Report.PDF", expected: ".pdf"},
+		{url: "https://github.com/user-attachments/assets/0b6e", contentType: "image/jpeg", expected: ".jpg"},
+		{url: "https://github.com/user-attachments/assets/0b6e", contentType: "image/png; charset=binary", expected: ".png"},
+		{url: "https://github.com/user-attachments/assets/0b6e", expected: ""},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if result := extension(u, tt.contentType); result != tt.expected {
+			t.Errorf("extension(%q, %q) = %q, want %q", tt.url, tt.contentType, result, tt.expected)
+		}
+	}
+}
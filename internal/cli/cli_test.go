@@ -1,7 +1,15 @@
 package cli
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/converter"
+	"github.com/wangyulu/issue2md2/internal/export"
 )
 
 func TestParseArgs(t *testing.T) {
@@ -17,8 +25,8 @@ func TestParseArgs(t *testing.T) {
 			args:        []string{"https://github.com/owner/repo/issues/123"},
 			expectedErr: false,
 			expectedFlag: Flags{
-				EnableReactions:   false,
-				EnableUserLinks:   false,
+				EnableReactions: false,
+				EnableUserLinks: false,
 			},
 			expectedArg: Args{
 				URL:        "https://github.com/owner/repo/issues/123",
@@ -30,8 +38,8 @@ func TestParseArgs(t *testing.T) {
 			args:        []string{"https://github.com/owner/repo/issues/123", "output.md"},
 			expectedErr: false,
 			expectedFlag: Flags{
-				EnableReactions:   false,
-				EnableUserLinks:   false,
+				EnableReactions: false,
+				EnableUserLinks: false,
 			},
 			expectedArg: Args{
 				URL:        "https://github.com/owner/repo/issues/123",
@@ -43,8 +51,8 @@ func TestParseArgs(t *testing.T) {
 			args:        []string{"-enable-reactions", "https://github.com/owner/repo/issues/123"},
 			expectedErr: false,
 			expectedFlag: Flags{
-				EnableReactions:   true,
-				EnableUserLinks:   false,
+				EnableReactions: true,
+				EnableUserLinks: false,
 			},
 			expectedArg: Args{
 				URL:        "https://github.com/owner/repo/issues/123",
@@ -56,21 +64,46 @@ func TestParseArgs(t *testing.T) {
 			args:        []string{"-enable-user-links", "https://github.com/owner/repo/issues/123"},
 			expectedErr: false,
 			expectedFlag: Flags{
-				EnableReactions:   false,
-				EnableUserLinks:   true,
+				EnableReactions: false,
+				EnableUserLinks: true,
 			},
 			expectedArg: Args{
 				URL:        "https://github.com/owner/repo/issues/123",
 				OutputFile: "",
 			},
 		},
+		{
+			name:        "启用时间线",
+			args:        []string{"-enable-timeline", "https://github.com/owner/repo/pull/42"},
+			expectedErr: false,
+			expectedFlag: Flags{
+				EnableTimeline: true,
+			},
+			expectedArg: Args{
+				URL:        "https://github.com/owner/repo/pull/42",
+				OutputFile: "",
+			},
+		},
+		{
+			name:        "启用 diff",
+			args:        []string{"-enable-files", "-enable-diff", "https://github.com/owner/repo/pull/42"},
+			expectedErr: false,
+			expectedFlag: Flags{
+				EnableFiles: true,
+				EnableDiff:  true,
+			},
+			expectedArg: Args{
+				URL:        "https://github.com/owner/repo/pull/42",
+				OutputFile: "",
+			},
+		},
 		{
 			name:        "所有标志",
 			args:        []string{"-enable-reactions", "-enable-user-links", "https://github.com/owner/repo/issues/123", "output.md"},
 			expectedErr: false,
 			expectedFlag: Flags{
-				EnableReactions:   true,
-				EnableUserLinks:   true,
+				EnableReactions: true,
+				EnableUserLinks: true,
 			},
 			expectedArg: Args{
 				URL:        "https://github.com/owner/repo/issues/123",
@@ -111,6 +144,12 @@ func TestParseArgs(t *testing.T) {
 			if flags.EnableUserLinks != tt.expectedFlag.EnableUserLinks {
 				t.Errorf("ParseArgs(%v).EnableUserLinks = %v, want %v", tt.args, flags.EnableUserLinks, tt.expectedFlag.EnableUserLinks)
 			}
+			if flags.EnableTimeline != tt.expectedFlag.EnableTimeline {
+				t.Errorf("ParseArgs(%v).EnableTimeline = %v, want %v", tt.args, flags.EnableTimeline, tt.expectedFlag.EnableTimeline)
+			}
+			if flags.EnableFiles != tt.expectedFlag.EnableFiles || flags.EnableDiff != tt.expectedFlag.EnableDiff {
+				t.Errorf("ParseArgs(%v) EnableFiles, EnableDiff = %v, %v, want %v, %v", tt.args, flags.EnableFiles, flags.EnableDiff, tt.expectedFlag.EnableFiles, tt.expectedFlag.EnableDiff)
+			}
 			if args.URL != tt.expectedArg.URL {
 				t.Errorf("ParseArgs(%v).URL = %q, want %q", tt.args, args.URL, tt.expectedArg.URL)
 			}
@@ -120,3 +159,557 @@ func TestParseArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestParseArgsValueFlags(t *testing.T) {
+	tests := []struct {
+		name                string
+		args                []string
+		expectedErr         bool
+		expectedPageSize    int
+		expectedMaxComments int
+		expectedAPIURL      string
+		expectedTimeout     time.Duration
+		expectedMaxRetries  int
+		expectedRetryWait   time.Duration
+		expectedDiffLines   int
+		expectedURL         string
+	}{
+		{
+			name:                "默认值",
+			args:                []string{"https://github.com/owner/repo/issues/123"},
+			expectedPageSize:    100,
+			expectedMaxComments: 0,
+			expectedMaxRetries:  3,
+			expectedRetryWait:   time.Minute,
+			expectedDiffLines:   500,
+			expectedURL:         "https://github.com/owner/repo/issues/123",
+		},
+		{
+			name:                "空格分隔的值",
+			args:                []string{"-page-size", "50", "-max-comments", "200", "https://github.com/owner/repo/issues/123"},
+			expectedPageSize:    50,
+			expectedMaxComments: 200,
+			expectedURL:         "https://github.com/owner/repo/issues/123",
+		},
+		{
+			name:                "等号分隔的值",
+			args:                []string{"-page-size=20", "-max-comments=10", "https://github.com/owner/repo/issues/123"},
+			expectedPageSize:    20,
+			expectedMaxComments: 10,
+			expectedURL:         "https://github.com/owner/repo/issues/123",
+		},
+		{
+			name:             "API 地址",
+			args:             []string{"-api-url", "http://127.0.0.1:8080/graphql", "https://github.com/owner/repo/issues/123"},
+			expectedPageSize: 100,
+			expectedAPIURL:   "http://127.0.0.1:8080/graphql",
+			expectedURL:      "https://github.com/owner/repo/issues/123",
+		},
+		{
+			name:             "请求超时",
+			args:             []string{"-timeout", "30s", "https://github.com/owner/repo/issues/123"},
+			expectedPageSize: 100,
+			expectedTimeout:  30 * time.Second,
+			expectedURL:      "https://github.com/owner/repo/issues/123",
+		},
+		{
+			name:        "无效的超时",
+			args:        []string{"-timeout=abc", "https://github.com/owner/repo/issues/123"},
+			expectedErr: true,
+		},
+		{
+			name:               "重试设置",
+			args:               []string{"-max-retries", "0", "-max-retry-wait=5m", "https://github.com/owner/repo/issues/123"},
+			expectedPageSize:   100,
+			expectedMaxRetries: 0,
+			expectedRetryWait:  5 * time.Minute,
+			expectedURL:        "https://github.com/owner/repo/issues/123",
+		},
+		{
+			name:              "diff 行数",
+			args:              []string{"-max-diff-lines=200", "https://github.com/owner/repo/pull/42"},
+			expectedPageSize:  100,
+			expectedDiffLines: 200,
+			expectedURL:       "https://github.com/owner/repo/pull/42",
+		},
+		{
+			name:        "max-diff-lines 为负数",
+			args:        []string{"-max-diff-lines", "-5", "https://github.com/owner/repo/pull/42"},
+			expectedErr: true,
+		},
+		{
+			name:        "page-size 超出范围",
+			args:        []string{"-page-size", "101", "https://github.com/owner/repo/issues/123"},
+			expectedErr: true,
+		},
+		{
+			name:        "max-comments 为负数",
+			args:        []string{"-max-comments=-1", "https://github.com/owner/repo/issues/123"},
+			expectedErr: true,
+		},
+		{
+			name:        "缺少标志值",
+			args:        []string{"https://github.com/owner/repo/issues/123", "-max-comments"},
+			expectedErr: true,
+		},
+		{
+			name:        "非数字值",
+			args:        []string{"-page-size", "abc", "https://github.com/owner/repo/issues/123"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, args, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+
+			if flags.PageSize != tt.expectedPageSize {
+				t.Errorf("ParseArgs(%v).PageSize = %d, want %d", tt.args, flags.PageSize, tt.expectedPageSize)
+			}
+			if flags.MaxComments != tt.expectedMaxComments {
+				t.Errorf("ParseArgs(%v).MaxComments = %d, want %d", tt.args, flags.MaxComments, tt.expectedMaxComments)
+			}
+			if tt.expectedRetryWait != 0 {
+				if flags.MaxRetries != tt.expectedMaxRetries {
+					t.Errorf("ParseArgs(%v).MaxRetries = %d, want %d", tt.args, flags.MaxRetries, tt.expectedMaxRetries)
+				}
+				if flags.MaxRetryWait != tt.expectedRetryWait {
+					t.Errorf("ParseArgs(%v).MaxRetryWait = %v, want %v", tt.args, flags.MaxRetryWait, tt.expectedRetryWait)
+				}
+			}
+			if tt.expectedDiffLines != 0 && flags.MaxDiffLines != tt.expectedDiffLines {
+				t.Errorf("ParseArgs(%v).MaxDiffLines = %d, want %d", tt.args, flags.MaxDiffLines, tt.expectedDiffLines)
+			}
+			if flags.Timeout != tt.expectedTimeout {
+				t.Errorf("ParseArgs(%v).Timeout = %v, want %v", tt.args, flags.Timeout, tt.expectedTimeout)
+			}
+			if flags.APIURL != tt.expectedAPIURL {
+				t.Errorf("ParseArgs(%v).APIURL = %q, want %q", tt.args, flags.APIURL, tt.expectedAPIURL)
+			}
+			if args.URL != tt.expectedURL {
+				t.Errorf("ParseArgs(%v).URL = %q, want %q", tt.args, args.URL, tt.expectedURL)
+			}
+		})
+	}
+}
+
+func TestParseArgsFilterFlags(t *testing.T) {
+	args := []string{
+		"-state", "open,closed",
+		"-label=bug, help wanted",
+		"-author", "@octocat",
+		"-created-after", "2024-01-01",
+		"-updated-before", "2024-06-01T12:00:00Z",
+		"https://github.com/owner/repo/issues", "archive",
+	}
+
+	flags, cliArgs, err := ParseArgs(args)
+	if err != nil {
+		t.Fatalf("ParseArgs(%v) unexpected error: %v", args, err)
+	}
+
+	if len(flags.States) != 2 || flags.States[0] != "open" || flags.States[1] != "closed" {
+		t.Errorf("ParseArgs().States = %v, want [open closed]", flags.States)
+	}
+	if len(flags.Labels) != 2 || flags.Labels[0] != "bug" || flags.Labels[1] != "help wanted" {
+		t.Errorf("ParseArgs().Labels = %v, want [bug help wanted]", flags.Labels)
+	}
+	if flags.Author != "octocat" {
+		t.Errorf("ParseArgs().Author = %q, want %q", flags.Author, "octocat")
+	}
+	if !flags.CreatedAfter.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseArgs().CreatedAfter = %v, want 2024-01-01", flags.CreatedAfter)
+	}
+	if !flags.UpdatedBefore.Equal(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseArgs().UpdatedBefore = %v, want 2024-06-01T12:00:00Z", flags.UpdatedBefore)
+	}
+	if cliArgs.OutputFile != "archive" {
+		t.Errorf("ParseArgs().OutputFile = %q, want %q", cliArgs.OutputFile, "archive")
+	}
+
+	invalid := [][]string{
+		{"-state", "draft", "https://github.com/owner/repo"},
+		{"-created-after", "yesterday", "https://github.com/owner/repo"},
+	}
+	for _, args := range invalid {
+		if _, _, err := ParseArgs(args); err == nil {
+			t.Errorf("ParseArgs(%v) expected error, got nil", args)
+		}
+	}
+}
+
+func TestParseArgsBatch(t *testing.T) {
+	tests := []struct {
+		name                string
+		args                []string
+		expectedErr         bool
+		expectedBatch       string
+		expectedDir         string
+		expectedPattern     string
+		expectedConcurrency int
+	}{
+		{
+			name:                "从文件读取",
+			args:                []string{"-batch", "urls.txt", "archive"},
+			expectedBatch:       "urls.txt",
+			expectedDir:         "archive",
+			expectedConcurrency: DefaultConcurrency,
+		},
+		{
+			name:                "从 stdin 读取并指定文件名模板",
+			args:                []string{"-batch", "-", "-name-pattern", "{repo}/{number}.md", "archive"},
+			expectedBatch:       "-",
+			expectedDir:         "archive",
+			expectedPattern:     "{repo}/{number}.md",
+			expectedConcurrency: DefaultConcurrency,
+		},
+		{
+			name:                "指定并发数",
+			args:                []string{"-concurrency", "8", "-batch", "urls.txt", "archive"},
+			expectedBatch:       "urls.txt",
+			expectedDir:         "archive",
+			expectedConcurrency: 8,
+		},
+		{
+			name:        "并发数必须为正数",
+			args:        []string{"-concurrency", "0", "-batch", "urls.txt", "archive"},
+			expectedErr: true,
+		},
+		{
+			name:        "缺少输出目录",
+			args:        []string{"-batch", "urls.txt"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, args, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if flags.BatchFile != tt.expectedBatch {
+				t.Errorf("ParseArgs(%v).BatchFile = %q, want %q", tt.args, flags.BatchFile, tt.expectedBatch)
+			}
+			if flags.NamePattern != tt.expectedPattern {
+				t.Errorf("ParseArgs(%v).NamePattern = %q, want %q", tt.args, flags.NamePattern, tt.expectedPattern)
+			}
+			if flags.Concurrency != tt.expectedConcurrency {
+				t.Errorf("ParseArgs(%v).Concurrency = %d, want %d", tt.args, flags.Concurrency, tt.expectedConcurrency)
+			}
+			if args.URL != "" {
+				t.Errorf("ParseArgs(%v).URL = %q, want empty", tt.args, args.URL)
+			}
+			if args.OutputFile != tt.expectedDir {
+				t.Errorf("ParseArgs(%v).OutputFile = %q, want %q", tt.args, args.OutputFile, tt.expectedDir)
+			}
+		})
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	results := []export.Result{
+		{URL: "https://github.com/owner/repo/issues/1", Path: "archive/issue-1.md"},
+		{URL: "https://github.com/owner/repo/issues/2", Err: errors.New("resource not found")},
+		{URL: "https://github.com/owner/repo/pull/3", Path: "archive/pull-3.md"},
+	}
+
+	var buf bytes.Buffer
+	failed := PrintSummary(&buf, results)
+
+	if failed != 1 {
+		t.Errorf("PrintSummary() = %d, want 1", failed)
+	}
+
+	output := buf.String()
+	for _, expected := range []string{
+		"STATUS",
+		"ok      https://github.com/owner/repo/issues/1  archive/issue-1.md",
+		"failed  https://github.com/owner/repo/issues/2  resource not found",
+		"2 succeeded, 1 failed",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("PrintSummary() output missing %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestParseArgsFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedFormat string
+		expectedErr    bool
+	}{
+		{name: "默认为 markdown", args: []string{"https://github.com/owner/repo/issues/1"}, expectedFormat: "markdown"},
+		{name: "html", args: []string{"-format", "html", "https://github.com/owner/repo/issues/1"}, expectedFormat: "html"},
+		{name: "json", args: []string{"-format", "json", "https://github.com/owner/repo/issues/1"}, expectedFormat: "json"},
+		{name: "等号形式", args: []string{"-format=markdown", "https://github.com/owner/repo/issues/1"}, expectedFormat: "markdown"},
+		{name: "不支持的格式", args: []string{"-format", "pdf", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if flags.Format != tt.expectedFormat {
+				t.Errorf("ParseArgs(%v).Format = %q, want %q", tt.args, flags.Format, tt.expectedFormat)
+			}
+		})
+	}
+}
+
+func TestParseArgsTemplate(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		expectedTemplate string
+		expectedErr      bool
+	}{
+		{name: "默认不使用模板", args: []string{"https://github.com/owner/repo/issues/1"}},
+		{name: "指定模板", args: []string{"-template", "issue.tmpl", "https://github.com/owner/repo/issues/1"}, expectedTemplate: "issue.tmpl"},
+		{name: "显式 markdown 格式", args: []string{"-format", "markdown", "-template=issue.tmpl", "https://github.com/owner/repo/issues/1"}, expectedTemplate: "issue.tmpl"},
+		{name: "不能与 html 同时使用", args: []string{"-format", "html", "-template", "issue.tmpl", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+		{name: "缺少路径", args: []string{"https://github.com/owner/repo/issues/1", "-template"}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if flags.Template != tt.expectedTemplate {
+				t.Errorf("ParseArgs(%v).Template = %q, want %q", tt.args, flags.Template, tt.expectedTemplate)
+			}
+		})
+	}
+}
+
+func TestParseArgsFrontmatter(t *testing.T) {
+	tests := []struct {
+		name                string
+		args                []string
+		expectedFrontmatter string
+		expectedFields      []converter.FrontmatterField
+		expectedErr         bool
+	}{
+		{name: "默认 YAML", args: []string{"https://github.com/owner/repo/issues/1"}, expectedFrontmatter: "yaml"},
+		{name: "TOML", args: []string{"-frontmatter", "toml", "https://github.com/owner/repo/issues/1"}, expectedFrontmatter: "toml"},
+		{name: "不输出", args: []string{"-frontmatter=none", "https://github.com/owner/repo/issues/1"}, expectedFrontmatter: "none"},
+		{
+			name:                "字段选择和重命名",
+			args:                []string{"-frontmatter-fields", "title,labels:tags", "https://github.com/owner/repo/issues/1"},
+			expectedFrontmatter: "yaml",
+			expectedFields:      []converter.FrontmatterField{{Name: "title", Key: "title"}, {Name: "labels", Key: "tags"}},
+		},
+		{name: "无效格式", args: []string{"-frontmatter", "xml", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+		{name: "未知字段", args: []string{"-frontmatter-fields", "title,nope", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+		{name: "不能与 json 同时使用", args: []string{"-format", "json", "-frontmatter", "toml", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+		{name: "字段不能与 html 同时使用", args: []string{"-format", "html", "-frontmatter-fields", "title", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if flags.Frontmatter != tt.expectedFrontmatter {
+				t.Errorf("ParseArgs(%v).Frontmatter = %q, want %q", tt.args, flags.Frontmatter, tt.expectedFrontmatter)
+			}
+			if fmt.Sprint(flags.FrontmatterFields) != fmt.Sprint(tt.expectedFields) {
+				t.Errorf("ParseArgs(%v).FrontmatterFields = %v, want %v", tt.args, flags.FrontmatterFields, tt.expectedFields)
+			}
+		})
+	}
+}
+
+func TestParseArgsDownloadAssets(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expected    bool
+		expectedErr bool
+	}{
+		{name: "默认不下载", args: []string{"https://github.com/owner/repo/issues/1"}},
+		{name: "单个资源写入文件", args: []string{"-download-assets", "https://github.com/owner/repo/issues/1", "issue.md"}, expected: true},
+		{name: "批量导出", args: []string{"-download-assets", "-batch", "urls.txt", "./archive"}, expected: true},
+		{name: "导出仓库", args: []string{"-download-assets", "https://github.com/owner/repo/issues", "./archive"}, expected: true},
+		{name: "输出到 stdout", args: []string{"-download-assets", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if flags.DownloadAssets != tt.expected {
+				t.Errorf("ParseArgs(%v).DownloadAssets = %v, want %v", tt.args, flags.DownloadAssets, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseArgsAnchor(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedAnchor  string
+		expectedContext int
+		expectedErr     bool
+	}{
+		{name: "默认导出整个讨论", args: []string{"https://github.com/owner/repo/issues/1#issuecomment-2"}, expectedAnchor: "thread"},
+		{name: "突出显示", args: []string{"-anchor", "highlight", "https://github.com/owner/repo/issues/1#issuecomment-2"}, expectedAnchor: "highlight"},
+		{name: "只导出评论", args: []string{"-anchor=comment", "https://github.com/owner/repo/issues/1#issuecomment-2"}, expectedAnchor: "comment"},
+		{name: "带上下文", args: []string{"-anchor", "comment", "-anchor-context", "3", "https://github.com/owner/repo/issues/1#issuecomment-2"}, expectedAnchor: "comment", expectedContext: 3},
+		{name: "无效模式", args: []string{"-anchor", "only", "https://github.com/owner/repo/issues/1#issuecomment-2"}, expectedErr: true},
+		{name: "负数上下文", args: []string{"-anchor", "comment", "-anchor-context", "-1", "https://github.com/owner/repo/issues/1#issuecomment-2"}, expectedErr: true},
+		{name: "上下文需要 comment 模式", args: []string{"-anchor-context", "2", "https://github.com/owner/repo/issues/1#issuecomment-2"}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if flags.Anchor != tt.expectedAnchor {
+				t.Errorf("ParseArgs(%v).Anchor = %q, want %q", tt.args, flags.Anchor, tt.expectedAnchor)
+			}
+			if flags.AnchorContext != tt.expectedContext {
+				t.Errorf("ParseArgs(%v).AnchorContext = %d, want %d", tt.args, flags.AnchorContext, tt.expectedContext)
+			}
+		})
+	}
+}
+
+func TestParseArgsMinimized(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expected    string
+		expectedErr bool
+	}{
+		{name: "默认折叠", args: []string{"https://github.com/owner/repo/issues/1"}, expected: "collapse"},
+		{name: "不导出", args: []string{"-minimized", "omit", "https://github.com/owner/repo/issues/1"}, expected: "omit"},
+		{name: "等号形式", args: []string{"-minimized=include", "https://github.com/owner/repo/issues/1"}, expected: "include"},
+		{name: "无效模式", args: []string{"-minimized", "hide", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if flags.Minimized != tt.expected {
+				t.Errorf("ParseArgs(%v).Minimized = %q, want %q", tt.args, flags.Minimized, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseArgsEditHistory(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expected    string
+		expectedErr bool
+	}{
+		{name: "默认不展示", args: []string{"https://github.com/owner/repo/issues/1"}, expected: "none"},
+		{name: "最后一次修订", args: []string{"-edit-history", "last", "https://github.com/owner/repo/issues/1"}, expected: "last"},
+		{name: "等号形式", args: []string{"-edit-history=full", "https://github.com/owner/repo/issues/1"}, expected: "full"},
+		{name: "无效模式", args: []string{"-edit-history", "all", "https://github.com/owner/repo/issues/1"}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, err := ParseArgs(tt.args)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("ParseArgs(%v) expected error, got nil", tt.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseArgs(%v) unexpected error: %v", tt.args, err)
+			}
+			if flags.EditHistory != tt.expected {
+				t.Errorf("ParseArgs(%v).EditHistory = %q, want %q", tt.args, flags.EditHistory, tt.expected)
+			}
+		})
+	}
+}
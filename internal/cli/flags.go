@@ -3,26 +3,98 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/converter"
 )
 
 // 错误常量
 const (
 	ErrMissingRequiredArg = "missing required argument: %s"
-	ErrUnknownFlag       = "unknown flag: %s"
-	ErrHelpDisplayed     = "help displayed"
+	ErrUnknownFlag        = "unknown flag: %s"
+	ErrHelpDisplayed      = "help displayed"
+	ErrMissingFlagValue   = "flag needs an argument: %s"
+	ErrInvalidFlagValue   = "invalid value %q for flag %s"
+	ErrConflictingFlags   = "flags %s and %s cannot be used together"
+)
+
+// DefaultFormat 默认输出格式
+const DefaultFormat = "markdown"
+
+// DefaultFrontmatter 默认 Frontmatter 格式
+const DefaultFrontmatter = "yaml"
+
+// DefaultAnchor URL 指向某条评论时默认的导出方式
+const DefaultAnchor = "thread"
+
+// DefaultMinimized 被隐藏的评论默认的导出方式
+const DefaultMinimized = "collapse"
+
+// DefaultEditHistory 正文和评论修订记录默认的展示方式
+const DefaultEditHistory = "none"
+
+// DefaultPageSize 分页获取评论时每页的默认数量
+const DefaultPageSize = 100
+
+// DefaultConcurrency 导出多个资源时默认的并发数
+const DefaultConcurrency = 4
+
+// 限流重试的默认值
+const (
+	DefaultMaxRetries   = 3
+	DefaultMaxRetryWait = time.Minute
 )
 
 // Flags 命令行标志
 type Flags struct {
+	Format          string // 输出格式：markdown、html 或 json
+	Template        string // 自定义 Markdown 模板文件路径，为空时使用默认模板
 	EnableReactions bool
 	EnableUserLinks bool
+	EnableTimeline  bool          // 在评论之间穿插标签变更、关闭、引用等时间线事件
+	DownloadAssets  bool          // 下载正文中的图片和附件到输出文件旁的 assets 目录
+	EnableFiles     bool          // 导出 Pull Request 的变更文件列表
+	EnableDiff      bool          // 导出 Pull Request 的变更文件列表和每个文件的 diff
+	MaxDiffLines    int           // 每个文件的 diff 最多显示的行数，0 表示不限制
+	PageSize        int           // 每页获取的评论数，1-100
+	MaxComments     int           // 最多获取的评论数，0 表示不限制
+	APIURL          string        // GraphQL API 地址，为空时根据 URL 主机名推断
+	Timeout         time.Duration // 单次 API 请求超时（包含重试等待），0 表示不限制
+	MaxRetries      int           // 限流或临时错误时的最大重试次数，0 表示不重试
+	MaxRetryWait    time.Duration // 单次重试最长等待时间，超过时直接报错
+
+	// Markdown Frontmatter
+	Frontmatter       string                       // yaml、toml、json 或 none
+	FrontmatterFields []converter.FrontmatterField // 字段及键名，为空时使用默认字段
+
+	// URL 指向某条评论（#issuecomment-N、#discussion_rN、#discussioncomment-N）时
+	Anchor        string // thread、comment 或 highlight
+	AnchorContext int    // -anchor comment 时锚点评论前后各保留的评论数
+
+	Minimized   string // 被隐藏（spam、off-topic 等）的评论：collapse、omit 或 include
+	EditHistory string // 正文和评论的修订记录：none、last 或 full
+
+	// 批量导出
+	BatchFile   string // URL 列表文件，"-" 表示 stdin；为空表示非批量模式
+	NamePattern string // 输出文件名模板，为空时使用默认模板
+	Concurrency int    // 导出多个资源时的并发数
+
+	// 导出仓库时的筛选条件
+	States        []string  // open, closed, merged
+	Labels        []string  // 必须包含的全部标签
+	Author        string    // 作者登录名
+	CreatedAfter  time.Time // 创建时间不早于
+	CreatedBefore time.Time // 创建时间早于
+	UpdatedAfter  time.Time // 更新时间不早于
+	UpdatedBefore time.Time // 更新时间早于
 }
 
 // Args 命令行参数
 type Args struct {
-	URL        string // 必需
-	OutputFile string // 可选，为空表示输出到 stdout
+	URL        string // 必需（批量模式下为空）
+	OutputFile string // 可选，为空表示输出到 stdout；导出仓库和批量模式下为输出目录
 }
 
 // ParseArgs 解析命令行参数
@@ -30,41 +102,261 @@ type Args struct {
 // 返回 Flags 和 Args，如果解析失败返回错误
 //
 // 支持的标志:
-//   -enable-reactions: 启用 Reactions 显示
-//   -enable-user-links: 启用用户链接
+//
+//	-format F: 输出格式，markdown、html 或 json（默认 markdown）
+//	-template PATH: 使用 text/template 模板文件渲染 Markdown（不能与 -format html/json 同时使用）
+//	-frontmatter F: Frontmatter 格式，yaml、toml、json 或 none（默认 yaml）
+//	-frontmatter-fields LIST: Frontmatter 字段，逗号分隔，name:key 可重命名，如 title,labels:tags,aliases
+//	-enable-reactions: 启用 Reactions 显示
+//	-enable-user-links: 启用用户链接
+//	-enable-timeline: 按时间顺序在评论之间穿插时间线事件（仅 Issue 和 Pull Request）
+//	-enable-files: 导出 Pull Request 的变更文件列表（路径、增删行数、变更类型）
+//	-enable-diff: 导出变更文件列表及每个文件的 diff
+//	-max-diff-lines N: 每个文件的 diff 最多显示的行数（默认 500，0 表示不限制）
+//	-download-assets: 下载图片和附件到输出文件所在目录的 assets 子目录，并改写为相对链接
+//	-anchor MODE: URL 指向某条评论时的导出方式，thread、comment 或 highlight（默认 thread）
+//	-anchor-context N: -anchor comment 时锚点评论前后各保留的评论数（默认 0）
+//	-minimized MODE: 被隐藏的评论的导出方式，collapse、omit 或 include（默认 collapse）
+//	-edit-history MODE: 正文和评论修订记录的展示方式，none、last 或 full（默认 none）
+//	-page-size N: 每页获取的评论数（1-100，默认 100）
+//	-max-comments N: 最多获取的评论数（默认 0，不限制）
+//	-api-url URL: GraphQL API 地址（默认根据 URL 主机名推断）
+//	-timeout D: 单次 API 请求超时，如 30s（默认 0，不限制）
+//	-max-retries N: 限流或临时错误时的最大重试次数（默认 3）
+//	-max-retry-wait D: 单次重试最长等待时间（默认 1m）
+//	-batch FILE: 从文件（"-" 表示 stdin）读取 URL 列表批量导出
+//	-name-pattern P: 导出多个资源时的文件名模板，如 {owner}-{repo}-{type}-{number}.md
+//	-concurrency N: 导出多个资源时的并发数（默认 4）
+//	-state S: 导出仓库时按状态筛选，逗号分隔（open, closed, merged）
+//	-label L: 导出仓库时按标签筛选，逗号分隔，需包含全部标签
+//	-author A: 导出仓库时按作者筛选
+//	-created-after T / -created-before T: 按创建时间筛选（YYYY-MM-DD 或 RFC 3339）
+//	-updated-after T / -updated-before T: 按更新时间筛选（YYYY-MM-DD 或 RFC 3339）
 //
 // 位置参数:
-//   url: 必需，GitHub URL
-//   output_file: 可选，输出文件路径
+//
+//	url: 必需，GitHub URL 或简写引用 owner/repo#N、owner/repo!N、#N（批量模式下省略）
+//	output_file: 可选，输出文件路径；批量模式下为必需的输出目录
 func ParseArgs(args []string) (*Flags, *Args, error) {
 	if len(args) == 0 {
 		return nil, nil, fmt.Errorf(ErrMissingRequiredArg, "url")
 	}
 
 	flags := &Flags{
+		Format:          DefaultFormat,
+		Frontmatter:     DefaultFrontmatter,
+		Anchor:          DefaultAnchor,
+		Minimized:       DefaultMinimized,
+		EditHistory:     DefaultEditHistory,
 		EnableReactions: false,
 		EnableUserLinks: false,
+		PageSize:        DefaultPageSize,
+		MaxComments:     0,
+		MaxDiffLines:    converter.DefaultMaxDiffLines,
+		MaxRetries:      DefaultMaxRetries,
+		MaxRetryWait:    DefaultMaxRetryWait,
+		Concurrency:     DefaultConcurrency,
 	}
 
 	// 解析标志
 	remainingArgs := []string{}
-	for _, arg := range args {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		switch arg {
 		case "-enable-reactions":
 			flags.EnableReactions = true
 		case "-enable-user-links":
 			flags.EnableUserLinks = true
+		case "-enable-timeline":
+			flags.EnableTimeline = true
+		case "-enable-files":
+			flags.EnableFiles = true
+		case "-enable-diff":
+			flags.EnableDiff = true
+		case "-download-assets":
+			flags.DownloadAssets = true
 		case "-h":
 			PrintHelp(os.Stdout)
 			return nil, nil, fmt.Errorf(ErrHelpDisplayed)
 		default:
-			if strings.HasPrefix(arg, "-") {
+			if !strings.HasPrefix(arg, "-") {
+				remainingArgs = append(remainingArgs, arg)
+				continue
+			}
+
+			name, value, err := flagValue(args, &i)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch name {
+			case "-format":
+				switch value {
+				case "markdown", "html", "json":
+				default:
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.Format = value
+			case "-template":
+				if value == "" {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.Template = value
+			case "-frontmatter":
+				switch value {
+				case "yaml", "toml", "json", "none":
+				default:
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.Frontmatter = value
+			case "-frontmatter-fields":
+				fields, err := converter.ParseFrontmatterFields(value)
+				if err != nil {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue+": %w", value, name, err)
+				}
+				flags.FrontmatterFields = fields
+			case "-anchor":
+				switch value {
+				case "thread", "comment", "highlight":
+				default:
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.Anchor = value
+			case "-anchor-context":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.AnchorContext = n
+			case "-minimized":
+				switch value {
+				case "collapse", "omit", "include":
+				default:
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.Minimized = value
+			case "-edit-history":
+				switch value {
+				case "none", "last", "full":
+				default:
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.EditHistory = value
+			case "-page-size":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 1 || n > 100 {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.PageSize = n
+			case "-max-comments":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.MaxComments = n
+			case "-max-diff-lines":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.MaxDiffLines = n
+			case "-api-url":
+				if value == "" {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.APIURL = value
+			case "-timeout":
+				d, err := time.ParseDuration(value)
+				if err != nil || d < 0 {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.Timeout = d
+			case "-max-retries":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.MaxRetries = n
+			case "-max-retry-wait":
+				d, err := time.ParseDuration(value)
+				if err != nil || d < 0 {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.MaxRetryWait = d
+			case "-batch":
+				if value == "" {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.BatchFile = value
+			case "-name-pattern":
+				if value == "" {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.NamePattern = value
+			case "-concurrency":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 1 {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				flags.Concurrency = n
+			case "-state":
+				for _, state := range splitList(value) {
+					switch state {
+					case "open", "closed", "merged":
+					default:
+						return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+					}
+				}
+				flags.States = splitList(value)
+			case "-label":
+				flags.Labels = splitList(value)
+			case "-author":
+				flags.Author = strings.TrimPrefix(value, "@")
+			case "-created-after", "-created-before", "-updated-after", "-updated-before":
+				t, err := parseTime(value)
+				if err != nil {
+					return nil, nil, fmt.Errorf(ErrInvalidFlagValue, value, name)
+				}
+				switch name {
+				case "-created-after":
+					flags.CreatedAfter = t
+				case "-created-before":
+					flags.CreatedBefore = t
+				case "-updated-after":
+					flags.UpdatedAfter = t
+				case "-updated-before":
+					flags.UpdatedBefore = t
+				}
+			default:
 				return nil, nil, fmt.Errorf(ErrUnknownFlag, arg)
 			}
-			remainingArgs = append(remainingArgs, arg)
 		}
 	}
 
+	// 模板和 Frontmatter 只用于 Markdown 输出
+	if flags.Format != "markdown" {
+		switch {
+		case flags.Template != "":
+			return nil, nil, fmt.Errorf(ErrConflictingFlags, "-template", "-format "+flags.Format)
+		case flags.Frontmatter != DefaultFrontmatter:
+			return nil, nil, fmt.Errorf(ErrConflictingFlags, "-frontmatter", "-format "+flags.Format)
+		case flags.FrontmatterFields != nil:
+			return nil, nil, fmt.Errorf(ErrConflictingFlags, "-frontmatter-fields", "-format "+flags.Format)
+		}
+	}
+
+	// 上下文评论只用于只导出锚点评论的模式
+	if flags.AnchorContext > 0 && flags.Anchor != "comment" {
+		return nil, nil, fmt.Errorf(ErrConflictingFlags, "-anchor-context", "-anchor "+flags.Anchor)
+	}
+
+	// 批量模式：唯一的位置参数为输出目录
+	if flags.BatchFile != "" {
+		if len(remainingArgs) == 0 {
+			return nil, nil, fmt.Errorf(ErrMissingRequiredArg, "output_dir")
+		}
+		return flags, &Args{OutputFile: remainingArgs[0]}, nil
+	}
+
 	// 验证必需参数
 	if len(remainingArgs) == 0 {
 		return nil, nil, fmt.Errorf(ErrMissingRequiredArg, "url")
@@ -78,5 +370,57 @@ func ParseArgs(args []string) (*Flags, *Args, error) {
 		cliArgs.OutputFile = remainingArgs[1]
 	}
 
+	// 资源文件保存在输出文件旁，输出到 stdout 时无处存放
+	if flags.DownloadAssets && cliArgs.OutputFile == "" {
+		return nil, nil, fmt.Errorf(ErrMissingRequiredArg, "output_file")
+	}
+
 	return flags, cliArgs, nil
 }
+
+// isValueFlag 判断标志是否需要参数值
+func isValueFlag(name string) bool {
+	switch name {
+	case "-format", "-template", "-frontmatter", "-frontmatter-fields", "-anchor", "-anchor-context", "-minimized", "-edit-history", "-page-size", "-max-comments", "-max-diff-lines", "-api-url", "-timeout", "-max-retries", "-max-retry-wait",
+		"-batch", "-name-pattern", "-concurrency",
+		"-state", "-label", "-author", "-created-after", "-created-before", "-updated-after", "-updated-before":
+		return true
+	}
+	return false
+}
+
+// flagValue 读取带值标志的名称和值，支持 "-flag value" 和 "-flag=value" 两种形式
+// i 指向当前参数，使用 "-flag value" 形式时会前移以消费值参数
+func flagValue(args []string, i *int) (string, string, error) {
+	name, value, hasValue := strings.Cut(args[*i], "=")
+	if !isValueFlag(name) {
+		return name, "", fmt.Errorf(ErrUnknownFlag, args[*i])
+	}
+	if hasValue {
+		return name, value, nil
+	}
+	if *i+1 >= len(args) {
+		return name, "", fmt.Errorf(ErrMissingFlagValue, name)
+	}
+	*i++
+	return name, args[*i], nil
+}
+
+// splitList 按逗号拆分列表值，去除空白和空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTime 解析 YYYY-MM-DD（UTC 零点）或 RFC 3339 格式的时间
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
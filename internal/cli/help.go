@@ -8,21 +8,98 @@ import (
 // PrintHelp 打印使用帮助信息到指定的 io.Writer
 func PrintHelp(w io.Writer) {
 	fmt.Fprintln(w, "Usage: issue2md [flags] <url> [output_file]")
+	fmt.Fprintln(w, "       issue2md [flags] <repository_url> <output_dir>")
+	fmt.Fprintln(w, "       issue2md [flags] -batch <file|-> <output_dir>")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "References:")
+	fmt.Fprintln(w, "  <url> may also be owner/repo#N, owner/repo!N (pull request), or #N and !N for the")
+	fmt.Fprintln(w, "  origin remote of the git repository in the current directory")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Flags:")
+	fmt.Fprintln(w, "  -format F")
+	fmt.Fprintln(w, "        Output format: markdown, html or json (default: markdown)")
+	fmt.Fprintln(w, "  -template PATH")
+	fmt.Fprintln(w, "        Render Markdown with a Go text/template file instead of the built-in layout")
+	fmt.Fprintln(w, "  -frontmatter F")
+	fmt.Fprintln(w, "        Frontmatter format: yaml, toml, json or none (default: yaml)")
+	fmt.Fprintln(w, "  -frontmatter-fields LIST")
+	fmt.Fprintln(w, "        Comma-separated frontmatter fields; name:key renames a field, e.g. title,labels:tags,aliases")
 	fmt.Fprintln(w, "  -enable-reactions")
 	fmt.Fprintln(w, "        Enable reactions display (default: false)")
 	fmt.Fprintln(w, "  -enable-user-links")
 	fmt.Fprintln(w, "        Render usernames as links to GitHub profiles (default: false)")
+	fmt.Fprintln(w, "  -enable-timeline")
+	fmt.Fprintln(w, "        Interleave timeline events (labels, assignments, closes, references, renames) with comments (default: false)")
+	fmt.Fprintln(w, "  -enable-files")
+	fmt.Fprintln(w, "        Export the pull request's changed files with additions, deletions and change type (default: false)")
+	fmt.Fprintln(w, "  -enable-diff")
+	fmt.Fprintln(w, "        Export the changed files and the unified diff of each file (default: false)")
+	fmt.Fprintln(w, "  -max-diff-lines N")
+	fmt.Fprintln(w, "        Maximum diff lines shown per file, 0 means unlimited (default: 500)")
+	fmt.Fprintln(w, "  -download-assets")
+	fmt.Fprintln(w, "        Save embedded images and attachments to an assets directory next to the output (default: false)")
+	fmt.Fprintln(w, "  -anchor MODE")
+	fmt.Fprintln(w, "        For URLs pointing at a comment (#issuecomment-N, #discussion_rN, #discussioncomment-N):")
+	fmt.Fprintln(w, "        thread exports the whole thread, comment exports only that comment, highlight marks it (default: thread)")
+	fmt.Fprintln(w, "  -anchor-context N")
+	fmt.Fprintln(w, "        With -anchor comment, also export N comments before and after it (default: 0)")
+	fmt.Fprintln(w, "  -minimized MODE")
+	fmt.Fprintln(w, "        Comments hidden as spam, off-topic, outdated, resolved, etc.:")
+	fmt.Fprintln(w, "        collapse folds them into <details> labeled with the reason, omit skips them, include keeps them as is (default: collapse)")
+	fmt.Fprintln(w, "  -edit-history MODE")
+	fmt.Fprintln(w, "        Edit history of the body and comments: none, last shows who last edited them and when,")
+	fmt.Fprintln(w, "        full also shows every revision as a collapsible diff against the previous one (default: none)")
+	fmt.Fprintln(w, "  -page-size N")
+	fmt.Fprintln(w, "        Number of comments fetched per API request, 1-100 (default: 100)")
+	fmt.Fprintln(w, "  -max-comments N")
+	fmt.Fprintln(w, "        Maximum number of comments to export, 0 means unlimited (default: 0)")
+	fmt.Fprintln(w, "  -api-url URL")
+	fmt.Fprintln(w, "        GraphQL API endpoint (default: inferred from the URL host)")
+	fmt.Fprintln(w, "  -timeout D")
+	fmt.Fprintln(w, "        Timeout for each API request, e.g. 30s (default: 0, no timeout)")
+	fmt.Fprintln(w, "  -max-retries N")
+	fmt.Fprintln(w, "        Retries on rate limits and 502/503/504 responses, 0 disables (default: 3)")
+	fmt.Fprintln(w, "  -max-retry-wait D")
+	fmt.Fprintln(w, "        Longest wait before a single retry; longer waits fail immediately (default: 1m)")
+	fmt.Fprintln(w, "  -batch FILE")
+	fmt.Fprintln(w, "        Read URLs from FILE, one per line, or from stdin when FILE is \"-\"")
+	fmt.Fprintln(w, "        Lines starting with \"#\" are comments, except #N references such as #12")
+	fmt.Fprintln(w, "  -name-pattern P")
+	fmt.Fprintln(w, "        File name pattern for batch and repository exports, using {host}, {owner}, {repo}, {type}, {number} and {ext}")
+	fmt.Fprintln(w, "  -concurrency N")
+	fmt.Fprintln(w, "        Number of items exported in parallel in batch and repository exports (default: 4)")
+	fmt.Fprintln(w, "  -state S")
+	fmt.Fprintln(w, "        Repository export: comma-separated states to include (open, closed, merged)")
+	fmt.Fprintln(w, "  -label L")
+	fmt.Fprintln(w, "        Repository export: comma-separated labels that must all be present")
+	fmt.Fprintln(w, "  -author A")
+	fmt.Fprintln(w, "        Repository export: only items opened by this user")
+	fmt.Fprintln(w, "  -created-after T, -created-before T")
+	fmt.Fprintln(w, "        Repository export: creation time range (YYYY-MM-DD or RFC 3339)")
+	fmt.Fprintln(w, "  -updated-after T, -updated-before T")
+	fmt.Fprintln(w, "        Repository export: last update time range (YYYY-MM-DD or RFC 3339)")
 	fmt.Fprintln(w, "  -h")
 	fmt.Fprintln(w, "        Show this help message")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Environment Variables:")
 	fmt.Fprintln(w, "  GITHUB_TOKEN")
 	fmt.Fprintln(w, "        GitHub personal access token (optional, for private repos)")
+	fmt.Fprintln(w, "  GITHUB_HOSTS")
+	fmt.Fprintln(w, "        Comma-separated GitHub Enterprise Server hostnames accepted in URLs")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Examples:")
 	fmt.Fprintln(w, "  issue2md https://github.com/owner/repo/issues/123")
 	fmt.Fprintln(w, "  issue2md -enable-reactions https://github.com/owner/repo/issues/123 output.md")
+	fmt.Fprintln(w, "  issue2md owner/repo#123")
+	fmt.Fprintln(w, "  issue2md -enable-timeline https://github.com/owner/repo/pull/42 pr-42.md")
+	fmt.Fprintln(w, "  issue2md -enable-diff -max-diff-lines 200 https://github.com/owner/repo/pull/42 pr-42.md")
+	fmt.Fprintln(w, "  issue2md '#123' issue-123.md")
 	fmt.Fprintln(w, "  GITHUB_TOKEN=ghp_xxx issue2md https://github.com/owner/private-repo/issues/1")
+	fmt.Fprintln(w, "  issue2md -state open -label bug https://github.com/owner/repo/issues ./archive")
+	fmt.Fprintln(w, "  issue2md -format html https://github.com/owner/repo/issues/123 issue.html")
+	fmt.Fprintln(w, "  issue2md -frontmatter toml -frontmatter-fields title,created_at:date,labels:tags https://github.com/owner/repo/issues/123")
+	fmt.Fprintln(w, "  issue2md -anchor comment -anchor-context 2 https://github.com/owner/repo/issues/123#issuecomment-456")
+	fmt.Fprintln(w, "  issue2md -download-assets https://github.com/owner/repo/issues/123 notes/issue-123.md")
+	fmt.Fprintln(w, "  issue2md -batch urls.txt ./archive")
+	fmt.Fprintln(w, "  GITHUB_HOSTS=github.example.com issue2md https://github.example.com/owner/repo/issues/1")
 }
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/wangyulu/issue2md2/internal/export"
+)
+
+// PrintSummary 以表格形式打印多资源导出的结果，返回失败的数量
+func PrintSummary(w io.Writer, results []export.Result) int {
+	failed := 0
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tURL\tOUTPUT")
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(tw, "failed\t%s\t%v\n", r.URL, r.Err)
+			continue
+		}
+		fmt.Fprintf(tw, "ok\t%s\t%s\n", r.URL, r.Path)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%d succeeded, %d failed\n", len(results)-failed, failed)
+	return failed
+}
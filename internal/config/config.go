@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 )
 
 // GetGitHubToken 从环境变量读取 GitHub Token
@@ -9,3 +10,15 @@ import (
 func GetGitHubToken() string {
 	return os.Getenv("GITHUB_TOKEN")
 }
+
+// GetGitHubHosts 从环境变量 GITHUB_HOSTS 读取额外的 GitHub Enterprise Server 主机名
+// 多个主机名以逗号分隔，忽略空白项；未设置时返回 nil
+func GetGitHubHosts() []string {
+	var hosts []string
+	for _, h := range strings.Split(os.Getenv("GITHUB_HOSTS"), ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
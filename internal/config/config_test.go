@@ -44,3 +44,33 @@ func TestGetGitHubToken(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGitHubHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{name: "未设置", value: "", expected: nil},
+		{name: "单个主机", value: "github.example.com", expected: []string{"github.example.com"}},
+		{name: "多个主机并忽略空白", value: " github.example.com, ,localhost:8080 ", expected: []string{"github.example.com", "localhost:8080"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("GITHUB_HOSTS", tt.value)
+			defer os.Unsetenv("GITHUB_HOSTS")
+
+			result := GetGitHubHosts()
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("GetGitHubHosts() = %q, want %q", result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("GetGitHubHosts()[%d] = %q, want %q", i, result[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
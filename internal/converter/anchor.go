@@ -0,0 +1,133 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// AnchorMode URL 指向某条评论（Document.Anchor 不为空）时的导出方式
+type AnchorMode string
+
+// 支持的锚点导出方式
+const (
+	AnchorThread    AnchorMode = "thread"    // 导出整个讨论，忽略锚点
+	AnchorComment   AnchorMode = "comment"   // 只导出锚点评论及前后 Options.AnchorContext 条评论
+	AnchorHighlight AnchorMode = "highlight" // 导出整个讨论并突出显示锚点评论
+)
+
+// commentAnchor 返回评论链接中的锚点，如 issuecomment-456；没有片段时返回空字符串
+func commentAnchor(url string) string {
+	if i := strings.LastIndex(url, "#"); i >= 0 {
+		return url[i+1:]
+	}
+	return ""
+}
+
+// isAnchored 判断评论是否为 anchor 指向的评论
+func isAnchored(comment github.Comment, anchor string) bool {
+	return anchor != "" && commentAnchor(comment.URL) == anchor
+}
+
+// applyAnchor 按 opts.AnchorMode 处理 doc.Anchor 指向的评论，返回处理后的副本，不修改 doc
+//   - AnchorThread（默认）: 清除锚点，按普通讨论导出
+//   - AnchorHighlight: 保留全部内容，渲染时突出显示锚点评论
+//   - AnchorComment: 去掉正文、时间线事件和 Review，只保留锚点评论及其前后的评论；
+//     锚点为行内代码评审评论或 Discussion 回复时，只保留所在的讨论或评论
+//
+// 锚点评论不存在时返回错误
+func applyAnchor(doc *Document, opts *Options) (*Document, error) {
+	if doc.Anchor == "" {
+		return doc, nil
+	}
+
+	result := *doc
+	if opts.AnchorMode == AnchorThread || opts.AnchorMode == "" {
+		result.Anchor = ""
+		return &result, nil
+	}
+
+	comments, thread := findAnchor(doc)
+	if comments < 0 && thread < 0 {
+		if doc.CommentsTruncated {
+			return nil, fmt.Errorf("comment #%s not found (comments truncated by max-comments limit)", doc.Anchor)
+		}
+		return nil, fmt.Errorf("comment #%s not found", doc.Anchor)
+	}
+
+	switch opts.AnchorMode {
+	case AnchorHighlight:
+		return &result, nil
+	case AnchorComment:
+	default:
+		return nil, fmt.Errorf("unsupported anchor mode: %s", opts.AnchorMode)
+	}
+
+	result.Body = ""
+	result.Edits = nil
+	result.Poll = nil
+	result.Reactions = nil
+	result.Comments = nil
+	result.CommentsTruncated = false
+	result.Events = nil
+	result.Reviews = nil
+	result.ReviewThreads = nil
+	result.Files = nil
+	result.DiffTooLarge = false
+	result.Answer = nil
+
+	if comments >= 0 {
+		c := doc.Comments[comments]
+		if isAnchored(c, doc.Anchor) {
+			result.Comments = contextWindow(doc.Comments, comments, opts.AnchorContext)
+			return &result, nil
+		}
+		for i, reply := range c.Replies {
+			if isAnchored(reply, doc.Anchor) {
+				c.Replies = contextWindow(c.Replies, i, opts.AnchorContext)
+				break
+			}
+		}
+		result.Comments = []github.Comment{c}
+		return &result, nil
+	}
+
+	t := doc.ReviewThreads[thread]
+	for i, comment := range t.Comments {
+		if isAnchored(comment, doc.Anchor) {
+			t.Comments = contextWindow(t.Comments, i, opts.AnchorContext)
+			break
+		}
+	}
+	result.ReviewThreads = []github.ReviewThread{t}
+	return &result, nil
+}
+
+// findAnchor 查找锚点评论，返回其（或其所回复的评论）在 Comments 中的下标或所在 ReviewThreads 的下标，
+// 未找到的一方为 -1
+func findAnchor(doc *Document) (comment, thread int) {
+	for i, c := range doc.Comments {
+		if isAnchored(c, doc.Anchor) {
+			return i, -1
+		}
+		for _, reply := range c.Replies {
+			if isAnchored(reply, doc.Anchor) {
+				return i, -1
+			}
+		}
+	}
+	for i, t := range doc.ReviewThreads {
+		for _, c := range t.Comments {
+			if isAnchored(c, doc.Anchor) {
+				return -1, i
+			}
+		}
+	}
+	return -1, -1
+}
+
+// contextWindow 返回 comments[i] 及其前后各 n 条评论
+func contextWindow(comments []github.Comment, i, n int) []github.Comment {
+	return comments[max(i-n, 0):min(i+n+1, len(comments))]
+}
@@ -0,0 +1,243 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// anchorDocument 返回包含 5 条评论和 2 个行内代码评审讨论的 Pull Request
+func anchorDocument(anchor string) *Document {
+	var comments []github.Comment
+	for i := 1; i <= 5; i++ {
+		comments = append(comments, github.Comment{
+			Author: fmt.Sprintf("user%d", i),
+			Body:   fmt.Sprintf("Comment %d", i),
+			URL:    fmt.Sprintf("https://github.com/owner/repo/pull/42#issuecomment-%d", i),
+		})
+	}
+
+	doc := PullRequestDocument(&github.PullRequest{
+		Thread: github.Thread{
+			Title:         "Anchored",
+			Body:          "PR body",
+			Comments:      comments,
+			TotalComments: 5,
+		},
+		Reviews: []github.Review{{Author: "reviewer", State: "APPROVED"}},
+		ReviewThreads: []github.ReviewThread{
+			{Path: "a.go", Comments: []github.Comment{{Body: "On a.go", URL: "https://github.com/owner/repo/pull/42#discussion_r10"}}},
+			{Path: "b.go", Comments: []github.Comment{
+				{Body: "On b.go", URL: "https://github.com/owner/repo/pull/42#discussion_r20"},
+				{Body: "Reply", URL: "https://github.com/owner/repo/pull/42#discussion_r21"},
+			}},
+		},
+	})
+	doc.Anchor = anchor
+	return doc
+}
+
+func TestApplyAnchor(t *testing.T) {
+	tests := []struct {
+		name             string
+		anchor           string
+		mode             AnchorMode
+		context          int
+		expectedAnchor   string
+		expectedBody     string
+		expectedComments []string
+		expectedThreads  []string
+		expectedErr      string
+	}{
+		{
+			name:             "没有锚点",
+			mode:             AnchorComment,
+			expectedBody:     "PR body",
+			expectedComments: []string{"Comment 1", "Comment 2", "Comment 3", "Comment 4", "Comment 5"},
+			expectedThreads:  []string{"a.go", "b.go"},
+		},
+		{
+			name:             "默认导出整个讨论",
+			anchor:           "issuecomment-3",
+			expectedBody:     "PR body",
+			expectedComments: []string{"Comment 1", "Comment 2", "Comment 3", "Comment 4", "Comment 5"},
+			expectedThreads:  []string{"a.go", "b.go"},
+		},
+		{
+			name:             "突出显示",
+			anchor:           "issuecomment-3",
+			mode:             AnchorHighlight,
+			expectedAnchor:   "issuecomment-3",
+			expectedBody:     "PR body",
+			expectedComments: []string{"Comment 1", "Comment 2", "Comment 3", "Comment 4", "Comment 5"},
+			expectedThreads:  []string{"a.go", "b.go"},
+		},
+		{
+			name:             "只导出锚点评论",
+			anchor:           "issuecomment-3",
+			mode:             AnchorComment,
+			expectedAnchor:   "issuecomment-3",
+			expectedComments: []string{"Comment 3"},
+		},
+		{
+			name:             "前后各一条评论",
+			anchor:           "issuecomment-3",
+			mode:             AnchorComment,
+			context:          1,
+			expectedAnchor:   "issuecomment-3",
+			expectedComments: []string{"Comment 2", "Comment 3", "Comment 4"},
+		},
+		{
+			name:             "上下文超出评论范围",
+			anchor:           "issuecomment-1",
+			mode:             AnchorComment,
+			context:          2,
+			expectedAnchor:   "issuecomment-1",
+			expectedComments: []string{"Comment 1", "Comment 2", "Comment 3"},
+		},
+		{
+			name:            "行内代码评审评论",
+			anchor:          "discussion_r21",
+			mode:            AnchorComment,
+			expectedAnchor:  "discussion_r21",
+			expectedThreads: []string{"b.go"},
+		},
+		{
+			name:        "锚点评论不存在",
+			anchor:      "issuecomment-9",
+			mode:        AnchorHighlight,
+			expectedErr: "comment #issuecomment-9 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := anchorDocument(tt.anchor)
+			result, err := applyAnchor(doc, &Options{AnchorMode: tt.mode, AnchorContext: tt.context})
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("applyAnchor() error = %v, want %q", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyAnchor() error = %v", err)
+			}
+
+			if result.Anchor != tt.expectedAnchor {
+				t.Errorf("Anchor = %q, want %q", result.Anchor, tt.expectedAnchor)
+			}
+			if result.Body != tt.expectedBody {
+				t.Errorf("Body = %q, want %q", result.Body, tt.expectedBody)
+			}
+
+			var comments []string
+			for _, c := range result.Comments {
+				comments = append(comments, c.Body)
+			}
+			if strings.Join(comments, ",") != strings.Join(tt.expectedComments, ",") {
+				t.Errorf("Comments = %v, want %v", comments, tt.expectedComments)
+			}
+
+			var threads []string
+			for _, thread := range result.ReviewThreads {
+				threads = append(threads, thread.Path)
+			}
+			if strings.Join(threads, ",") != strings.Join(tt.expectedThreads, ",") {
+				t.Errorf("ReviewThreads = %v, want %v", threads, tt.expectedThreads)
+			}
+
+			// 原 Document 不被修改
+			if doc.Anchor != tt.anchor || len(doc.Comments) != 5 || doc.Body != "PR body" {
+				t.Errorf("applyAnchor() modified the original document")
+			}
+		})
+	}
+}
+
+func TestApplyAnchorReviewThreadContext(t *testing.T) {
+	doc := anchorDocument("discussion_r21")
+
+	result, err := applyAnchor(doc, &Options{AnchorMode: AnchorComment})
+	if err != nil {
+		t.Fatalf("applyAnchor() error = %v", err)
+	}
+	if len(result.ReviewThreads) != 1 || len(result.ReviewThreads[0].Comments) != 1 || result.ReviewThreads[0].Comments[0].Body != "Reply" {
+		t.Errorf("ReviewThreads = %+v, want only the anchored reply", result.ReviewThreads)
+	}
+	if len(doc.ReviewThreads[1].Comments) != 2 {
+		t.Errorf("applyAnchor() modified the original review thread")
+	}
+}
+
+func TestConvertAnchorHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		anchor   string
+		expected []string
+	}{
+		{name: "Markdown", format: FormatMarkdown, anchor: "issuecomment-2", expected: []string{"Comment 2\n📌 **Linked comment**\n"}},
+		{name: "Markdown 行内代码评审", format: FormatMarkdown, anchor: "discussion_r21", expected: []string{"Reply\n📌 **Linked comment**\n"}},
+		{name: "HTML", format: FormatHTML, anchor: "issuecomment-2", expected: []string{`<article class="comment anchored" id="comment-2">`, `<span class="linked">📌 Linked comment</span>`}},
+		{name: "JSON", format: FormatJSON, anchor: "issuecomment-2", expected: []string{`"anchor": "issuecomment-2"`, `"url": "https://github.com/owner/repo/pull/42#issuecomment-2"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(anchorDocument(tt.anchor), &Options{Format: tt.format, AnchorMode: AnchorHighlight})
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(result), expected) {
+					t.Errorf("Convert() missing %q, got:\n%s", expected, result)
+				}
+			}
+			if tt.format != FormatJSON && strings.Count(string(result), "Linked comment") != 1 {
+				t.Errorf("Convert() should highlight exactly one comment, got:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestApplyAnchorDiscussionReply(t *testing.T) {
+	var replies []github.Comment
+	for i := 1; i <= 3; i++ {
+		replies = append(replies, github.Comment{
+			Body: fmt.Sprintf("Reply %d", i),
+			URL:  fmt.Sprintf("https://github.com/owner/repo/discussions/4#discussioncomment-1%d", i),
+		})
+	}
+	doc := DiscussionDocument(&github.Discussion{
+		Thread: github.Thread{
+			Title: "Question",
+			Comments: []github.Comment{
+				{Body: "Comment 1", URL: "https://github.com/owner/repo/discussions/4#discussioncomment-1", Replies: replies},
+				{Body: "Comment 2", URL: "https://github.com/owner/repo/discussions/4#discussioncomment-2"},
+			},
+		},
+		Answer: &replies[1],
+	})
+	doc.Anchor = "discussioncomment-12"
+
+	result, err := applyAnchor(doc, &Options{AnchorMode: AnchorComment})
+	if err != nil {
+		t.Fatalf("applyAnchor() error = %v", err)
+	}
+	if len(result.Comments) != 1 || result.Comments[0].Body != "Comment 1" {
+		t.Fatalf("Comments = %+v, want only the replied comment", result.Comments)
+	}
+	if len(result.Comments[0].Replies) != 1 || result.Comments[0].Replies[0].Body != "Reply 2" {
+		t.Errorf("Replies = %+v, want only the anchored reply", result.Comments[0].Replies)
+	}
+	if result.Answer != nil {
+		t.Errorf("Answer = %+v, want nil", result.Answer)
+	}
+	if len(doc.Comments[0].Replies) != 3 {
+		t.Errorf("applyAnchor() modified the original replies")
+	}
+}
@@ -3,7 +3,6 @@ package converter
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/wangyulu/issue2md2/internal/github"
 )
@@ -11,8 +10,23 @@ import (
 // Reactions 反应统计（使用 github 包的类型）
 type Reactions = github.Reactions
 
-// Options Markdown 转换选项
+// Options 转换选项
 type Options struct {
+	Format   Format // 输出格式，为空时输出 Markdown
+	Template string // 自定义 Markdown 模板内容，为空时使用 DefaultTemplate
+
+	Frontmatter       FrontmatterFormat  // Frontmatter 格式，为空时使用 YAML
+	FrontmatterFields []FrontmatterField // 输出的字段及键名，为空时使用默认字段
+
+	AnchorMode    AnchorMode // URL 指向某条评论时的导出方式，为空时导出整个讨论
+	AnchorContext int        // AnchorComment 模式下锚点评论前后各保留的评论数
+
+	MaxDiffLines int // 每个文件的 diff 最多显示的行数，0 表示不限制
+
+	Minimized MinimizedMode // 被隐藏的评论的导出方式，为空时折叠
+
+	EditHistory EditHistoryMode // 正文和评论修订记录的展示方式，为空时不展示
+
 	EnableReactions bool // 是否启用 Reactions 显示
 	EnableUserLinks bool // 是否将用户名渲染为链接
 }
@@ -20,6 +34,8 @@ type Options struct {
 // DefaultOptions 返回默认转换选项
 func DefaultOptions() *Options {
 	return &Options{
+		Format:          FormatMarkdown,
+		MaxDiffLines:    DefaultMaxDiffLines,
 		EnableReactions: false,
 		EnableUserLinks: false,
 	}
@@ -69,157 +85,122 @@ func renderUser(username, userURL string, enableLinks bool) string {
 	return fmt.Sprintf("@%s", username)
 }
 
-// ToMarkdown 将 Issue 转换为 Markdown 字符串
-func ToMarkdown(issue *github.Issue, opts *Options) ([]byte, error) {
-	var sb strings.Builder
-
-	// Frontmatter
-	sb.WriteString(generateFrontmatter(issue.Title, issue.URL, issue.Author, issue.AuthorURL, issue.CreatedAt, issue.Status, "issue"))
-	sb.WriteString("\n")
-
-	// 标题和正文
-	sb.WriteString(fmt.Sprintf("# %s\n", issue.Title))
-	sb.WriteString("\n")
-	if issue.Body != "" {
-		sb.WriteString(issue.Body)
-		sb.WriteString("\n")
-	}
-
-	// Reactions
-	if opts.EnableReactions && issue.Reactions != nil {
-		sb.WriteString("## Reactions\n")
-		sb.WriteString("\n")
-		sb.WriteString(renderReactions(issue.Reactions))
-		sb.WriteString("\n")
-	}
-
-	// Comments
-	if len(issue.Comments) > 0 {
-		sb.WriteString("---\n")
-		sb.WriteString("\n")
-		sb.WriteString("## Comments\n")
-		sb.WriteString("\n")
-
-		for _, comment := range issue.Comments {
-			sb.WriteString(fmt.Sprintf("### %s commented at %s\n",
-				renderUser(comment.Author, comment.AuthorURL, opts.EnableUserLinks),
-				comment.CreatedAt.UTC().Format(time.RFC3339)))
-			sb.WriteString("\n")
-			if comment.Body != "" {
-				sb.WriteString(comment.Body)
-				sb.WriteString("\n")
-			}
-			if opts.EnableReactions && comment.Reactions != nil {
-				sb.WriteString(renderReactions(comment.Reactions))
-				sb.WriteString("\n")
-			}
-		}
+// renderBadges 返回用户名后的标记，如 [bot]、(maintainer)、[bot] (contributor)，没有标记时返回空字符串
+// OWNER、MEMBER 和 COLLABORATOR 均视为 maintainer；NONE 等其他关系不显示
+func renderBadges(authorType, association string) string {
+	var parts []string
+	switch authorType {
+	case "Bot":
+		parts = append(parts, "[bot]")
+	case "Mannequin":
+		parts = append(parts, "[mannequin]")
+	}
+	switch association {
+	case "OWNER", "MEMBER", "COLLABORATOR":
+		parts = append(parts, "(maintainer)")
+	case "CONTRIBUTOR":
+		parts = append(parts, "(contributor)")
+	case "FIRST_TIME_CONTRIBUTOR", "FIRST_TIMER":
+		parts = append(parts, "(first-time contributor)")
 	}
+	return strings.Join(parts, " ")
+}
 
-	return []byte(sb.String()), nil
+// ToMarkdown 将 Issue 转换为 Markdown 字符串
+func ToMarkdown(issue *github.Issue, opts *Options) ([]byte, error) {
+	return toMarkdown(IssueDocument(issue), opts)
 }
 
 // ToMarkdownPR 将 PullRequest 转换为 Markdown 字符串
 func ToMarkdownPR(pr *github.PullRequest, opts *Options) ([]byte, error) {
-	var sb strings.Builder
-
-	// Frontmatter
-	sb.WriteString(generateFrontmatter(pr.Title, pr.URL, pr.Author, pr.AuthorURL, pr.CreatedAt, pr.Status, "pull_request"))
-	sb.WriteString("\n")
-
-	// 标题和描述
-	sb.WriteString(fmt.Sprintf("# %s\n", pr.Title))
-	sb.WriteString("\n")
-	if pr.Body != "" {
-		sb.WriteString(pr.Body)
-		sb.WriteString("\n")
-	}
-
-	// Reactions
-	if opts.EnableReactions && pr.Reactions != nil {
-		sb.WriteString("## Reactions\n")
-		sb.WriteString("\n")
-		sb.WriteString(renderReactions(pr.Reactions))
-		sb.WriteString("\n")
-	}
-
-	// Comments
-	if len(pr.Comments) > 0 {
-		sb.WriteString("---\n")
-		sb.WriteString("\n")
-		sb.WriteString("## Comments\n")
-		sb.WriteString("\n")
-
-		for _, comment := range pr.Comments {
-			sb.WriteString(fmt.Sprintf("### %s commented at %s\n",
-				renderUser(comment.Author, comment.AuthorURL, opts.EnableUserLinks),
-				comment.CreatedAt.UTC().Format(time.RFC3339)))
-			sb.WriteString("\n")
-			if comment.Body != "" {
-				sb.WriteString(comment.Body)
-				sb.WriteString("\n")
-			}
-			if opts.EnableReactions && comment.Reactions != nil {
-				sb.WriteString(renderReactions(comment.Reactions))
-				sb.WriteString("\n")
-			}
-		}
-	}
-
-	return []byte(sb.String()), nil
+	return toMarkdown(PullRequestDocument(pr), opts)
 }
 
 // ToMarkdownDiscussion 将 Discussion 转换为 Markdown 字符串
 func ToMarkdownDiscussion(discussion *github.Discussion, opts *Options) ([]byte, error) {
-	var sb strings.Builder
-
-	// Frontmatter
-	sb.WriteString(generateFrontmatter(discussion.Title, discussion.URL, discussion.Author, discussion.AuthorURL, discussion.CreatedAt, discussion.Status, "discussion"))
-	sb.WriteString("\n")
-
-	// 标题和正文
-	sb.WriteString(fmt.Sprintf("# %s\n", discussion.Title))
-	sb.WriteString("\n")
-	if discussion.Body != "" {
-		sb.WriteString(discussion.Body)
-		sb.WriteString("\n")
-	}
-
-	// Reactions
-	if opts.EnableReactions && discussion.Reactions != nil {
-		sb.WriteString("## Reactions\n")
-		sb.WriteString("\n")
-		sb.WriteString(renderReactions(discussion.Reactions))
-		sb.WriteString("\n")
-	}
-
-	// Comments
-	if len(discussion.Comments) > 0 {
-		sb.WriteString("---\n")
-		sb.WriteString("\n")
-		sb.WriteString("## Comments\n")
-		sb.WriteString("\n")
-
-		for _, comment := range discussion.Comments {
-			sb.WriteString(fmt.Sprintf("### %s commented at %s\n",
-				renderUser(comment.Author, comment.AuthorURL, opts.EnableUserLinks),
-				comment.CreatedAt.UTC().Format(time.RFC3339)))
-			sb.WriteString("\n")
-			if comment.Body != "" {
-				sb.WriteString(comment.Body)
-				sb.WriteString("\n")
-			}
-			// Answer 标记
-			if comment.IsAnswer {
-				sb.WriteString("✅ **Answer**")
-				sb.WriteString("\n")
-			}
-			if opts.EnableReactions && comment.Reactions != nil {
-				sb.WriteString(renderReactions(comment.Reactions))
-				sb.WriteString("\n")
+	return toMarkdown(DiscussionDocument(discussion), opts)
+}
+
+// toMarkdown 忽略 opts.Format，经 Convert 将 Document 渲染为 Markdown，与其他格式一样处理锚点和被隐藏的评论
+func toMarkdown(doc *Document, opts *Options) ([]byte, error) {
+	markdown := *opts
+	markdown.Format = FormatMarkdown
+	return Convert(doc, &markdown)
+}
+
+// showReview 判断 Review 是否需要展示
+// 没有正文的普通 Review 只是行内评论的容器，不单独展示
+func showReview(review github.Review) bool {
+	if reviewVerb(review.State) == "" {
+		return false
+	}
+	return review.State != "COMMENTED" || review.Body != ""
+}
+
+// reviewVerb 返回 Review 状态对应的动词，不需要展示的状态返回空字符串
+func reviewVerb(state string) string {
+	switch state {
+	case "APPROVED":
+		return "approved"
+	case "CHANGES_REQUESTED":
+		return "requested changes"
+	case "COMMENTED":
+		return "reviewed"
+	case "DISMISSED":
+		return "reviewed (dismissed)"
+	default:
+		return ""
+	}
+}
+
+// renderThreadLocation 渲染讨论所在的文件和行号，如 `main.go` lines 10-12 (resolved)
+func renderThreadLocation(thread github.ReviewThread) string {
+	location := fmt.Sprintf("`%s`", thread.Path) + threadLines(thread)
+	if states := threadStates(thread); states != "" {
+		location += fmt.Sprintf(" (%s)", states)
+	}
+	return location
+}
+
+// threadLines 返回讨论所在的行号描述，如 " lines 10-12"，行号未知时返回空字符串
+func threadLines(thread github.ReviewThread) string {
+	switch {
+	case thread.StartLine > 0 && thread.StartLine != thread.Line:
+		return fmt.Sprintf(" lines %d-%d", thread.StartLine, thread.Line)
+	case thread.Line > 0:
+		return fmt.Sprintf(" line %d", thread.Line)
+	default:
+		return ""
+	}
+}
+
+// threadStates 返回讨论的状态描述，如 "resolved, outdated"
+func threadStates(thread github.ReviewThread) string {
+	var states []string
+	if thread.IsResolved {
+		states = append(states, "resolved")
+	}
+	if thread.IsOutdated {
+		states = append(states, "outdated")
+	}
+	return strings.Join(states, ", ")
+}
+
+// codeFence 返回比内容中最长连续反引号更长的代码块围栏（至少 3 个）
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
 			}
+		} else {
+			run = 0
 		}
 	}
-
-	return []byte(sb.String()), nil
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
 }
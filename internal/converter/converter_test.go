@@ -109,6 +109,31 @@ func TestRenderUser(t *testing.T) {
 	}
 }
 
+func TestRenderBadges(t *testing.T) {
+	tests := []struct {
+		name        string
+		authorType  string
+		association string
+		expected    string
+	}{
+		{name: "普通用户", authorType: "User", association: "NONE", expected: ""},
+		{name: "仓库所有者", authorType: "User", association: "OWNER", expected: "(maintainer)"},
+		{name: "协作者", authorType: "User", association: "COLLABORATOR", expected: "(maintainer)"},
+		{name: "首次贡献者", authorType: "User", association: "FIRST_TIME_CONTRIBUTOR", expected: "(first-time contributor)"},
+		{name: "Bot", authorType: "Bot", association: "NONE", expected: "[bot]"},
+		{name: "贡献过的 Bot", authorType: "Bot", association: "CONTRIBUTOR", expected: "[bot] (contributor)"},
+		{name: "未知", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := renderBadges(tt.authorType, tt.association); result != tt.expected {
+				t.Errorf("renderBadges() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestDefaultOptions(t *testing.T) {
 	opts := DefaultOptions()
 
@@ -122,99 +147,138 @@ func TestDefaultOptions(t *testing.T) {
 
 func TestToMarkdown(t *testing.T) {
 	tests := []struct {
-		name           string
-		issue          *github.Issue
+		name            string
+		issue           *github.Issue
 		enableReactions bool
 		enableUserLinks bool
-		contains       []string // 检查输出是否包含某些关键字
+		contains        []string // 检查输出是否包含某些关键字
 	}{
 		{
 			name: "简单 Issue（无评论）",
 			issue: &github.Issue{
-				Title:     "Test Issue",
-				Body:      "Issue body",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/octocat/Hello-World/issues/123",
-				Comments: []github.Comment{},
+				Thread: github.Thread{
+					Title:     "Test Issue",
+					Body:      "Issue body",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/octocat/Hello-World/issues/123",
+					Comments:  []github.Comment{},
+				},
 			},
 			enableReactions: false,
 			enableUserLinks: false,
-			contains:       []string{"---", "Test Issue", "Issue body"},
+			contains:        []string{"---", "Test Issue", "Issue body"},
 		},
 		{
 			name: "带 Reactions 的 Issue",
 			issue: &github.Issue{
-				Title:     "Test Issue",
-				Body:      "Issue body",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/octocat/Hello-World/issues/123",
-				Reactions: &github.Reactions{
-					ThumbsUp: 5,
-					Heart:      3,
+				Thread: github.Thread{
+					Title:     "Test Issue",
+					Body:      "Issue body",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/octocat/Hello-World/issues/123",
+					Reactions: &github.Reactions{
+						ThumbsUp: 5,
+						Heart:    3,
+					},
 				},
 			},
 			enableReactions: true,
 			enableUserLinks: false,
-			contains:       []string{"## Reactions", "👍 5", "❤️ 3"},
+			contains:        []string{"## Reactions", "👍 5", "❤️ 3"},
 		},
 		{
 			name: "带评论的 Issue",
 			issue: &github.Issue{
-				Title:     "Test Issue",
-				Body:      "Issue body",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/octocat/Hello-World/issues/123",
-				Comments: []github.Comment{
-					{
-						Body:      "First comment",
-						Author:    "user1",
-						AuthorURL: "https://github.com/user1",
-						CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
-					},
-					{
-						Body:      "Second comment",
-						Author:    "user2",
-						AuthorURL: "https://github.com/user2",
-						CreatedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+				Thread: github.Thread{
+					Title:     "Test Issue",
+					Body:      "Issue body",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/octocat/Hello-World/issues/123",
+					Comments: []github.Comment{
+						{
+							Body:      "First comment",
+							Author:    "user1",
+							AuthorURL: "https://github.com/user1",
+							CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+						},
+						{
+							Body:      "Second comment",
+							Author:    "user2",
+							AuthorURL: "https://github.com/user2",
+							CreatedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+						},
 					},
 				},
 			},
 			enableReactions: false,
 			enableUserLinks: false,
-			contains: []string{"### @user1", "### @user2"},
+			contains:        []string{"### @user1", "### @user2"},
 		},
 		{
 			name: "带 Reactions 的评论",
 			issue: &github.Issue{
-				Title:     "Test Issue",
-				Body:      "Issue body",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/octocat/Hello-World/issues/123",
-				Comments: []github.Comment{
-					{
-						Body:      "Comment with reactions",
-						Author:    "user1",
-						AuthorURL: "https://github.com/user1",
-						CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
-						Reactions: &github.Reactions{ThumbsUp: 2},
+				Thread: github.Thread{
+					Title:     "Test Issue",
+					Body:      "Issue body",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/octocat/Hello-World/issues/123",
+					Comments: []github.Comment{
+						{
+							Body:      "Comment with reactions",
+							Author:    "user1",
+							AuthorURL: "https://github.com/user1",
+							CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+							Reactions: &github.Reactions{ThumbsUp: 2},
+						},
 					},
 				},
 			},
 			enableReactions: true,
 			enableUserLinks: false,
-			contains: []string{"👍 2"},
+			contains:        []string{"👍 2"},
+		},
+		{
+			name: "评论被截断的 Issue",
+			issue: &github.Issue{
+				Thread: github.Thread{
+					Title:     "Test Issue",
+					Body:      "Issue body",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/octocat/Hello-World/issues/123",
+					Comments: []github.Comment{
+						{
+							Body:      "First comment",
+							Author:    "user1",
+							AuthorURL: "https://github.com/user1",
+							CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+						},
+					},
+					TotalComments:     230,
+					CommentsTruncated: true,
+				},
+			},
+			enableReactions: false,
+			enableUserLinks: false,
+			contains: []string{
+				"comments_total: 230\n",
+				"comments_truncated: true\n",
+				"> Showing 1 of 230 comments (truncated by max-comments limit)",
+			},
 		},
 	}
 
@@ -255,45 +319,49 @@ func TestToMarkdown(t *testing.T) {
 func TestToMarkdownPR(t *testing.T) {
 	tests := []struct {
 		name     string
-		pr        *github.PullRequest
-		contains  []string
+		pr       *github.PullRequest
+		contains []string
 	}{
 		{
 			name: "简单 PR（无评论）",
 			pr: &github.PullRequest{
-				Title:     "Test PR",
-				Body:      "PR description",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/octocat/Hello-World/pull/42",
-				Comments: []github.Comment{},
+				Thread: github.Thread{
+					Title:     "Test PR",
+					Body:      "PR description",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/octocat/Hello-World/pull/42",
+					Comments:  []github.Comment{},
+				},
 			},
 			contains: []string{"type: \"pull_request\"", "Test PR", "PR description"},
 		},
 		{
 			name: "带 Review Comments 的 PR",
 			pr: &github.PullRequest{
-				Title:     "Test PR",
-				Body:      "PR description",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/octocat/Hello-World/pull/42",
-				Comments: []github.Comment{
-					{
-						Body:      "Review comment 1",
-						Author:    "reviewer1",
-						AuthorURL: "https://github.com/reviewer1",
-						CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
-					},
-					{
-						Body:      "Regular comment",
-						Author:    "commenter1",
-						AuthorURL: "https://github.com/commenter1",
-						CreatedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+				Thread: github.Thread{
+					Title:     "Test PR",
+					Body:      "PR description",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/octocat/Hello-World/pull/42",
+					Comments: []github.Comment{
+						{
+							Body:      "Review comment 1",
+							Author:    "reviewer1",
+							AuthorURL: "https://github.com/reviewer1",
+							CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+						},
+						{
+							Body:      "Regular comment",
+							Author:    "commenter1",
+							AuthorURL: "https://github.com/commenter1",
+							CreatedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+						},
 					},
 				},
 			},
@@ -302,16 +370,79 @@ func TestToMarkdownPR(t *testing.T) {
 		{
 			name: "Merged 状态的 PR",
 			pr: &github.PullRequest{
-				Title:     "Test PR",
-				Body:      "PR description",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "merged",
-				URL:       "https://github.com/octocat/Hello-World/pull/42",
+				Thread: github.Thread{
+					Title:     "Test PR",
+					Body:      "PR description",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "merged",
+					URL:       "https://github.com/octocat/Hello-World/pull/42",
+				},
 			},
 			contains: []string{"status: \"merged\""},
 		},
+		{
+			name: "带 Review 和行内评审讨论的 PR",
+			pr: &github.PullRequest{
+				Thread: github.Thread{
+					Title:     "Test PR",
+					Body:      "PR description",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/octocat/Hello-World/pull/42",
+				},
+
+				Reviews: []github.Review{
+					{
+						Author:      "reviewer1",
+						AuthorURL:   "https://github.com/reviewer1",
+						Body:        "Looks good",
+						State:       "APPROVED",
+						SubmittedAt: time.Date(2024, 1, 4, 10, 0, 0, 0, time.UTC),
+					},
+					{
+						Author:      "reviewer2",
+						AuthorURL:   "https://github.com/reviewer2",
+						State:       "COMMENTED",
+						SubmittedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+					},
+				},
+				ReviewThreads: []github.ReviewThread{
+					{
+						Path:       "main.go",
+						Line:       12,
+						StartLine:  10,
+						DiffHunk:   "@@ -1,3 +1,4 @@\n+fmt.Println()",
+						IsResolved: true,
+						Comments: []github.Comment{
+							{
+								Body:      "Inline comment",
+								Author:    "reviewer2",
+								AuthorURL: "https://github.com/reviewer2",
+								CreatedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+							},
+							{
+								Body:      "Fixed",
+								Author:    "octocat",
+								AuthorURL: "https://github.com/octocat",
+								CreatedAt: time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC),
+							},
+						},
+					},
+				},
+			},
+			contains: []string{
+				"## Review",
+				"### @reviewer1 approved at 2024-01-04T10:00:00Z",
+				"### `main.go` lines 10-12 (resolved)",
+				"```diff\n@@ -1,3 +1,4 @@\n+fmt.Println()\n```",
+				"#### @reviewer2 commented at 2024-01-03T10:00:00Z\n\nInline comment",
+				"#### @octocat commented at 2024-01-03T11:00:00Z\n\nFixed",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -342,41 +473,45 @@ func TestToMarkdownPR(t *testing.T) {
 
 func TestToMarkdownDiscussion(t *testing.T) {
 	tests := []struct {
-		name     string
+		name       string
 		discussion *github.Discussion
-		contains  []string
+		contains   []string
 	}{
 		{
 			name: "简单 Discussion（无评论）",
 			discussion: &github.Discussion{
-				Title:     "Test Discussion",
-				Body:      "Discussion body",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/community/community/discussions/12345",
-				Comments: []github.Comment{},
+				Thread: github.Thread{
+					Title:     "Test Discussion",
+					Body:      "Discussion body",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/community/community/discussions/12345",
+					Comments:  []github.Comment{},
+				},
 			},
 			contains: []string{"type: \"discussion\"", "Test Discussion", "Discussion body"},
 		},
 		{
 			name: "带 Answer 的 Discussion",
 			discussion: &github.Discussion{
-				Title:     "Test Discussion",
-				Body:      "Discussion body",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/community/community/discussions/12345",
-				Comments: []github.Comment{
-					{
-						Body:      "This is the answer",
-						Author:    "expert",
-						AuthorURL: "https://github.com/expert",
-						CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
-						IsAnswer:  true,
+				Thread: github.Thread{
+					Title:     "Test Discussion",
+					Body:      "Discussion body",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/community/community/discussions/12345",
+					Comments: []github.Comment{
+						{
+							Body:      "This is the answer",
+							Author:    "expert",
+							AuthorURL: "https://github.com/expert",
+							CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+							IsAnswer:  true,
+						},
 					},
 				},
 			},
@@ -385,20 +520,22 @@ func TestToMarkdownDiscussion(t *testing.T) {
 		{
 			name: "带 Reactions 的 Answer",
 			discussion: &github.Discussion{
-				Title:     "Test Discussion",
-				Body:      "Discussion body",
-				Author:    "octocat",
-				AuthorURL: "https://github.com/octocat",
-				CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				Status:    "open",
-				URL:       "https://github.com/community/community/discussions/12345",
-				Comments: []github.Comment{
-					{
-						Body:      "This is the answer",
-						Author:    "expert",
-						AuthorURL: "https://github.com/expert",
-						CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
-						IsAnswer:  true,
+				Thread: github.Thread{
+					Title:     "Test Discussion",
+					Body:      "Discussion body",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+					Status:    "open",
+					URL:       "https://github.com/community/community/discussions/12345",
+					Comments: []github.Comment{
+						{
+							Body:      "This is the answer",
+							Author:    "expert",
+							AuthorURL: "https://github.com/expert",
+							CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+							IsAnswer:  true,
+						},
 					},
 				},
 			},
@@ -431,3 +568,59 @@ func TestToMarkdownDiscussion(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderThreadLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		thread   github.ReviewThread
+		expected string
+	}{
+		{
+			name:     "单行",
+			thread:   github.ReviewThread{Path: "main.go", Line: 5},
+			expected: "`main.go` line 5",
+		},
+		{
+			name:     "多行且已解决、已过期",
+			thread:   github.ReviewThread{Path: "main.go", StartLine: 3, Line: 5, IsResolved: true, IsOutdated: true},
+			expected: "`main.go` lines 3-5 (resolved, outdated)",
+		},
+		{
+			name:     "行号未知",
+			thread:   github.ReviewThread{Path: "docs/README.md"},
+			expected: "`docs/README.md`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := renderThreadLocation(tt.thread)
+
+			if result != tt.expected {
+				t.Errorf("renderThreadLocation() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "没有反引号", content: "+a := 1", expected: "```"},
+		{name: "行内代码", content: "+// use `a`", expected: "```"},
+		{name: "包含代码块", content: "+```go\n+a := 1\n+```", expected: "````"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := codeFence(tt.content)
+
+			if result != tt.expected {
+				t.Errorf("codeFence() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
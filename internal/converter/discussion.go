@@ -0,0 +1,40 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// categoryText 返回 Discussion 分类的描述，如 :pray: Q&A，未设置时返回空字符串
+func categoryText(c *github.DiscussionCategory) string {
+	if c == nil {
+		return ""
+	}
+	if c.Emoji == "" {
+		return c.Name
+	}
+	return c.Emoji + " " + c.Name
+}
+
+// renderPoll 将投票渲染为问题、结果表格和总票数
+func renderPoll(p *github.Poll) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n\n", p.Question)
+	b.WriteString("| Option | Votes | Share |\n")
+	b.WriteString("| --- | ---: | ---: |\n")
+	for _, option := range p.Options {
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", strings.ReplaceAll(option.Option, "|", `\|`), option.Votes, pollShare(option.Votes, p.TotalVotes))
+	}
+	fmt.Fprintf(&b, "\n%s\n", plural(p.TotalVotes, "vote"))
+	return b.String()
+}
+
+// pollShare 返回得票占比，如 60%，没有投票时为 0%
+func pollShare(votes, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(votes)*100/float64(total))
+}
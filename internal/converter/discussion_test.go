@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestRenderPoll(t *testing.T) {
+	tests := []struct {
+		name     string
+		poll     *github.Poll
+		expected string
+	}{
+		{
+			name: "有投票",
+			poll: &github.Poll{
+				Question:   "Which format?",
+				TotalVotes: 3,
+				Options:    []github.PollOption{{Option: "YAML", Votes: 2}, {Option: "A|B", Votes: 1}},
+			},
+			expected: "**Which format?**\n\n| Option | Votes | Share |\n| --- | ---: | ---: |\n" +
+				"| YAML | 2 | 67% |\n| A\\|B | 1 | 33% |\n\n3 votes\n",
+		},
+		{
+			name:     "没有投票",
+			poll:     &github.Poll{Question: "Any?", Options: []github.PollOption{{Option: "Yes"}}},
+			expected: "**Any?**\n\n| Option | Votes | Share |\n| --- | ---: | ---: |\n| Yes | 0 | 0% |\n\n0 votes\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := renderPoll(tt.poll); result != tt.expected {
+				t.Errorf("renderPoll() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
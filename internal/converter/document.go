@@ -0,0 +1,95 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// Document 统一的渲染数据模型
+// Issue、Pull Request 和 Discussion 都先转换为 Document，再由同一套流程渲染为各种格式，
+// 因此新的输出特性只需实现一次。类型特有的数据放在 Thread 之外的字段中，其他类型为空
+type Document struct {
+	Type string // issue, pull_request, discussion
+	github.Thread
+
+	Anchor string // 突出显示的评论锚点，如 issuecomment-456；渲染前按 Options.AnchorMode 处理
+
+	// 获取到的评论数，由 IssueDocument 等在按锚点和 -minimized omit 筛选评论之前记录，用于评论截断提示
+	FetchedComments int
+
+	prepared bool // 是否已经过 Prepare 处理
+
+	Assignees []string          // 仅 Issue 和 Pull Request
+	Milestone *github.Milestone // 仅 Issue 和 Pull Request，未设置时为 nil
+
+	StateReason string // 仅 Issue：COMPLETED, NOT_PLANNED, DUPLICATE, REOPENED
+
+	// 仅 Pull Request
+	IsDraft            bool
+	MergedAt           time.Time
+	MergedBy           string
+	BaseRef            string
+	HeadRef            string
+	RequestedReviewers []string
+	Reviews            []github.Review
+	ReviewThreads      []github.ReviewThread
+	Files              []github.ChangedFile
+	DiffTooLarge       bool
+
+	// 仅 Discussion
+	Category       *github.DiscussionCategory
+	UpvoteCount    int
+	Answer         *github.Comment // 被采纳的答案，没有时为 nil
+	AnswerChosenAt time.Time
+	AnswerChosenBy string
+	Poll           *github.Poll
+}
+
+// IssueDocument 将 Issue 转换为 Document
+func IssueDocument(issue *github.Issue) *Document {
+	return &Document{
+		Type:            "issue",
+		Thread:          issue.Thread,
+		FetchedComments: len(issue.Comments),
+		Assignees:       issue.Assignees,
+		Milestone:       issue.Milestone,
+		StateReason:     issue.StateReason,
+	}
+}
+
+// PullRequestDocument 将 PullRequest 转换为 Document
+func PullRequestDocument(pr *github.PullRequest) *Document {
+	return &Document{
+		Type:               "pull_request",
+		Thread:             pr.Thread,
+		FetchedComments:    len(pr.Comments),
+		Assignees:          pr.Assignees,
+		Milestone:          pr.Milestone,
+		IsDraft:            pr.IsDraft,
+		MergedAt:           pr.MergedAt,
+		MergedBy:           pr.MergedBy,
+		BaseRef:            pr.BaseRef,
+		HeadRef:            pr.HeadRef,
+		RequestedReviewers: pr.RequestedReviewers,
+		Reviews:            pr.Reviews,
+		ReviewThreads:      pr.ReviewThreads,
+		Files:              pr.Files,
+		DiffTooLarge:       pr.DiffTooLarge,
+	}
+}
+
+// DiscussionDocument 将 Discussion 转换为 Document
+func DiscussionDocument(discussion *github.Discussion) *Document {
+	return &Document{
+		Type:            "discussion",
+		Thread:          discussion.Thread,
+		FetchedComments: len(discussion.Comments),
+		Category:        discussion.Category,
+		UpvoteCount:     discussion.UpvoteCount,
+		Answer:          discussion.Answer,
+		AnswerChosenAt:  discussion.AnswerChosenAt,
+		AnswerChosenBy:  discussion.AnswerChosenBy,
+		Poll:            discussion.Poll,
+	}
+}
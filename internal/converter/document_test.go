@@ -0,0 +1,80 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestConvertUniform(t *testing.T) {
+	thread := github.Thread{
+		Title:    "Shared",
+		Status:   "open",
+		Labels:   []string{"bug"},
+		Comments: []github.Comment{{Author: "user1", Body: "Shared comment"}},
+	}
+
+	docs := []struct {
+		name string
+		doc  *Document
+	}{
+		{name: "Issue", doc: IssueDocument(&github.Issue{Thread: thread})},
+		{name: "Pull Request", doc: PullRequestDocument(&github.PullRequest{Thread: thread})},
+		{name: "Discussion", doc: DiscussionDocument(&github.Discussion{Thread: thread})},
+	}
+
+	formats := []struct {
+		format   Format
+		expected []string
+	}{
+		{format: FormatMarkdown, expected: []string{"# Shared", "### @user1 commented at", "Shared comment"}},
+		{format: FormatHTML, expected: []string{`<span class="label">bug</span>`, `id="comment-1"`, "Shared comment"}},
+		{format: FormatJSON, expected: []string{`"labels": [`, `"body": "Shared comment"`}},
+	}
+
+	for _, d := range docs {
+		for _, f := range formats {
+			t.Run(d.name+"/"+string(f.format), func(t *testing.T) {
+				result, err := Convert(d.doc, &Options{Format: f.format})
+				if err != nil {
+					t.Fatalf("Convert() error = %v", err)
+				}
+				for _, expected := range f.expected {
+					if !strings.Contains(string(result), expected) {
+						t.Errorf("Convert() missing %q, got:\n%s", expected, result)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDocumentType(t *testing.T) {
+	pr := &github.PullRequest{
+		Reviews:       []github.Review{{State: "APPROVED"}},
+		ReviewThreads: []github.ReviewThread{{Path: "main.go"}},
+	}
+
+	tests := []struct {
+		name     string
+		doc      *Document
+		expected string
+		reviews  int
+	}{
+		{name: "Issue", doc: IssueDocument(&github.Issue{}), expected: "issue"},
+		{name: "Pull Request", doc: PullRequestDocument(pr), expected: "pull_request", reviews: 1},
+		{name: "Discussion", doc: DiscussionDocument(&github.Discussion{}), expected: "discussion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.doc.Type != tt.expected {
+				t.Errorf("Type = %q, want %q", tt.doc.Type, tt.expected)
+			}
+			if len(tt.doc.Reviews) != tt.reviews || len(tt.doc.ReviewThreads) != tt.reviews {
+				t.Errorf("Reviews = %d, ReviewThreads = %d, want %d", len(tt.doc.Reviews), len(tt.doc.ReviewThreads), tt.reviews)
+			}
+		})
+	}
+}
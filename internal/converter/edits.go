@@ -0,0 +1,188 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// EditHistoryMode 正文和评论修订记录的展示方式
+type EditHistoryMode string
+
+// 支持的修订记录展示方式
+const (
+	EditHistoryNone EditHistoryMode = "none" // 不展示（默认）
+	EditHistoryLast EditHistoryMode = "last" // 正文后显示最后一次修订的编辑者和时间
+	EditHistoryFull EditHistoryMode = "full" // 正文后显示可折叠的完整修订历史，相邻版本之间以 diff 展示
+)
+
+// revision 修订历史中的一个版本
+// 第一个版本的 Text 为完整正文，之后为与上一个可用版本的 diff；修订内容被删除时 Text 为空
+type revision struct {
+	Label  string // 如 Edited by @hubot at 2024-01-02T10:00:00Z
+	Text   string
+	IsDiff bool
+}
+
+// lastEditedText 返回最后一次修订的说明，如 Last edited by @hubot at 2024-01-02T10:00:00Z，没有修订时返回空字符串
+func lastEditedText(edits []github.Edit) string {
+	if len(edits) == 0 {
+		return ""
+	}
+	last := edits[len(edits)-1]
+	if last.Editor == "" {
+		return "Last edited at " + formatTime(last.EditedAt)
+	}
+	return fmt.Sprintf("Last edited by @%s at %s", last.Editor, formatTime(last.EditedAt))
+}
+
+// editSummary 返回完整修订历史的标题，如 Last edited by @hubot at 2024-01-02T10:00:00Z (2 revisions)
+func editSummary(edits []github.Edit) string {
+	return fmt.Sprintf("%s (%s)", lastEditedText(edits), plural(len(edits), "revision"))
+}
+
+// revisions 将修订记录转换为修订历史，第一条为原始正文
+func revisions(edits []github.Edit) []revision {
+	var result []revision
+	previous, hasPrevious := "", false
+	for i, edit := range edits {
+		verb := "Edited"
+		if i == 0 {
+			verb = "Original"
+		}
+		label := verb + " at " + formatTime(edit.EditedAt)
+		if edit.Editor != "" {
+			label = fmt.Sprintf("%s by @%s at %s", verb, edit.Editor, formatTime(edit.EditedAt))
+		}
+
+		if edit.Deleted {
+			result = append(result, revision{Label: label + " (revision deleted)"})
+			continue
+		}
+		body := strings.ReplaceAll(edit.Body, "\r\n", "\n")
+		if !hasPrevious {
+			result = append(result, revision{Label: label, Text: strings.TrimRight(body, "\n")})
+		} else {
+			result = append(result, revision{Label: label, Text: lineDiff(previous, body), IsDiff: true})
+		}
+		previous, hasPrevious = body, true
+	}
+	return result
+}
+
+// renderEditHistory 按 opts.EditHistory 渲染正文后的修订说明，没有修订或未启用时返回空字符串
+//   - EditHistoryLast: 一行 ✏️ Last edited by @x at T
+//   - EditHistoryFull: 以上述说明为标题的 <details>，依次列出原始正文和每次修订的 diff
+func renderEditHistory(edits []github.Edit, opts *Options) string {
+	if len(edits) == 0 {
+		return ""
+	}
+	switch opts.EditHistory {
+	case EditHistoryLast:
+		return "✏️ " + lastEditedText(edits)
+	case EditHistoryFull:
+	default:
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<details>\n<summary>✏️ %s</summary>\n\n", editSummary(edits))
+	for _, r := range revisions(edits) {
+		if r.Text == "" && !r.IsDiff {
+			fmt.Fprintf(&b, "%s\n\n", r.Label)
+			continue
+		}
+		lang := "markdown"
+		if r.IsDiff {
+			lang = "diff"
+		}
+		fence := codeFence(r.Text)
+		fmt.Fprintf(&b, "%s:\n\n%s%s\n%s\n%s\n\n", r.Label, fence, lang, r.Text, fence)
+	}
+	b.WriteString("</details>")
+	return b.String()
+}
+
+// maxDiffCells lineDiff 计算最长公共子序列时表格的最大单元数，超过时不逐行比较
+const maxDiffCells = 1 << 20
+
+// lineDiff 按行比较两个版本，返回完整的行级 diff：未改动的行以空格开头，删除的行以 - 开头，新增的行以 + 开头
+// 先去掉相同的开头和结尾；剩余部分过大（超过 maxDiffCells）时整体显示为删除旧行、新增新行
+func lineDiff(before, after string) string {
+	a, b := splitLines(before), splitLines(after)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var lines []string
+	for _, line := range a[:prefix] {
+		lines = append(lines, " "+line)
+	}
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, " "+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffMiddle 按最长公共子序列比较 a 和 b，表格过大时整体显示为删除 a、新增 b
+func diffMiddle(a, b []string) []string {
+	var lines []string
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, "-"+line)
+		}
+		for _, line := range b {
+			lines = append(lines, "+"+line)
+		}
+		return lines
+	}
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+	return lines
+}
+
+// splitLines 按行拆分文本，忽略末尾的换行，空文本返回 nil
+func splitLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,170 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		expected string
+	}{
+		{name: "相同", before: "a\nb", after: "a\nb\n", expected: " a\n b"},
+		{name: "修改一行", before: "a\nb\nc", after: "a\nB\nc", expected: " a\n-b\n+B\n c"},
+		{name: "新增和删除", before: "a\nb", after: "b\nc", expected: "-a\n b\n+c"},
+		{name: "从空正文开始", before: "", after: "a", expected: "+a"},
+		{name: "重复的行", before: "a\nx\na", after: "a\na", expected: " a\n-x\n a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := lineDiff(tt.before, tt.after); result != tt.expected {
+				t.Errorf("lineDiff() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestLineDiffLarge 测试大段修改时只比较不同的部分，超过限制时整体显示为删除和新增
+func TestLineDiffLarge(t *testing.T) {
+	var before, after []string
+	for i := 0; i < 3000; i++ {
+		before = append(before, fmt.Sprintf("old %d", i))
+		after = append(after, fmt.Sprintf("new %d", i))
+	}
+	same := strings.Repeat("same\n", 5000)
+
+	result := lineDiff(same+strings.Join(before, "\n")+"\nend", same+strings.Join(after, "\n")+"\nend")
+	lines := strings.Split(result, "\n")
+	if len(lines) != 5000+6000+1 {
+		t.Fatalf("lineDiff() returned %d lines, want %d", len(lines), 5000+6000+1)
+	}
+	if lines[4999] != " same" || lines[5000] != "-old 0" || lines[8000] != "+new 0" || lines[len(lines)-1] != " end" {
+		t.Errorf("lineDiff() = %q ... %q, want unchanged lines around the removed and added block", lines[4999:5001], lines[len(lines)-1])
+	}
+}
+
+func TestRenderEditHistory(t *testing.T) {
+	edits := []github.Edit{
+		{Editor: "octocat", EditedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Body: "Crash on start\r\n"},
+		{Editor: "hubot", EditedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), Deleted: true},
+		{EditedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC), Body: "Crash on exit"},
+	}
+
+	tests := []struct {
+		name     string
+		edits    []github.Edit
+		mode     EditHistoryMode
+		expected string
+	}{
+		{name: "未启用", edits: edits, mode: EditHistoryNone, expected: ""},
+		{name: "默认不展示", edits: edits, expected: ""},
+		{name: "没有修订", mode: EditHistoryFull, expected: ""},
+		{name: "最后一次修订", edits: edits[:2], mode: EditHistoryLast, expected: "✏️ Last edited by @hubot at 2024-01-02T10:00:00Z"},
+		{name: "编辑者未知", edits: edits, mode: EditHistoryLast, expected: "✏️ Last edited at 2024-01-03T10:00:00Z"},
+		{
+			name:  "完整修订历史",
+			edits: edits,
+			mode:  EditHistoryFull,
+			expected: "<details>\n<summary>✏️ Last edited at 2024-01-03T10:00:00Z (3 revisions)</summary>\n\n" +
+				"Original by @octocat at 2024-01-01T10:00:00Z:\n\n```markdown\nCrash on start\n```\n\n" +
+				"Edited by @hubot at 2024-01-02T10:00:00Z (revision deleted)\n\n" +
+				"Edited at 2024-01-03T10:00:00Z:\n\n```diff\n-Crash on start\n+Crash on exit\n```\n\n" +
+				"</details>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := renderEditHistory(tt.edits, &Options{EditHistory: tt.mode}); result != tt.expected {
+				t.Errorf("renderEditHistory() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertEditHistory(t *testing.T) {
+	edits := []github.Edit{
+		{Editor: "octocat", EditedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Body: "Crash"},
+		{Editor: "octocat", EditedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), Body: "Crash on <exit>"},
+	}
+	doc := IssueDocument(&github.Issue{
+		Thread: github.Thread{
+			Title:  "Crash",
+			Status: "open",
+			Body:   "Crash on <exit>",
+			Edits:  edits,
+			Comments: []github.Comment{
+				{Author: "user1", Body: "Same here", Edits: []github.Edit{{Editor: "user1", EditedAt: time.Date(2024, 1, 4, 10, 0, 0, 0, time.UTC), Body: "Same here"}}},
+				{Author: "user2", Body: "+1"},
+			},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		opts       *Options
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "Markdown 未启用",
+			opts:       &Options{Format: FormatMarkdown},
+			unexpected: []string{"✏️"},
+		},
+		{
+			name: "Markdown 最后一次修订",
+			opts: &Options{Format: FormatMarkdown, EditHistory: EditHistoryLast},
+			expected: []string{
+				"Crash on <exit>\n✏️ Last edited by @octocat at 2024-01-02T10:00:00Z\n",
+				"Same here\n✏️ Last edited by @user1 at 2024-01-04T10:00:00Z\n",
+			},
+		},
+		{
+			name:     "Markdown 完整修订历史",
+			opts:     &Options{Format: FormatMarkdown, EditHistory: EditHistoryFull},
+			expected: []string{"<summary>✏️ Last edited by @octocat at 2024-01-02T10:00:00Z (2 revisions)</summary>", "```diff\n-Crash\n+Crash on <exit>\n```"},
+		},
+		{
+			name:     "HTML 最后一次修订",
+			opts:     &Options{Format: FormatHTML, EditHistory: EditHistoryLast},
+			expected: []string{`<p class="edited">✏️ Last edited by @octocat at 2024-01-02T10:00:00Z</p>`},
+		},
+		{
+			name:     "HTML 完整修订历史",
+			opts:     &Options{Format: FormatHTML, EditHistory: EditHistoryFull},
+			expected: []string{`<details class="edits">`, "<pre><code>Crash</code></pre>", "<pre class=\"diff\"><code>-Crash\n", "Crash on &lt;exit&gt;</code></pre>"},
+		},
+		{
+			name:     "JSON",
+			opts:     &Options{Format: FormatJSON},
+			expected: []string{`"editor": "octocat"`, `"edited_at": "2024-01-02T10:00:00Z"`, `"body": "Crash on <exit>"`, `"editor": "user1"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(doc, tt.opts)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(result), expected) {
+					t.Errorf("Convert() missing %q, got:\n%s", expected, result)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(string(result), unexpected) {
+					t.Errorf("Convert() should not contain %q, got:\n%s", unexpected, result)
+				}
+			}
+		})
+	}
+}
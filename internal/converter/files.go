@@ -0,0 +1,84 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// DefaultMaxDiffLines 每个文件的 diff 默认最多显示的行数
+const DefaultMaxDiffLines = 500
+
+// diffTooLargeNotice diff 超出 GitHub 大小限制时的提示
+const diffTooLargeNotice = "Diff not included: too large for the GitHub API"
+
+// filesSummary 返回变更文件的统计，如 2 files changed, 12 additions, 4 deletions
+func filesSummary(files []github.ChangedFile) string {
+	additions, deletions := 0, 0
+	for _, f := range files {
+		additions += f.Additions
+		deletions += f.Deletions
+	}
+	return fmt.Sprintf("%s changed, %s, %s",
+		plural(len(files), "file"), plural(additions, "addition"), plural(deletions, "deletion"))
+}
+
+// plural 返回带数量的名词，数量不为 1 时使用复数，如 1 file、2 files
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// renderFileTable 将变更文件渲染为统计行和 Markdown 表格
+func renderFileTable(files []github.ChangedFile) string {
+	var b strings.Builder
+	b.WriteString(filesSummary(files) + "\n\n")
+	b.WriteString("| File | Change | Additions | Deletions |\n")
+	b.WriteString("| --- | --- | ---: | ---: |\n")
+	for _, f := range files {
+		fmt.Fprintf(&b, "| `%s` | %s | +%d | -%d |\n",
+			strings.ReplaceAll(f.Path, "|", `\|`), changeTypeText(f.ChangeType), f.Additions, f.Deletions)
+	}
+	return b.String()
+}
+
+// changeTypeText 返回变更类型的显示文本，如 MODIFIED → modified
+func changeTypeText(changeType string) string {
+	return strings.ToLower(changeType)
+}
+
+// truncatePatch 按行数截断补丁，maxLines 为 0 时不截断
+// 返回截断后的补丁、显示的行数和原始行数
+func truncatePatch(patch string, maxLines int) (string, int, int) {
+	lines := strings.Split(patch, "\n")
+	if maxLines <= 0 || len(lines) <= maxLines {
+		return patch, len(lines), len(lines)
+	}
+	return strings.Join(lines[:maxLines], "\n"), maxLines, len(lines)
+}
+
+// diffNotice 返回 diff 被截断的提示，未截断时返回空字符串
+func diffNotice(shown, total int) string {
+	if shown >= total {
+		return ""
+	}
+	return fmt.Sprintf("Diff truncated: showing %d of %d lines (max-diff-lines limit)", shown, total)
+}
+
+// renderDiff 将文件的补丁渲染为 diff 代码块，超过 maxLines 时截断并附加提示
+// 没有补丁（未获取 diff、二进制文件或纯重命名）时返回空字符串
+func renderDiff(f github.ChangedFile, maxLines int) string {
+	if f.Patch == "" {
+		return ""
+	}
+	patch, shown, total := truncatePatch(f.Patch, maxLines)
+	fence := codeFence(patch)
+	result := fence + "diff\n" + patch + "\n" + fence + "\n"
+	if notice := diffNotice(shown, total); notice != "" {
+		result += "\n> " + notice + "\n"
+	}
+	return result
+}
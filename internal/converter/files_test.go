@@ -0,0 +1,107 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestRenderDiff(t *testing.T) {
+	patch := "@@ -1,2 +1,3 @@\n-a\n+b\n+c"
+
+	tests := []struct {
+		name     string
+		file     github.ChangedFile
+		maxLines int
+		expected string
+	}{
+		{name: "没有补丁", file: github.ChangedFile{Path: "logo.png"}, maxLines: 10, expected: ""},
+		{name: "未超过限制", file: github.ChangedFile{Patch: patch}, maxLines: 4, expected: "```diff\n" + patch + "\n```\n"},
+		{name: "不限制", file: github.ChangedFile{Patch: patch}, maxLines: 0, expected: "```diff\n" + patch + "\n```\n"},
+		{
+			name:     "截断",
+			file:     github.ChangedFile{Patch: patch},
+			maxLines: 2,
+			expected: "```diff\n@@ -1,2 +1,3 @@\n-a\n```\n\n> Diff truncated: showing 2 of 4 lines (max-diff-lines limit)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := renderDiff(tt.file, tt.maxLines); result != tt.expected {
+				t.Errorf("renderDiff() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilesSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []github.ChangedFile
+		expected string
+	}{
+		{name: "单个文件", files: []github.ChangedFile{{Additions: 1, Deletions: 0}}, expected: "1 file changed, 1 addition, 0 deletions"},
+		{name: "多个文件", files: []github.ChangedFile{{Additions: 3, Deletions: 1}, {Additions: 2}}, expected: "2 files changed, 5 additions, 1 deletion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := filesSummary(tt.files); result != tt.expected {
+				t.Errorf("filesSummary() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertFiles(t *testing.T) {
+	doc := PullRequestDocument(&github.PullRequest{
+		Thread: github.Thread{Title: "Add retry", Status: "open"},
+		Files: []github.ChangedFile{
+			{Path: "a|b.go", ChangeType: "MODIFIED", Additions: 2, Deletions: 1, Patch: "@@ -1 +1,2 @@\n-a\n+<b>\n+c"},
+			{Path: "logo.png", ChangeType: "ADDED"},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		format   Format
+		expected []string
+	}{
+		{
+			name:     "Markdown",
+			format:   FormatMarkdown,
+			expected: []string{"| `a\\|b.go` | modified | +2 | -1 |\n", "### `a|b.go`\n\n```diff\n@@ -1 +1,2 @@\n-a\n```\n\n> Diff truncated: showing 2 of 4 lines"},
+		},
+		{
+			name:   "HTML",
+			format: FormatHTML,
+			expected: []string{
+				`<td><a href="#file-1"><code>a|b.go</code></a></td><td>modified</td>`,
+				`<td><code>logo.png</code></td><td>added</td>`,
+				"\n-a</code></pre>",
+				`<p class="notice">Diff truncated: showing 2 of 4 lines (max-diff-lines limit)</p>`,
+			},
+		},
+		{
+			name:     "JSON",
+			format:   FormatJSON,
+			expected: []string{`"change_type": "MODIFIED"`, `"patch": "@@ -1 +1,2 @@\n-a"`, `"patch_truncated": true`, `"diff_too_large": false`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(doc, &Options{Format: tt.format, MaxDiffLines: 2})
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(result), expected) {
+					t.Errorf("Convert() missing %q, got:\n%s", expected, result)
+				}
+			}
+		})
+	}
+}
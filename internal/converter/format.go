@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// Format 输出格式
+type Format string
+
+// 支持的输出格式
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+)
+
+// Extension 返回输出格式对应的文件扩展名（不含点）
+func (f Format) Extension() string {
+	switch f {
+	case FormatHTML:
+		return "html"
+	case FormatJSON:
+		return "json"
+	default:
+		return "md"
+	}
+}
+
+// Convert 按 opts.AnchorMode 处理锚点评论后，按 opts.Format 渲染 Document，所有资源类型都经过这里输出
+func Convert(doc *Document, opts *Options) ([]byte, error) {
+	doc, err := Prepare(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Format {
+	case FormatMarkdown, "":
+		return renderMarkdown(doc, opts)
+	case FormatHTML:
+		return renderHTML(doc, opts)
+	case FormatJSON:
+		return renderJSON(doc, opts)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", opts.Format)
+	}
+}
+
+// Prepare 按 opts.AnchorMode 和 opts.Minimized 筛选评论，返回处理后的文档，不修改 doc
+// Convert 会自动调用；需要在渲染前处理实际输出的内容（如下载图片）时可先调用，已处理过的文档原样返回
+func Prepare(doc *Document, opts *Options) (*Document, error) {
+	if doc.prepared {
+		return doc, nil
+	}
+
+	doc, err := applyAnchor(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := *applyMinimized(doc, opts)
+	result.prepared = true
+	return &result, nil
+}
+
+// ConvertIssue 按 opts.Format 将 Issue 转换为对应格式
+func ConvertIssue(issue *github.Issue, opts *Options) ([]byte, error) {
+	return Convert(IssueDocument(issue), opts)
+}
+
+// ConvertPullRequest 按 opts.Format 将 PullRequest 转换为对应格式
+func ConvertPullRequest(pr *github.PullRequest, opts *Options) ([]byte, error) {
+	return Convert(PullRequestDocument(pr), opts)
+}
+
+// ConvertDiscussion 按 opts.Format 将 Discussion 转换为对应格式
+func ConvertDiscussion(discussion *github.Discussion, opts *Options) ([]byte, error) {
+	return Convert(DiscussionDocument(discussion), opts)
+}
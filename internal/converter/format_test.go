@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestConvertIssue(t *testing.T) {
+	issue := &github.Issue{Thread: github.Thread{Title: "Test", Author: "octocat", Status: "open"}}
+
+	tests := []struct {
+		name           string
+		format         Format
+		expectedPrefix string
+		expectError    bool
+	}{
+		{name: "默认为 Markdown", format: "", expectedPrefix: "---\n"},
+		{name: "Markdown", format: FormatMarkdown, expectedPrefix: "---\n"},
+		{name: "HTML", format: FormatHTML, expectedPrefix: "<!DOCTYPE html>"},
+		{name: "JSON", format: FormatJSON, expectedPrefix: "{\n  \"schema_version\": 1,"},
+		{name: "不支持的格式", format: "pdf", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ConvertIssue(issue, &Options{Format: tt.format})
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ConvertIssue() with format %q expected error, got nil", tt.format)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ConvertIssue() unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(string(result), tt.expectedPrefix) {
+				t.Errorf("ConvertIssue() with format %q = %q, want prefix %q", tt.format, result, tt.expectedPrefix)
+			}
+		})
+	}
+}
+
+func TestFormatExtension(t *testing.T) {
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{format: FormatMarkdown, expected: "md"},
+		{format: FormatHTML, expected: "html"},
+		{format: FormatJSON, expected: "json"},
+		{format: "", expected: "md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			if result := tt.format.Extension(); result != tt.expected {
+				t.Errorf("Format(%q).Extension() = %q, want %q", tt.format, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	doc := IssueDocument(&github.Issue{
+		Thread: github.Thread{
+			Title: "Crash",
+			Body:  "Body",
+			Comments: []github.Comment{
+				{Author: "user1", Body: "Same here", URL: "https://github.com/owner/repo/issues/1#issuecomment-1"},
+				{Author: "spammer", Body: "Buy now", IsMinimized: true},
+				{Author: "user2", Body: "Fixed", URL: "https://github.com/owner/repo/issues/1#issuecomment-3"},
+			},
+		},
+	})
+	doc.Anchor = "issuecomment-3"
+	opts := &Options{AnchorMode: AnchorComment, AnchorContext: 1, Minimized: MinimizedOmit}
+
+	prepared, err := Prepare(doc, opts)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if len(prepared.Comments) != 1 || prepared.Comments[0].Author != "user2" || prepared.Body != "" {
+		t.Errorf("Prepare() comments = %+v, body = %q, want only the anchored comment", prepared.Comments, prepared.Body)
+	}
+	if len(doc.Comments) != 3 || doc.Body != "Body" {
+		t.Errorf("Prepare() should not modify doc, got %+v", doc)
+	}
+
+	// 已处理过的文档原样返回，Convert 不会再次筛选
+	again, err := Prepare(prepared, opts)
+	if err != nil || again != prepared {
+		t.Errorf("Prepare() on a prepared document = %p, %v, want %p", again, err, prepared)
+	}
+	want, err := Convert(doc, opts)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	got, err := Convert(prepared, opts)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Convert(prepared) = %q, want %q", got, want)
+	}
+}
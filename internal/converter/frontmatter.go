@@ -1,28 +1,412 @@
 package converter
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 )
 
-// generateFrontmatter 生成 YAML Frontmatter
-func generateFrontmatter(title, url, author, authorURL string, createdAt time.Time, status, typ string) string {
+// FrontmatterFormat Frontmatter 格式
+type FrontmatterFormat string
+
+// 支持的 Frontmatter 格式
+const (
+	FrontmatterYAML FrontmatterFormat = "yaml" // --- 包裹的 YAML（默认）
+	FrontmatterTOML FrontmatterFormat = "toml" // +++ 包裹的 TOML，适用于 Hugo
+	FrontmatterJSON FrontmatterFormat = "json" // 位于文件开头的 JSON 对象
+	FrontmatterNone FrontmatterFormat = "none" // 不输出 Frontmatter
+)
+
+// FrontmatterField 要输出的 Frontmatter 字段，Key 为输出时使用的键名
+type FrontmatterField struct {
+	Name string
+	Key  string
+}
+
+// FrontmatterFieldNames 返回所有可用的 Frontmatter 字段名
+func FrontmatterFieldNames() []string {
+	return []string{
+		"title", "url", "author", "author_url", "created_at", "status", "type",
+		"labels", "assignees", "milestone", "closed_at", "updated_at", "state_reason", "locked",
+		"draft", "merged_at", "merged_by", "base_ref", "head_ref", "requested_reviewers",
+		"category", "upvotes", "answered", "answer_chosen_at", "answer_chosen_by", "poll_question", "poll_votes",
+		"comments_total", "comments_truncated", "participants", "aliases",
+	}
+}
+
+// defaultFrontmatterFields 未指定字段时输出的字段
+func defaultFrontmatterFields() []FrontmatterField {
+	var fields []FrontmatterField
+	for _, name := range []string{"title", "url", "author", "author_url", "created_at", "status", "type"} {
+		fields = append(fields, FrontmatterField{Name: name, Key: name})
+	}
+	return fields
+}
+
+// ParseFrontmatterFields 解析逗号分隔的字段列表，每项为 name 或 name:key（重命名）
+// 如 "title,url,labels:tags,aliases"
+func ParseFrontmatterFields(spec string) ([]FrontmatterField, error) {
+	var fields []FrontmatterField
+	keys := make(map[string]bool)
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, key, renamed := strings.Cut(item, ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !renamed {
+			key = name
+		}
+
+		if !isFrontmatterField(name) {
+			return nil, fmt.Errorf("unknown frontmatter field: %s (available: %s)", name, strings.Join(FrontmatterFieldNames(), ", "))
+		}
+		if !isFrontmatterKey(key) {
+			return nil, fmt.Errorf("invalid frontmatter key: %q (use letters, digits, '_' or '-')", key)
+		}
+		if keys[key] {
+			return nil, fmt.Errorf("duplicate frontmatter key: %s", key)
+		}
+		keys[key] = true
+
+		fields = append(fields, FrontmatterField{Name: name, Key: key})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no frontmatter fields specified")
+	}
+	return fields, nil
+}
+
+// isFrontmatterField 判断字段名是否可用
+func isFrontmatterField(name string) bool {
+	for _, n := range FrontmatterFieldNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isFrontmatterKey 判断键名是否在 YAML、TOML 和 JSON 中都无需引号
+func isFrontmatterKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// frontmatterEntry 待输出的键值对，Value 为 string、time.Time、[]string、int 或 bool
+type frontmatterEntry struct {
+	Key   string
+	Value any
+}
+
+// renderFrontmatter 按格式渲染 Frontmatter
+// fields 为空时输出默认字段，评论被截断时额外追加 comments_total 和 comments_truncated
+func renderFrontmatter(doc *Document, format FrontmatterFormat, fields []FrontmatterField) string {
+	if len(fields) == 0 {
+		fields = defaultFrontmatterFields()
+		if doc.CommentsTruncated {
+			fields = append(fields,
+				FrontmatterField{Name: "comments_total", Key: "comments_total"},
+				FrontmatterField{Name: "comments_truncated", Key: "comments_truncated"},
+			)
+		}
+	}
+
+	var entries []frontmatterEntry
+	for _, f := range fields {
+		if value := frontmatterValue(doc, f.Name); value != nil {
+			entries = append(entries, frontmatterEntry{Key: f.Key, Value: value})
+		}
+	}
+
+	switch format {
+	case FrontmatterNone:
+		return ""
+	case FrontmatterTOML:
+		return renderTOMLFrontmatter(entries)
+	case FrontmatterJSON:
+		return renderJSONFrontmatter(entries)
+	default:
+		return renderYAMLFrontmatter(entries)
+	}
+}
+
+// frontmatterValue 返回字段的值
+// 没有值的可选字段（milestone、closed_at、merged_by 等）以及不适用于该类型的 Pull Request、Discussion 字段返回 nil
+func frontmatterValue(doc *Document, name string) any {
+	switch name {
+	case "title":
+		return doc.Title
+	case "url":
+		return doc.URL
+	case "author":
+		return doc.Author
+	case "author_url":
+		return doc.AuthorURL
+	case "created_at":
+		return doc.CreatedAt
+	case "status":
+		return doc.Status
+	case "type":
+		return doc.Type
+	case "labels":
+		return nonNil(doc.Labels)
+	case "assignees":
+		return nonNil(doc.Assignees)
+	case "milestone":
+		if doc.Milestone == nil {
+			return nil
+		}
+		return doc.Milestone.Title
+	case "closed_at":
+		if doc.ClosedAt.IsZero() {
+			return nil
+		}
+		return doc.ClosedAt
+	case "updated_at":
+		if doc.UpdatedAt.IsZero() {
+			return nil
+		}
+		return doc.UpdatedAt
+	case "state_reason":
+		if doc.StateReason == "" {
+			return nil
+		}
+		return doc.StateReason
+	case "locked":
+		return doc.Locked
+	case "draft":
+		if doc.Type != "pull_request" {
+			return nil
+		}
+		return doc.IsDraft
+	case "merged_at":
+		if doc.MergedAt.IsZero() {
+			return nil
+		}
+		return doc.MergedAt
+	case "merged_by":
+		if doc.MergedBy == "" {
+			return nil
+		}
+		return doc.MergedBy
+	case "base_ref":
+		if doc.BaseRef == "" {
+			return nil
+		}
+		return doc.BaseRef
+	case "head_ref":
+		if doc.HeadRef == "" {
+			return nil
+		}
+		return doc.HeadRef
+	case "requested_reviewers":
+		if doc.Type != "pull_request" {
+			return nil
+		}
+		return nonNil(doc.RequestedReviewers)
+	case "category":
+		if doc.Category == nil {
+			return nil
+		}
+		return doc.Category.Name
+	case "upvotes":
+		if doc.Type != "discussion" {
+			return nil
+		}
+		return doc.UpvoteCount
+	case "answered":
+		// 只有 Q&A 类分类可以采纳答案
+		if doc.Category == nil || !doc.Category.IsAnswerable {
+			return nil
+		}
+		return doc.Answer != nil
+	case "answer_chosen_at":
+		if doc.AnswerChosenAt.IsZero() {
+			return nil
+		}
+		return doc.AnswerChosenAt
+	case "answer_chosen_by":
+		if doc.AnswerChosenBy == "" {
+			return nil
+		}
+		return doc.AnswerChosenBy
+	case "poll_question":
+		if doc.Poll == nil {
+			return nil
+		}
+		return doc.Poll.Question
+	case "poll_votes":
+		if doc.Poll == nil {
+			return nil
+		}
+		return doc.Poll.TotalVotes
+	case "comments_total":
+		return doc.TotalComments
+	case "comments_truncated":
+		return doc.CommentsTruncated
+	case "participants":
+		return participants(doc)
+	case "aliases":
+		return aliases(doc.URL)
+	default:
+		return nil
+	}
+}
+
+// nonNil 将 nil 列表转换为空列表，使其输出为 []
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// participants 返回作者、评论者（含 Discussion 回复的作者）、答案作者和采纳者以及 Reviewer，按首次出现的顺序去重
+func participants(doc *Document) []string {
+	logins := []string{}
+	seen := make(map[string]bool)
+	add := func(login string) {
+		if login != "" && !seen[login] {
+			seen[login] = true
+			logins = append(logins, login)
+		}
+	}
+
+	add(doc.Author)
+	for _, comment := range doc.Comments {
+		add(comment.Author)
+		for _, reply := range comment.Replies {
+			add(reply.Author)
+		}
+	}
+	if doc.Answer != nil {
+		add(doc.Answer.Author)
+	}
+	add(doc.AnswerChosenBy)
+	for _, review := range doc.Reviews {
+		add(review.Author)
+	}
+	for _, thread := range doc.ReviewThreads {
+		for _, comment := range thread.Comments {
+			add(comment.Author)
+		}
+	}
+	return logins
+}
+
+// aliases 返回资源的短引用，如 owner/repo#123，无法识别 URL 时返回空列表
+func aliases(rawURL string) []string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return []string{}
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 {
+		return []string{}
+	}
+	return []string{fmt.Sprintf("%s/%s#%s", parts[0], parts[1], parts[3])}
+}
+
+// renderYAMLFrontmatter 渲染 --- 包裹的 YAML Frontmatter
+func renderYAMLFrontmatter(entries []frontmatterEntry) string {
 	var sb strings.Builder
 
 	sb.WriteString("---\n")
-	sb.WriteString(fmt.Sprintf("title: %s\n", quoteYAML(title)))
-	sb.WriteString(fmt.Sprintf("url: %s\n", quoteYAML(url)))
-	sb.WriteString(fmt.Sprintf("author: %s\n", quoteYAML(author)))
-	sb.WriteString(fmt.Sprintf("author_url: %s\n", quoteYAML(authorURL)))
-	sb.WriteString(fmt.Sprintf("created_at: %q\n", createdAt.UTC().Format(time.RFC3339)))
-	sb.WriteString(fmt.Sprintf("status: %s\n", quoteYAML(status)))
-	sb.WriteString(fmt.Sprintf("type: %s\n", quoteYAML(typ)))
+	for _, e := range entries {
+		var value string
+		switch v := e.Value.(type) {
+		case string:
+			value = quoteYAML(v)
+		case time.Time:
+			value = fmt.Sprintf("%q", v.UTC().Format(time.RFC3339))
+		case []string:
+			var items []string
+			for _, item := range v {
+				items = append(items, quoteYAML(item))
+			}
+			value = "[" + strings.Join(items, ", ") + "]"
+		default:
+			value = fmt.Sprint(v)
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", e.Key, value))
+	}
 	sb.WriteString("---\n")
 
 	return sb.String()
 }
 
+// renderTOMLFrontmatter 渲染 +++ 包裹的 TOML Frontmatter，时间使用 TOML 原生日期时间
+func renderTOMLFrontmatter(entries []frontmatterEntry) string {
+	var sb strings.Builder
+
+	sb.WriteString("+++\n")
+	for _, e := range entries {
+		var value string
+		switch v := e.Value.(type) {
+		case string:
+			value = quoteTOML(v)
+		case time.Time:
+			value = v.UTC().Format(time.RFC3339)
+		case []string:
+			var items []string
+			for _, item := range v {
+				items = append(items, quoteTOML(item))
+			}
+			value = "[" + strings.Join(items, ", ") + "]"
+		default:
+			value = fmt.Sprint(v)
+		}
+		sb.WriteString(fmt.Sprintf("%s = %s\n", e.Key, value))
+	}
+	sb.WriteString("+++\n")
+
+	return sb.String()
+}
+
+// renderJSONFrontmatter 渲染位于文件开头的 JSON 对象（Hugo 的 JSON Frontmatter）
+func renderJSONFrontmatter(entries []frontmatterEntry) string {
+	var sb strings.Builder
+
+	sb.WriteString("{\n")
+	for i, e := range entries {
+		value := e.Value
+		if t, ok := value.(time.Time); ok {
+			value = t.UTC().Format(time.RFC3339)
+		}
+
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		// 值只可能是字符串、字符串列表、数字和布尔值，编码不会失败
+		_ = enc.Encode(value)
+
+		sb.WriteString(fmt.Sprintf("  %q: %s", e.Key, strings.TrimSuffix(buf.String(), "\n")))
+		if i < len(entries)-1 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
 // quoteYAML 为 YAML 字符串添加引号
 func quoteYAML(s string) string {
 	// 如果字符串包含单引号，使用单引号包裹并转义单引号
@@ -32,3 +416,37 @@ func quoteYAML(s string) string {
 	// 否则使用双引号包裹（默认）
 	return fmt.Sprintf("%q", s)
 }
+
+// quoteTOML 返回 TOML 基本字符串（双引号），只使用 TOML 支持的转义序列
+func quoteTOML(s string) string {
+	var sb strings.Builder
+
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				sb.WriteString(fmt.Sprintf(`\u%04X`, r))
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+
+	return sb.String()
+}
@@ -4,29 +4,31 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
 )
 
 func TestGenerateFrontmatter(t *testing.T) {
 	tests := []struct {
-		name     string
-		title    string
-		url      string
-		author   string
+		name      string
+		title     string
+		url       string
+		author    string
 		authorURL string
 		createdAt time.Time
-		status   string
-		typ      string
-		expected string
+		status    string
+		typ       string
+		expected  string
 	}{
 		{
-			name:     "Issue Frontmatter",
-			title:    "Test Issue",
-			url:      "https://github.com/owner/repo/issues/123",
-			author:   "octocat",
+			name:      "Issue Frontmatter",
+			title:     "Test Issue",
+			url:       "https://github.com/owner/repo/issues/123",
+			author:    "octocat",
 			authorURL: "https://github.com/octocat",
 			createdAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-			status:   "open",
-			typ:      "issue",
+			status:    "open",
+			typ:       "issue",
 			expected: `---
 title: "Test Issue"
 url: "https://github.com/owner/repo/issues/123"
@@ -39,14 +41,14 @@ type: "issue"
 `,
 		},
 		{
-			name:     "PR Frontmatter",
-			title:    "Test PR",
-			url:      "https://github.com/owner/repo/pull/42",
-			author:   "octocat",
+			name:      "PR Frontmatter",
+			title:     "Test PR",
+			url:       "https://github.com/owner/repo/pull/42",
+			author:    "octocat",
 			authorURL: "https://github.com/octocat",
 			createdAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-			status:   "open",
-			typ:      "pull_request",
+			status:    "open",
+			typ:       "pull_request",
 			expected: `---
 title: "Test PR"
 url: "https://github.com/owner/repo/pull/42"
@@ -59,14 +61,14 @@ type: "pull_request"
 `,
 		},
 		{
-			name:     "Discussion Frontmatter",
-			title:    "Test Discussion",
-			url:      "https://github.com/owner/repo/discussions/7",
-			author:   "octocat",
+			name:      "Discussion Frontmatter",
+			title:     "Test Discussion",
+			url:       "https://github.com/owner/repo/discussions/7",
+			author:    "octocat",
 			authorURL: "https://github.com/octocat",
 			createdAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-			status:   "open",
-			typ:      "discussion",
+			status:    "open",
+			typ:       "discussion",
 			expected: `---
 title: "Test Discussion"
 url: "https://github.com/owner/repo/discussions/7"
@@ -79,14 +81,14 @@ type: "discussion"
 `,
 		},
 		{
-			name:     "Title with special characters",
-			title:    `Test "Issue" with 'quotes' and \backslashes\`,
-			url:      "https://github.com/owner/repo/issues/123",
-			author:   "octocat",
+			name:      "Title with special characters",
+			title:     `Test "Issue" with 'quotes' and \backslashes\`,
+			url:       "https://github.com/owner/repo/issues/123",
+			author:    "octocat",
 			authorURL: "https://github.com/octocat",
 			createdAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-			status:   "open",
-			typ:      "issue",
+			status:    "open",
+			typ:       "issue",
 			expected: `---
 title: 'Test "Issue" with ''quotes'' and \backslashes\'
 url: "https://github.com/owner/repo/issues/123"
@@ -102,14 +104,25 @@ type: "issue"
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := generateFrontmatter(tt.title, tt.url, tt.author, tt.authorURL, tt.createdAt, tt.status, tt.typ)
+			doc := &Document{
+				Type: tt.typ,
+				Thread: github.Thread{
+					Title:     tt.title,
+					URL:       tt.url,
+					Author:    tt.author,
+					AuthorURL: tt.authorURL,
+					CreatedAt: tt.createdAt,
+					Status:    tt.status,
+				},
+			}
+			result := renderFrontmatter(doc, FrontmatterYAML, nil)
 
 			if result != tt.expected {
 				// Split into lines for better error reporting
 				resultLines := strings.Split(result, "\n")
 				expectedLines := strings.Split(tt.expected, "\n")
 
-				t.Errorf("generateFrontmatter() output mismatch:\nGot:\n%s\n\nWant:\n%s", result, tt.expected)
+				t.Errorf("renderFrontmatter() output mismatch:\nGot:\n%s\n\nWant:\n%s", result, tt.expected)
 
 				// Find the first differing line
 				maxLines := len(resultLines)
@@ -133,3 +146,331 @@ type: "issue"
 		})
 	}
 }
+
+func TestRenderFrontmatterFormats(t *testing.T) {
+	doc := &Document{
+		Type: "issue",
+		Thread: github.Thread{
+			Title:     `Say "hi"`,
+			URL:       "https://github.com/owner/repo/issues/123",
+			Author:    "octocat",
+			CreatedAt: time.Date(2024, 1, 1, 20, 0, 0, 0, time.FixedZone("UTC+8", 8*3600)),
+			ClosedAt:  time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC),
+			Labels:    []string{"bug", "good first issue"},
+			Comments: []github.Comment{
+				{Author: "user1"},
+				{Author: "octocat"},
+				{Author: "user1"},
+			},
+			TotalComments: 3,
+		},
+		Milestone: &github.Milestone{Title: "v1.0"},
+	}
+	fields := []FrontmatterField{
+		{Name: "title", Key: "title"},
+		{Name: "created_at", Key: "date"},
+		{Name: "closed_at", Key: "closed_at"},
+		{Name: "labels", Key: "tags"},
+		{Name: "assignees", Key: "assignees"},
+		{Name: "milestone", Key: "milestone"},
+		{Name: "comments_total", Key: "comments"},
+		{Name: "participants", Key: "participants"},
+		{Name: "aliases", Key: "aliases"},
+	}
+
+	tests := []struct {
+		name     string
+		format   FrontmatterFormat
+		expected string
+	}{
+		{
+			name:   "YAML",
+			format: FrontmatterYAML,
+			expected: `---
+title: "Say \"hi\""
+date: "2024-01-01T12:00:00Z"
+closed_at: "2024-01-05T12:00:00Z"
+tags: ["bug", "good first issue"]
+assignees: []
+milestone: "v1.0"
+comments: 3
+participants: ["octocat", "user1"]
+aliases: ["owner/repo#123"]
+---
+`,
+		},
+		{
+			name:   "TOML",
+			format: FrontmatterTOML,
+			expected: `+++
+title = "Say \"hi\""
+date = 2024-01-01T12:00:00Z
+closed_at = 2024-01-05T12:00:00Z
+tags = ["bug", "good first issue"]
+assignees = []
+milestone = "v1.0"
+comments = 3
+participants = ["octocat", "user1"]
+aliases = ["owner/repo#123"]
++++
+`,
+		},
+		{
+			name:   "JSON",
+			format: FrontmatterJSON,
+			expected: `{
+  "title": "Say \"hi\"",
+  "date": "2024-01-01T12:00:00Z",
+  "closed_at": "2024-01-05T12:00:00Z",
+  "tags": ["bug","good first issue"],
+  "assignees": [],
+  "milestone": "v1.0",
+  "comments": 3,
+  "participants": ["octocat","user1"],
+  "aliases": ["owner/repo#123"]
+}
+`,
+		},
+		{
+			name:     "None",
+			format:   FrontmatterNone,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := renderFrontmatter(doc, tt.format, fields)
+			if result != tt.expected {
+				t.Errorf("renderFrontmatter() =\n%s\nwant:\n%s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderFrontmatterOmitsEmptyOptionalFields(t *testing.T) {
+	fields := []FrontmatterField{
+		{Name: "title", Key: "title"},
+		{Name: "milestone", Key: "milestone"},
+		{Name: "closed_at", Key: "closed_at"},
+		{Name: "updated_at", Key: "updated_at"},
+		{Name: "state_reason", Key: "state_reason"},
+		{Name: "draft", Key: "draft"},
+		{Name: "merged_at", Key: "merged_at"},
+		{Name: "merged_by", Key: "merged_by"},
+		{Name: "base_ref", Key: "base_ref"},
+		{Name: "head_ref", Key: "head_ref"},
+		{Name: "requested_reviewers", Key: "requested_reviewers"},
+		{Name: "category", Key: "category"},
+		{Name: "upvotes", Key: "upvotes"},
+		{Name: "answered", Key: "answered"},
+		{Name: "answer_chosen_at", Key: "answer_chosen_at"},
+		{Name: "answer_chosen_by", Key: "answer_chosen_by"},
+		{Name: "poll_question", Key: "poll_question"},
+		{Name: "poll_votes", Key: "poll_votes"},
+	}
+
+	result := renderFrontmatter(&Document{Thread: github.Thread{Title: "Open"}}, FrontmatterYAML, fields)
+	expected := "---\ntitle: \"Open\"\n---\n"
+	if result != expected {
+		t.Errorf("renderFrontmatter() = %q, want %q", result, expected)
+	}
+}
+
+func TestRenderFrontmatterPullRequestFields(t *testing.T) {
+	doc := PullRequestDocument(&github.PullRequest{
+		Thread: github.Thread{
+			Title:     "Add retry",
+			UpdatedAt: time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC),
+			Locked:    true,
+		},
+		IsDraft:            false,
+		MergedAt:           time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC),
+		MergedBy:           "maintainer",
+		BaseRef:            "main",
+		HeadRef:            "feature/retry",
+		RequestedReviewers: []string{"owner/core"},
+	})
+	fields := []FrontmatterField{
+		{Name: "updated_at", Key: "updated_at"},
+		{Name: "locked", Key: "locked"},
+		{Name: "draft", Key: "draft"},
+		{Name: "merged_at", Key: "merged_at"},
+		{Name: "merged_by", Key: "merged_by"},
+		{Name: "base_ref", Key: "base"},
+		{Name: "head_ref", Key: "head"},
+		{Name: "requested_reviewers", Key: "reviewers"},
+	}
+
+	result := renderFrontmatter(doc, FrontmatterYAML, fields)
+	expected := `---
+updated_at: "2024-02-04T12:00:00Z"
+locked: true
+draft: false
+merged_at: "2024-02-03T12:00:00Z"
+merged_by: "maintainer"
+base: "main"
+head: "feature/retry"
+reviewers: ["owner/core"]
+---
+`
+	if result != expected {
+		t.Errorf("renderFrontmatter() =\n%s\nwant:\n%s", result, expected)
+	}
+}
+
+func TestRenderFrontmatterDiscussionFields(t *testing.T) {
+	fields := []FrontmatterField{
+		{Name: "category", Key: "category"},
+		{Name: "upvotes", Key: "upvotes"},
+		{Name: "answered", Key: "answered"},
+		{Name: "answer_chosen_at", Key: "answer_chosen_at"},
+		{Name: "answer_chosen_by", Key: "answer_chosen_by"},
+		{Name: "poll_question", Key: "poll_question"},
+		{Name: "poll_votes", Key: "poll_votes"},
+	}
+
+	tests := []struct {
+		name       string
+		discussion *github.Discussion
+		expected   string
+	}{
+		{
+			name: "已采纳答案",
+			discussion: &github.Discussion{
+				Category:       &github.DiscussionCategory{Name: "Q&A", IsAnswerable: true},
+				UpvoteCount:    3,
+				Answer:         &github.Comment{Author: "expert"},
+				AnswerChosenAt: time.Date(2024, 4, 3, 8, 0, 0, 0, time.UTC),
+				AnswerChosenBy: "octocat",
+				Poll:           &github.Poll{Question: "A or B?", TotalVotes: 5},
+			},
+			expected: "---\ncategory: \"Q&A\"\nupvotes: 3\nanswered: true\nanswer_chosen_at: \"2024-04-03T08:00:00Z\"\n" +
+				"answer_chosen_by: \"octocat\"\npoll_question: \"A or B?\"\npoll_votes: 5\n---\n",
+		},
+		{
+			name:       "未采纳答案",
+			discussion: &github.Discussion{Category: &github.DiscussionCategory{Name: "Q&A", IsAnswerable: true}},
+			expected:   "---\ncategory: \"Q&A\"\nupvotes: 0\nanswered: false\n---\n",
+		},
+		{
+			name:       "不可采纳答案的分类",
+			discussion: &github.Discussion{Category: &github.DiscussionCategory{Name: "Ideas"}},
+			expected:   "---\ncategory: \"Ideas\"\nupvotes: 0\n---\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := renderFrontmatter(DiscussionDocument(tt.discussion), FrontmatterYAML, fields)
+			if result != tt.expected {
+				t.Errorf("renderFrontmatter() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseFrontmatterFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected []FrontmatterField
+		wantErr  string
+	}{
+		{
+			name: "字段和重命名",
+			spec: "title, labels:tags,aliases",
+			expected: []FrontmatterField{
+				{Name: "title", Key: "title"},
+				{Name: "labels", Key: "tags"},
+				{Name: "aliases", Key: "aliases"},
+			},
+		},
+		{name: "未知字段", spec: "title,nope", wantErr: "unknown frontmatter field: nope"},
+		{name: "非法键名", spec: "title:my title", wantErr: "invalid frontmatter key"},
+		{name: "空键名", spec: "title:", wantErr: "invalid frontmatter key"},
+		{name: "重复键名", spec: "labels:tags,assignees:tags", wantErr: "duplicate frontmatter key: tags"},
+		{name: "空列表", spec: " , ", wantErr: "no frontmatter fields specified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseFrontmatterFields(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseFrontmatterFields(%q) error = %v, want containing %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFrontmatterFields(%q) error = %v", tt.spec, err)
+			}
+			if len(fields) != len(tt.expected) {
+				t.Fatalf("ParseFrontmatterFields(%q) = %v, want %v", tt.spec, fields, tt.expected)
+			}
+			for i := range fields {
+				if fields[i] != tt.expected[i] {
+					t.Errorf("fields[%d] = %v, want %v", i, fields[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteTOML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "plain", expected: `"plain"`},
+		{input: `a "b" \c`, expected: `"a \"b\" \\c"`},
+		{input: "line1\nline2\ttab", expected: `"line1\nline2\ttab"`},
+		{input: "bell\a", expected: `"bell\u0007"`},
+		{input: "中文 ✅", expected: `"中文 ✅"`},
+	}
+
+	for _, tt := range tests {
+		if result := quoteTOML(tt.input); result != tt.expected {
+			t.Errorf("quoteTOML(%q) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestParticipants(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      *Document
+		expected []string
+	}{
+		{
+			name: "Discussion 回复",
+			doc: DiscussionDocument(&github.Discussion{
+				Thread: github.Thread{
+					Author: "octocat",
+					Comments: []github.Comment{
+						{Author: "user1", Replies: []github.Comment{{Author: "octocat"}, {Author: "helper"}}},
+						{Author: "user2"},
+					},
+				},
+			}),
+			expected: []string{"octocat", "user1", "helper", "user2"},
+		},
+		{
+			name: "Discussion 答案",
+			doc: DiscussionDocument(&github.Discussion{
+				Thread:         github.Thread{Author: "octocat", Comments: []github.Comment{{Author: "user1"}}},
+				Answer:         &github.Comment{Author: "expert"},
+				AnswerChosenBy: "maintainer",
+			}),
+			expected: []string{"octocat", "user1", "expert", "maintainer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := participants(tt.doc); strings.Join(result, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("participants() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
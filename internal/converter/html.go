@@ -0,0 +1,612 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// htmlPage HTML 文档的渲染数据
+type htmlPage struct {
+	Title     string
+	Type      string
+	Status    string
+	URL       string
+	Labels    []string
+	Author    htmlUser
+	CreatedAt string
+	UpdatedAt string
+	Body      template.HTML
+	Edits     *htmlEdits // 正文的修订记录
+	Reactions string
+	Poll      *htmlPoll     // Discussion 的投票
+	Answer    *htmlComment  // Discussion 被采纳的答案
+	Comments  []htmlComment // 评论和时间线事件，按时间顺序
+	Notice    string        // 评论截断提示
+	Reviews   []htmlComment
+	Threads   []htmlThread
+
+	Files        []htmlFile
+	FilesSummary string // 如 2 files changed, 12 additions, 4 deletions
+	DiffNotice   string // diff 超出 GitHub 大小限制的提示
+
+	// 元数据，为空时不显示
+	Category  string // 如 🙏 Q&A
+	Assignees []string
+	Milestone string
+	Draft     bool
+	BaseRef   string
+	HeadRef   string
+	Reviewers []string // 被请求 Review 的用户和团队
+	Merged    string   // 如 by @octocat at 2024-02-03T12:00:00Z
+	Answered  string   // 采纳答案的信息，格式同 Merged
+	Closed    string   // 如 as completed at 2024-01-05T12:00:00Z
+	Upvotes   int
+	Locked    bool
+}
+
+// htmlUser 用户信息，URL 为空时不渲染链接
+type htmlUser struct {
+	Login  string
+	URL    string
+	Badges string // 如 [bot]、(maintainer)
+}
+
+// htmlComment 评论或 Review，ID 用作页面内锚点
+type htmlComment struct {
+	ID        string
+	Author    htmlUser
+	Verb      string // commented、approved 等
+	CreatedAt string
+	Body      template.HTML
+	Edits     *htmlEdits // 修订记录
+	Reactions string
+	IsAnswer  bool
+	Anchored  bool          // URL 指向的评论，突出显示
+	Minimized string        // 非空时折叠正文，内容为隐藏说明，如 Hidden as off-topic
+	Event     template.HTML // 非空时为时间线事件，其他字段为空
+	Replies   []htmlComment // Discussion 评论的回复
+}
+
+// htmlThread 行内代码评审讨论
+type htmlThread struct {
+	ID       string
+	Path     string
+	Lines    string
+	States   string
+	DiffHunk string
+	Comments []htmlComment
+}
+
+// htmlFile 变更文件，ID 用作页面内锚点，Patch 为空时不渲染 diff
+type htmlFile struct {
+	ID        string
+	Path      string
+	Change    string
+	Additions int
+	Deletions int
+	Patch     string
+	Notice    string // diff 截断提示
+}
+
+// htmlEdits 修订记录，Revisions 为空时只显示最后一次修订的说明
+type htmlEdits struct {
+	Summary   string // 如 Last edited by @hubot at 2024-01-02T10:00:00Z
+	Revisions []revision
+}
+
+// htmlPoll Discussion 投票
+type htmlPoll struct {
+	Question   string
+	TotalVotes string // 如 5 votes
+	Options    []htmlPollOption
+}
+
+// htmlPollOption 投票选项，Share 为得票占比，如 60%
+type htmlPollOption struct {
+	Option string
+	Votes  int
+	Share  string
+}
+
+// renderHTML 将 Document 渲染为独立的 HTML 文档
+func renderHTML(doc *Document, opts *Options) ([]byte, error) {
+	r := newHTMLRenderer(opts)
+	r.anchor = doc.Anchor
+
+	page := htmlPage{
+		Title:     doc.Title,
+		Type:      doc.Type,
+		Status:    doc.Status,
+		URL:       doc.URL,
+		Labels:    doc.Labels,
+		Author:    r.user(doc.Author, doc.AuthorURL, doc.AuthorType, doc.AuthorAssociation),
+		CreatedAt: formatTime(doc.CreatedAt),
+		Body:      r.markdown(doc.Body),
+		Edits:     r.edits(doc.Edits),
+		Reactions: r.reactions(doc.Reactions),
+		Comments:  r.timeline(doc),
+		Notice:    truncationNotice(doc.FetchedComments, doc.TotalComments, doc.CommentsTruncated),
+
+		Category:  categoryText(doc.Category),
+		Assignees: doc.Assignees,
+		Milestone: milestoneText(doc.Milestone),
+		Draft:     doc.IsDraft,
+		BaseRef:   doc.BaseRef,
+		HeadRef:   doc.HeadRef,
+		Reviewers: doc.RequestedReviewers,
+		Merged:    mergedText(doc),
+		Answered:  answerChosenText(doc),
+		Closed:    closedText(doc),
+		Upvotes:   doc.UpvoteCount,
+		Locked:    doc.Locked,
+	}
+	if !doc.UpdatedAt.IsZero() {
+		page.UpdatedAt = formatTime(doc.UpdatedAt)
+	}
+	if doc.Poll != nil {
+		poll := &htmlPoll{Question: doc.Poll.Question, TotalVotes: plural(doc.Poll.TotalVotes, "vote")}
+		for _, option := range doc.Poll.Options {
+			poll.Options = append(poll.Options, htmlPollOption{
+				Option: option.Option,
+				Votes:  option.Votes,
+				Share:  pollShare(option.Votes, doc.Poll.TotalVotes),
+			})
+		}
+		page.Poll = poll
+	}
+	if doc.Answer != nil {
+		answer := r.comment("answer", "answered", *doc.Answer)
+		// 答案同时出现在评论列表中，这里不重复标记
+		answer.IsAnswer, answer.Anchored = false, false
+		page.Answer = &answer
+	}
+
+	for _, review := range doc.Reviews {
+		if !showReview(review) {
+			continue
+		}
+		page.Reviews = append(page.Reviews, htmlComment{
+			ID:        fmt.Sprintf("review-%d", len(page.Reviews)+1),
+			Author:    r.user(review.Author, review.AuthorURL, review.AuthorType, review.AuthorAssociation),
+			Verb:      reviewVerb(review.State),
+			CreatedAt: formatTime(review.SubmittedAt),
+			Body:      r.markdown(review.Body),
+		})
+	}
+
+	for i, thread := range doc.ReviewThreads {
+		id := fmt.Sprintf("thread-%d", i+1)
+		page.Threads = append(page.Threads, htmlThread{
+			ID:       id,
+			Path:     thread.Path,
+			Lines:    threadLines(thread),
+			States:   threadStates(thread),
+			DiffHunk: thread.DiffHunk,
+			Comments: r.comments(id+"-comment", thread.Comments),
+		})
+	}
+
+	if len(doc.Files) > 0 {
+		page.FilesSummary = filesSummary(doc.Files)
+	}
+	if doc.DiffTooLarge {
+		page.DiffNotice = diffTooLargeNotice
+	}
+	for i, f := range doc.Files {
+		file := htmlFile{
+			ID:        fmt.Sprintf("file-%d", i+1),
+			Path:      f.Path,
+			Change:    changeTypeText(f.ChangeType),
+			Additions: f.Additions,
+			Deletions: f.Deletions,
+		}
+		if f.Patch != "" {
+			var shown, total int
+			file.Patch, shown, total = truncatePatch(f.Patch, r.opts.MaxDiffLines)
+			file.Notice = diffNotice(shown, total)
+		}
+		page.Files = append(page.Files, file)
+	}
+
+	return r.render(page)
+}
+
+// htmlRenderer 将 Markdown 正文渲染为 HTML，并记录渲染过程中的第一个错误
+type htmlRenderer struct {
+	opts   *Options
+	anchor string // 突出显示的评论锚点
+	md     goldmark.Markdown
+	err    error
+}
+
+// newHTMLRenderer 创建按 GitHub 风格（GFM、换行即换行）渲染 Markdown 的渲染器
+// 正文中的原始 HTML 不会被输出，避免导出的页面执行不可信的脚本
+func newHTMLRenderer(opts *Options) *htmlRenderer {
+	return &htmlRenderer{
+		opts: opts,
+		md: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithRendererOptions(html.WithHardWraps()),
+		),
+	}
+}
+
+// markdown 将 Markdown 渲染为 HTML 片段
+func (r *htmlRenderer) markdown(source string) template.HTML {
+	if source == "" || r.err != nil {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(source), &buf); err != nil {
+		r.err = fmt.Errorf("failed to render markdown: %w", err)
+		return ""
+	}
+	return template.HTML(buf.String())
+}
+
+// user 返回用户信息及其标记，未启用用户链接时不带 URL
+func (r *htmlRenderer) user(login, url, authorType, association string) htmlUser {
+	if !r.opts.EnableUserLinks {
+		url = ""
+	}
+	return htmlUser{Login: login, URL: url, Badges: renderBadges(authorType, association)}
+}
+
+// reactions 返回 Reactions 统计，未启用时返回空字符串
+func (r *htmlRenderer) reactions(reactions *github.Reactions) string {
+	if !r.opts.EnableReactions {
+		return ""
+	}
+	return renderReactions(reactions)
+}
+
+// comments 转换评论列表，锚点为 prefix-1、prefix-2 ...，回复的锚点为 prefix-1-reply-1 ...
+func (r *htmlRenderer) comments(prefix string, comments []github.Comment) []htmlComment {
+	var result []htmlComment
+	for i, comment := range comments {
+		c := r.comment(fmt.Sprintf("%s-%d", prefix, i+1), "commented", comment)
+		for j, reply := range comment.Replies {
+			c.Replies = append(c.Replies, r.comment(fmt.Sprintf("%s-reply-%d", c.ID, j+1), "replied", reply))
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// comment 转换单条评论，不包含回复
+func (r *htmlRenderer) comment(id, verb string, comment github.Comment) htmlComment {
+	c := htmlComment{
+		ID:        id,
+		Author:    r.user(comment.Author, comment.AuthorURL, comment.AuthorType, comment.AuthorAssociation),
+		Verb:      verb,
+		CreatedAt: formatTime(comment.CreatedAt),
+		Body:      r.markdown(comment.Body),
+		Edits:     r.edits(comment.Edits),
+		Reactions: r.reactions(comment.Reactions),
+		IsAnswer:  comment.IsAnswer,
+		Anchored:  isAnchored(comment, r.anchor),
+	}
+	if collapsed(comment, r.opts) {
+		c.Minimized = minimizedLabel(comment.MinimizedReason)
+	}
+	return c
+}
+
+// edits 按 -edit-history 转换修订记录，未启用或没有修订时返回 nil
+func (r *htmlRenderer) edits(edits []github.Edit) *htmlEdits {
+	if len(edits) == 0 {
+		return nil
+	}
+	switch r.opts.EditHistory {
+	case EditHistoryLast:
+		return &htmlEdits{Summary: lastEditedText(edits)}
+	case EditHistoryFull:
+		return &htmlEdits{Summary: editSummary(edits), Revisions: revisions(edits)}
+	default:
+		return nil
+	}
+}
+
+// timeline 按时间顺序合并评论和时间线事件，评论的锚点与 comments 相同
+func (r *htmlRenderer) timeline(doc *Document) []htmlComment {
+	comments := r.comments("comment", doc.Comments)
+	var result []htmlComment
+	for _, item := range doc.Timeline() {
+		if item.Event == nil {
+			result = append(result, comments[0])
+			comments = comments[1:]
+			continue
+		}
+		result = append(result, htmlComment{Event: r.markdown(renderEvent(*item.Event, r.opts.EnableUserLinks))})
+	}
+	return result
+}
+
+// render 执行页面模板
+func (r *htmlRenderer) render(page htmlPage) ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	tmpl, err := template.New("page").Parse(htmlTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, page); err != nil {
+		return nil, fmt.Errorf("failed to render html: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatTime 格式化为 UTC 的 RFC 3339 时间
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// truncationNotice 返回评论被截断的提示，未截断时返回空字符串
+func truncationNotice(shown, total int, truncated bool) string {
+	if !truncated {
+		return ""
+	}
+	return fmt.Sprintf("Showing %d of %d comments (truncated by max-comments limit)", shown, total)
+}
+
+// htmlTemplate HTML 页面模板，样式内嵌以便单个文件即可在浏览器中打开
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+` + htmlStyle + `</style>
+</head>
+<body>
+<main>
+<header>
+<h1 id="top">{{.Title}}</h1>
+<dl class="meta">
+<dt>Type</dt><dd>{{.Type}}</dd>
+<dt>Status</dt><dd><span class="status status-{{.Status}}">{{.Status}}</span></dd>
+<dt>Author</dt><dd>{{template "user" .Author}}</dd>
+{{- if .Category}}
+<dt>Category</dt><dd>{{.Category}}</dd>
+{{- end}}
+{{- if .Labels}}
+<dt>Labels</dt><dd>{{range .Labels}}<span class="label">{{.}}</span> {{end}}</dd>
+{{- end}}
+{{- if .Assignees}}
+<dt>Assignees</dt><dd>{{range $i, $a := .Assignees}}{{if $i}}, {{end}}@{{$a}}{{end}}</dd>
+{{- end}}
+{{- if .Milestone}}
+<dt>Milestone</dt><dd>{{.Milestone}}</dd>
+{{- end}}
+{{- if .Draft}}
+<dt>Draft</dt><dd>yes</dd>
+{{- end}}
+{{- if .BaseRef}}
+<dt>Branches</dt><dd><code>{{.HeadRef}}</code> → <code>{{.BaseRef}}</code></dd>
+{{- end}}
+{{- if .Reviewers}}
+<dt>Review requested</dt><dd>{{range $i, $r := .Reviewers}}{{if $i}}, {{end}}@{{$r}}{{end}}</dd>
+{{- end}}
+{{- if .Merged}}
+<dt>Merged</dt><dd>{{.Merged}}</dd>
+{{- end}}
+{{- if .Answered}}
+<dt>Answer chosen</dt><dd>{{.Answered}}</dd>
+{{- end}}
+{{- if .Closed}}
+<dt>Closed</dt><dd>{{.Closed}}</dd>
+{{- end}}
+{{- if .Upvotes}}
+<dt>Upvotes</dt><dd>{{.Upvotes}}</dd>
+{{- end}}
+{{- if .Locked}}
+<dt>Locked</dt><dd>yes</dd>
+{{- end}}
+<dt>Created</dt><dd><time datetime="{{.CreatedAt}}">{{.CreatedAt}}</time></dd>
+{{- if .UpdatedAt}}
+<dt>Updated</dt><dd><time datetime="{{.UpdatedAt}}">{{.UpdatedAt}}</time></dd>
+{{- end}}
+<dt>URL</dt><dd><a href="{{.URL}}">{{.URL}}</a></dd>
+</dl>
+</header>
+<article class="markdown-body">
+{{.Body}}</article>
+{{- with .Edits}}
+{{template "edits" .}}
+{{- end}}
+{{- with .Poll}}
+<section id="poll">
+<h2>Poll</h2>
+<p><strong>{{.Question}}</strong></p>
+<table class="poll">
+<thead><tr><th>Option</th><th>Votes</th><th>Share</th></tr></thead>
+<tbody>
+{{- range .Options}}
+<tr><td>{{.Option}}</td><td>{{.Votes}}</td><td>{{.Share}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<p>{{.TotalVotes}}</p>
+</section>
+{{- end}}
+{{- if .Reactions}}
+<p class="reactions">{{.Reactions}}</p>
+{{- end}}
+{{- with .Answer}}
+<section id="accepted-answer">
+<h2>Accepted Answer</h2>
+{{template "comment" .}}
+</section>
+{{- end}}
+{{- if .Comments}}
+<section id="comments">
+<h2>Comments</h2>
+{{- range .Comments}}
+{{if .Event}}<div class="event">{{.Event}}</div>{{else}}{{template "comment" .}}{{end}}
+{{- end}}
+</section>
+{{- end}}
+{{- if .Notice}}
+<p class="notice">{{.Notice}}</p>
+{{- end}}
+{{- if or .Reviews .Threads}}
+<section id="review">
+<h2>Review</h2>
+{{- range .Reviews}}
+{{template "comment" .}}
+{{- end}}
+{{- range .Threads}}
+<section class="thread" id="{{.ID}}">
+<h3><a class="anchor" href="#{{.ID}}">#</a> <code>{{.Path}}</code>{{.Lines}}{{if .States}} <span class="thread-state">({{.States}})</span>{{end}}</h3>
+{{- if .DiffHunk}}
+<pre class="diff"><code>{{.DiffHunk}}</code></pre>
+{{- end}}
+{{- range .Comments}}
+{{template "comment" .}}
+{{- end}}
+</section>
+{{- end}}
+</section>
+{{- end}}
+{{- if .Files}}
+<section id="files">
+<h2>Files Changed</h2>
+<p>{{.FilesSummary}}</p>
+<table class="files">
+<thead><tr><th>File</th><th>Change</th><th>Additions</th><th>Deletions</th></tr></thead>
+<tbody>
+{{- range .Files}}
+<tr><td>{{if .Patch}}<a href="#{{.ID}}"><code>{{.Path}}</code></a>{{else}}<code>{{.Path}}</code>{{end}}</td><td>{{.Change}}</td><td class="additions">+{{.Additions}}</td><td class="deletions">-{{.Deletions}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- if .DiffNotice}}
+<p class="notice">{{.DiffNotice}}</p>
+{{- end}}
+{{- range .Files}}
+{{- if .Patch}}
+<section class="file" id="{{.ID}}">
+<h3><a class="anchor" href="#{{.ID}}">#</a> <code>{{.Path}}</code></h3>
+<pre class="diff"><code>{{.Patch}}</code></pre>
+{{- if .Notice}}
+<p class="notice">{{.Notice}}</p>
+{{- end}}
+</section>
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+{{define "user"}}{{if .URL}}<a href="{{.URL}}">@{{.Login}}</a>{{else}}@{{.Login}}{{end}}{{if .Badges}} <span class="badge">{{.Badges}}</span>{{end}}{{end}}
+{{- define "comment"}}<article class="comment{{if .Anchored}} anchored{{end}}" id="{{.ID}}">
+<header><a class="anchor" href="#{{.ID}}">#</a> <strong>{{template "user" .Author}}</strong> {{.Verb}} at <time datetime="{{.CreatedAt}}">{{.CreatedAt}}</time>{{if .IsAnswer}} <span class="answer">✅ Answer</span>{{end}}{{if .Anchored}} <span class="linked">📌 Linked comment</span>{{end}}</header>
+{{- if .Minimized}}
+<details class="minimized">
+<summary>{{.Minimized}}</summary>
+{{- if .Body}}
+<div class="markdown-body">
+{{.Body}}</div>
+{{- end}}
+</details>
+{{- else if .Body}}
+<div class="markdown-body">
+{{.Body}}</div>
+{{- end}}
+{{- with .Edits}}
+{{template "edits" .}}
+{{- end}}
+{{- if .Reactions}}
+<p class="reactions">{{.Reactions}}</p>
+{{- end}}
+{{- if .Replies}}
+<div class="replies">
+{{- range .Replies}}
+{{template "comment" .}}
+{{- end}}
+</div>
+{{- end}}
+</article>{{end}}
+{{- define "edits"}}{{if .Revisions}}<details class="edits">
+<summary>✏️ {{.Summary}}</summary>
+{{- range .Revisions}}
+<p>{{.Label}}</p>
+{{- if .IsDiff}}
+<pre class="diff"><code>{{.Text}}</code></pre>
+{{- else if .Text}}
+<pre><code>{{.Text}}</code></pre>
+{{- end}}
+{{- end}}
+</details>{{else}}<p class="edited">✏️ {{.Summary}}</p>{{end}}{{end}}`
+
+// htmlStyle 页面内嵌样式
+const htmlStyle = `body { margin: 0; background: #f6f8fa; color: #1f2328; font: 16px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; }
+main { max-width: 880px; margin: 0 auto; padding: 32px 16px; }
+h1 { margin: 0 0 16px; font-size: 2em; line-height: 1.25; }
+h2 { margin: 32px 0 16px; padding-bottom: 8px; border-bottom: 1px solid #d1d9e0; }
+a { color: #0969da; text-decoration: none; }
+a:hover { text-decoration: underline; }
+.meta { display: grid; grid-template-columns: max-content 1fr; gap: 4px 16px; margin: 0 0 24px; color: #59636e; font-size: 14px; }
+.meta dt { font-weight: 600; }
+.meta dd { margin: 0; overflow-wrap: anywhere; }
+.status { padding: 2px 8px; border-radius: 999px; color: #fff; background: #59636e; }
+.status-open { background: #1f883d; }
+.status-closed { background: #8250df; }
+.status-merged { background: #8250df; }
+.markdown-body, .comment { background: #fff; border: 1px solid #d1d9e0; border-radius: 6px; }
+.markdown-body { padding: 16px; overflow-wrap: break-word; }
+.markdown-body > :first-child { margin-top: 0; }
+.markdown-body > :last-child { margin-bottom: 0; }
+.markdown-body img { max-width: 100%; }
+.markdown-body table { border-collapse: collapse; }
+.markdown-body th, .markdown-body td { padding: 6px 13px; border: 1px solid #d1d9e0; }
+.markdown-body blockquote { margin: 0; padding: 0 1em; color: #59636e; border-left: 4px solid #d1d9e0; }
+code, pre { font: 85% ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+code { padding: 2px 4px; background: #eff1f3; border-radius: 4px; }
+pre { padding: 16px; overflow: auto; background: #f6f8fa; border-radius: 6px; }
+pre code { padding: 0; background: none; }
+.comment { margin: 16px 0; }
+.comment > header { padding: 8px 16px; background: #f6f8fa; border-bottom: 1px solid #d1d9e0; border-radius: 6px 6px 0 0; color: #59636e; font-size: 14px; }
+.comment > header strong { color: #1f2328; }
+.comment > .markdown-body { border: 0; }
+.comment > .reactions { margin: 0; padding: 0 16px 12px; }
+.replies { padding: 0 16px; border-top: 1px solid #d1d9e0; }
+.label { display: inline-block; padding: 0 8px; border: 1px solid #d1d9e0; border-radius: 999px; color: #1f2328; }
+.anchor { color: #59636e; }
+.answer { color: #1f883d; font-weight: 600; }
+.minimized summary { padding: 8px 16px; color: #59636e; cursor: pointer; }
+.edited, .edits { margin: 8px 16px; color: #59636e; font-size: 14px; }
+.edits summary { cursor: pointer; }
+.badge { color: #59636e; font-size: 12px; font-weight: 400; }
+.comment.anchored { border-color: #d4a72c; box-shadow: 0 0 0 3px #fff8c5; }
+.linked { color: #9a6700; font-weight: 600; }
+.reactions { font-size: 14px; }
+.event { margin: 8px 16px; color: #59636e; font-size: 14px; }
+.event p { margin: 0; }
+.notice { padding: 8px 16px; color: #9a6700; background: #fff8c5; border: 1px solid #d4a72c; border-radius: 6px; }
+.thread { margin: 24px 0; }
+.thread h3 { font-size: 16px; }
+.thread-state { color: #59636e; font-weight: normal; }
+pre.diff { background: #fff; border: 1px solid #d1d9e0; }
+.files, .poll { border-collapse: collapse; font-size: 14px; }
+.files th, .files td, .poll th, .poll td { padding: 6px 13px; border: 1px solid #d1d9e0; text-align: left; }
+.poll td + td { text-align: right; }
+.files .additions { color: #1f883d; text-align: right; }
+.files .deletions { color: #d1242f; text-align: right; }
+.file h3 { font-size: 16px; }
+`
@@ -0,0 +1,220 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestRenderHTML(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		issue       *github.Issue
+		opts        *Options
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "完整文档",
+			issue: &github.Issue{
+				Thread: github.Thread{
+					Title:     "Crash on <startup>",
+					Body:      "Steps:\n\n1. run **it**\n2. see `panic`",
+					Author:    "octocat",
+					AuthorURL: "https://github.com/octocat",
+					CreatedAt: createdAt,
+					Status:    "open",
+					URL:       "https://github.com/owner/repo/issues/1",
+					Labels:    []string{"bug", "p1"},
+					Comments: []github.Comment{
+						{Author: "user1", Body: "First", CreatedAt: createdAt},
+						{Author: "user2", Body: "| a | b |\n|---|---|\n| 1 | 2 |", CreatedAt: createdAt},
+					},
+				},
+			},
+			opts: DefaultOptions(),
+			contains: []string{
+				"<!DOCTYPE html>",
+				"<style>",
+				"<title>Crash on &lt;startup&gt;</title>",
+				`<h1 id="top">Crash on &lt;startup&gt;</h1>`,
+				`<span class="status status-open">open</span>`,
+				`<dt>Labels</dt><dd><span class="label">bug</span> <span class="label">p1</span> </dd>`,
+				`<time datetime="2024-01-01T12:00:00Z">`,
+				"<strong>it</strong>",
+				"<code>panic</code>",
+				`<article class="comment" id="comment-1">`,
+				`<a class="anchor" href="#comment-2">#</a> <strong>@user2</strong> commented at`,
+				"<table>",
+			},
+			notContains: []string{
+				`href="https://github.com/octocat"`,
+				`<p class="reactions">`,
+				`<section id="review">`,
+			},
+		},
+		{
+			name: "正文中的原始 HTML 不会输出",
+			issue: &github.Issue{
+				Thread: github.Thread{
+					Title:  "XSS",
+					Body:   "<script>alert(1)</script>\n\ntext",
+					Author: "octocat",
+				},
+			},
+			opts:        DefaultOptions(),
+			contains:    []string{"<p>text</p>"},
+			notContains: []string{"<script>"},
+		},
+		{
+			name: "用户链接、Reactions 和截断提示",
+			issue: &github.Issue{
+				Thread: github.Thread{
+					Title:             "Test",
+					Author:            "octocat",
+					AuthorURL:         "https://github.com/octocat",
+					Reactions:         &Reactions{ThumbsUp: 2},
+					Comments:          []github.Comment{{Author: "user1", AuthorURL: "https://github.com/user1", Body: "Hi", Reactions: &Reactions{Heart: 1}}},
+					TotalComments:     5,
+					CommentsTruncated: true,
+				},
+			},
+			opts: &Options{Format: FormatHTML, EnableReactions: true, EnableUserLinks: true},
+			contains: []string{
+				`<a href="https://github.com/octocat">@octocat</a>`,
+				`<a href="https://github.com/user1">@user1</a>`,
+				`<p class="reactions">👍 2</p>`,
+				`<p class="reactions">❤️ 1</p>`,
+				`<p class="notice">Showing 1 of 5 comments (truncated by max-comments limit)</p>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := renderHTML(IssueDocument(tt.issue), tt.opts)
+			if err != nil {
+				t.Fatalf("renderHTML() error = %v", err)
+			}
+
+			output := string(result)
+			for _, expected := range tt.contains {
+				if !strings.Contains(output, expected) {
+					t.Errorf("renderHTML() missing %q, got:\n%s", expected, output)
+				}
+			}
+			for _, unexpected := range tt.notContains {
+				if strings.Contains(output, unexpected) {
+					t.Errorf("renderHTML() should not contain %q, got:\n%s", unexpected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderHTMLPR(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	pr := &github.PullRequest{
+		Thread: github.Thread{
+			Title:  "Add feature",
+			Author: "octocat",
+			Status: "merged",
+		},
+
+		Reviews: []github.Review{
+			{Author: "reviewer", AuthorAssociation: "MEMBER", State: "APPROVED", Body: "LGTM", SubmittedAt: createdAt},
+			{Author: "reviewer", State: "COMMENTED", SubmittedAt: createdAt},
+		},
+		ReviewThreads: []github.ReviewThread{
+			{
+				Path:       "main.go",
+				Line:       12,
+				StartLine:  10,
+				DiffHunk:   "@@ -1,2 +1,2 @@\n-old <a>\n+new",
+				IsResolved: true,
+				Comments: []github.Comment{
+					{Author: "reviewer", Body: "Why?", CreatedAt: createdAt},
+					{Author: "octocat", AuthorType: "Bot", Body: "Because.", CreatedAt: createdAt},
+				},
+			},
+		},
+	}
+
+	result, err := renderHTML(PullRequestDocument(pr), DefaultOptions())
+	if err != nil {
+		t.Fatalf("renderHTML() error = %v", err)
+	}
+
+	output := string(result)
+	for _, expected := range []string{
+		`<span class="status status-merged">merged</span>`,
+		`<section id="review">`,
+		`<article class="comment" id="review-1">`,
+		`<strong>@reviewer <span class="badge">(maintainer)</span></strong> approved at`,
+		`<strong>@octocat <span class="badge">[bot]</span></strong> commented at`,
+		"<p>LGTM</p>",
+		`<section class="thread" id="thread-1">`,
+		`<code>main.go</code> lines 10-12 <span class="thread-state">(resolved)</span>`,
+		"<pre class=\"diff\"><code>@@ -1,2 &#43;1,2 @@\n-old &lt;a&gt;\n&#43;new</code></pre>",
+		`<article class="comment" id="thread-1-comment-2">`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("renderHTML() missing %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, `id="review-2"`) {
+		t.Errorf("renderHTML() should skip COMMENTED review without body, got:\n%s", output)
+	}
+}
+
+func TestRenderHTMLDiscussion(t *testing.T) {
+	discussion := &github.Discussion{
+		Thread: github.Thread{
+			Title:  "How to?",
+			Author: "octocat",
+			Status: "open",
+			Comments: []github.Comment{
+				{Author: "user1", Body: "Like this", IsAnswer: true, Replies: []github.Comment{{Author: "octocat", Body: "Thanks"}}},
+			},
+		},
+		Category:       &github.DiscussionCategory{Name: "Q&A", Emoji: ":pray:", IsAnswerable: true},
+		UpvoteCount:    2,
+		Answer:         &github.Comment{Author: "user1", Body: "Like this", IsAnswer: true},
+		AnswerChosenAt: time.Date(2024, 4, 3, 8, 0, 0, 0, time.UTC),
+		AnswerChosenBy: "octocat",
+		Poll: &github.Poll{
+			Question:   "A or B?",
+			TotalVotes: 4,
+			Options:    []github.PollOption{{Option: "A", Votes: 3}, {Option: "B", Votes: 1}},
+		},
+	}
+
+	result, err := renderHTML(DiscussionDocument(discussion), DefaultOptions())
+	if err != nil {
+		t.Fatalf("renderHTML() error = %v", err)
+	}
+
+	for _, expected := range []string{
+		`<span class="answer">✅ Answer</span>`,
+		"<section id=\"accepted-answer\">\n<h2>Accepted Answer</h2>\n<article class=\"comment\" id=\"answer\">",
+		"<div class=\"replies\">\n<article class=\"comment\" id=\"comment-1-reply-1\">",
+		"<strong>@octocat</strong> replied at",
+		"<dt>Category</dt><dd>:pray: Q&amp;A</dd>",
+		"<dt>Answer chosen</dt><dd>by @octocat at 2024-04-03T08:00:00Z</dd>",
+		"<dt>Upvotes</dt><dd>2</dd>",
+		"<section id=\"poll\">\n<h2>Poll</h2>\n<p><strong>A or B?</strong></p>",
+		"<tr><td>A</td><td>3</td><td>75%</td></tr>",
+		"<p>4 votes</p>",
+	} {
+		if !strings.Contains(string(result), expected) {
+			t.Errorf("renderHTML() missing %q, got:\n%s", expected, result)
+		}
+	}
+	if strings.Count(string(result), `<span class="answer">`) != 1 {
+		t.Errorf("renderHTML() should mark the answer only in the comment list, got:\n%s", result)
+	}
+}
@@ -0,0 +1,449 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// SchemaVersion JSON 导出格式的版本号
+// 删除或重命名字段、改变字段含义时递增；只新增字段时不变
+const SchemaVersion = 1
+
+// jsonDocument JSON 导出的顶层结构，各类型共有的字段
+//
+// 所有时间均为 UTC 的 RFC 3339 字符串，未知时为空字符串；
+// 列表字段始终存在，没有数据时为空数组
+type jsonDocument struct {
+	SchemaVersion     int           `json:"schema_version"`
+	Type              string        `json:"type"` // issue, pull_request, discussion
+	Title             string        `json:"title"`
+	URL               string        `json:"url"`
+	Author            jsonUser      `json:"author"`
+	CreatedAt         string        `json:"created_at"`
+	UpdatedAt         string        `json:"updated_at"`
+	ClosedAt          string        `json:"closed_at"`
+	Status            string        `json:"status"` // open, closed, merged
+	Locked            bool          `json:"locked"`
+	Labels            []string      `json:"labels"`
+	Body              string        `json:"body"`  // 原始 Markdown
+	Edits             []jsonEdit    `json:"edits"` // 正文的修订记录，按时间正序；未启用 -edit-history 时为空数组
+	Reactions         jsonReactions `json:"reactions"`
+	Comments          []jsonComment `json:"comments"`
+	CommentsTotal     int           `json:"comments_total"`     // API 返回的评论总数
+	CommentsFetched   int           `json:"comments_fetched"`   // 获取到的评论数，-minimized omit 省略的评论也计算在内
+	CommentsTruncated bool          `json:"comments_truncated"` // 是否因 max-comments 限制而截断
+	Events            []jsonEvent   `json:"events"`             // 时间线事件，按时间正序；未启用 -enable-timeline 时为空数组
+	Anchor            string        `json:"anchor,omitempty"`   // 突出显示的评论锚点，与评论 url 的片段对应
+}
+
+// jsonIssue Issue 的 JSON 结构，在 jsonDocument 基础上增加指派人、里程碑和关闭原因
+type jsonIssue struct {
+	jsonDocument
+	Assignees   []string       `json:"assignees"`
+	Milestone   *jsonMilestone `json:"milestone"`    // 未设置时为 null
+	StateReason string         `json:"state_reason"` // COMPLETED, NOT_PLANNED, DUPLICATE, REOPENED，从未关闭时为空
+}
+
+// jsonDiscussion Discussion 的 JSON 结构，在 jsonDocument 基础上增加分类、投票和被采纳的答案
+type jsonDiscussion struct {
+	jsonDocument
+	Category       *jsonCategory `json:"category"` // 未知时为 null
+	UpvoteCount    int           `json:"upvote_count"`
+	Answer         *jsonComment  `json:"answer"`           // 被采纳的答案，没有时为 null；同时出现在 comments 或其回复中
+	AnswerChosenAt string        `json:"answer_chosen_at"` // 没有答案时为空字符串
+	AnswerChosenBy string        `json:"answer_chosen_by"`
+	Poll           *jsonPoll     `json:"poll"` // 没有投票时为 null
+}
+
+// jsonCategory Discussion 分类
+type jsonCategory struct {
+	Name         string `json:"name"`
+	Emoji        string `json:"emoji"`         // 如 :pray:
+	IsAnswerable bool   `json:"is_answerable"` // 是否为可采纳答案的 Q&A 类分类
+}
+
+// jsonPoll Discussion 投票
+type jsonPoll struct {
+	Question   string           `json:"question"`
+	TotalVotes int              `json:"total_votes"`
+	Options    []jsonPollOption `json:"options"`
+}
+
+// jsonPollOption 投票选项
+type jsonPollOption struct {
+	Option string `json:"option"`
+	Votes  int    `json:"votes"`
+}
+
+// jsonPullRequest Pull Request 的 JSON 结构，在 jsonDocument 基础上增加合并信息和 Review
+type jsonPullRequest struct {
+	jsonDocument
+	Assignees          []string           `json:"assignees"`
+	Milestone          *jsonMilestone     `json:"milestone"` // 未设置时为 null
+	IsDraft            bool               `json:"is_draft"`
+	MergedAt           string             `json:"merged_at"`
+	MergedBy           string             `json:"merged_by"`
+	BaseRef            string             `json:"base_ref"`
+	HeadRef            string             `json:"head_ref"`
+	RequestedReviewers []string           `json:"requested_reviewers"` // 用户为登录名，团队为 org/team
+	Reviews            []jsonReview       `json:"reviews"`
+	ReviewThreads      []jsonReviewThread `json:"review_threads"`
+	Files              []jsonFile         `json:"files"`          // 变更文件；未启用 -enable-files 或 -enable-diff 时为空数组
+	DiffTooLarge       bool               `json:"diff_too_large"` // diff 超出 GitHub 的大小限制，patch 均为空
+}
+
+// jsonMilestone 里程碑
+type jsonMilestone struct {
+	Title string `json:"title"`
+	DueOn string `json:"due_on"`
+}
+
+// jsonUser 用户
+type jsonUser struct {
+	Login       string `json:"login"`
+	URL         string `json:"url"`                   // 用户主页链接
+	Type        string `json:"type,omitempty"`        // User, Bot, Mannequin, Organization；时间线事件的 actor 不含
+	Association string `json:"association,omitempty"` // OWNER, MEMBER, COLLABORATOR, CONTRIBUTOR, FIRST_TIME_CONTRIBUTOR, NONE 等；时间线事件的 actor 不含
+}
+
+// jsonReactions 各类 Reaction 的数量
+type jsonReactions struct {
+	ThumbsUp   int `json:"thumbs_up"`
+	ThumbsDown int `json:"thumbs_down"`
+	Laugh      int `json:"laugh"`
+	Hooray     int `json:"hooray"`
+	Confused   int `json:"confused"`
+	Heart      int `json:"heart"`
+	Rocket     int `json:"rocket"`
+	Eyes       int `json:"eyes"`
+}
+
+// jsonComment 评论
+type jsonComment struct {
+	Author    jsonUser      `json:"author"`
+	CreatedAt string        `json:"created_at"`
+	URL       string        `json:"url"` // 评论链接，片段为评论锚点
+	Body      string        `json:"body"`
+	Reactions jsonReactions `json:"reactions"`
+	IsAnswer  bool          `json:"is_answer,omitempty"` // 仅 Discussion 的 Answer 评论为 true
+	Replies   []jsonComment `json:"replies,omitempty"`   // 仅 Discussion 的顶层评论：回复，按时间正序
+
+	IsMinimized     bool   `json:"is_minimized,omitempty"`     // 被隐藏的评论为 true
+	MinimizedReason string `json:"minimized_reason,omitempty"` // 隐藏原因，如 spam、off-topic、outdated
+
+	Edits []jsonEdit `json:"edits,omitempty"` // 修订记录，按时间正序；仅启用 -edit-history 且评论被编辑过时存在
+}
+
+// jsonEdit 正文或评论的一次修订，body 为修订后的完整正文
+type jsonEdit struct {
+	Editor   string `json:"editor"` // 编辑者登录名，账号已删除时为空字符串
+	EditedAt string `json:"edited_at"`
+	Body     string `json:"body"`    // 修订内容被删除时为空字符串
+	Deleted  bool   `json:"deleted"` // 修订内容是否已被删除
+}
+
+// jsonEvent 时间线事件，只包含该类型相关的字段
+type jsonEvent struct {
+	Type          string           `json:"type"` // labeled, unlabeled, assigned, closed, cross_referenced, merged 等
+	Actor         jsonUser         `json:"actor"`
+	CreatedAt     string           `json:"created_at"`
+	Label         string           `json:"label,omitempty"`
+	Assignee      string           `json:"assignee,omitempty"`
+	Milestone     string           `json:"milestone,omitempty"`
+	StateReason   string           `json:"state_reason,omitempty"` // COMPLETED, NOT_PLANNED, DUPLICATE
+	PreviousTitle string           `json:"previous_title,omitempty"`
+	CurrentTitle  string           `json:"current_title,omitempty"`
+	Ref           string           `json:"ref,omitempty"`    // 合并到的分支
+	Source        *jsonEventSource `json:"source,omitempty"` // 关联的 Issue、Pull Request 或 commit
+}
+
+// jsonEventSource 事件关联的 Issue、Pull Request 或 commit
+type jsonEventSource struct {
+	Title string `json:"title"` // commit 为缩写的 SHA
+	URL   string `json:"url"`
+}
+
+// jsonReview Pull Request Review 总结
+type jsonReview struct {
+	Author      jsonUser `json:"author"`
+	State       string   `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED, PENDING
+	SubmittedAt string   `json:"submitted_at"`
+	Body        string   `json:"body"`
+}
+
+// jsonReviewThread 行内代码评审讨论
+type jsonReviewThread struct {
+	Path       string        `json:"path"`
+	Line       int           `json:"line"`       // 结束行号，0 表示未知
+	StartLine  int           `json:"start_line"` // 多行评论的起始行号，单行评论为 0
+	DiffHunk   string        `json:"diff_hunk"`
+	IsResolved bool          `json:"is_resolved"`
+	IsOutdated bool          `json:"is_outdated"`
+	Comments   []jsonComment `json:"comments"` // 第一条为发起评论，其余为回复
+}
+
+// jsonFile Pull Request 的变更文件
+type jsonFile struct {
+	Path           string `json:"path"`
+	ChangeType     string `json:"change_type"` // ADDED, DELETED, MODIFIED, RENAMED, COPIED, CHANGED
+	Additions      int    `json:"additions"`
+	Deletions      int    `json:"deletions"`
+	Patch          string `json:"patch"`           // unified diff，未获取 diff 或二进制文件时为空字符串
+	PatchTruncated bool   `json:"patch_truncated"` // 是否因 max-diff-lines 限制而截断
+}
+
+// renderJSON 将 Document 渲染为 JSON，各类型额外包含各自特有的字段
+func renderJSON(doc *Document, opts *Options) ([]byte, error) {
+	base := jsonDocument{
+		SchemaVersion:     SchemaVersion,
+		Type:              doc.Type,
+		Title:             doc.Title,
+		URL:               doc.URL,
+		Author:            jsonUser{Login: doc.Author, URL: doc.AuthorURL, Type: doc.AuthorType, Association: doc.AuthorAssociation},
+		CreatedAt:         formatJSONTime(doc.CreatedAt),
+		UpdatedAt:         formatJSONTime(doc.UpdatedAt),
+		ClosedAt:          formatJSONTime(doc.ClosedAt),
+		Status:            doc.Status,
+		Locked:            doc.Locked,
+		Labels:            toJSONLabels(doc.Labels),
+		Body:              doc.Body,
+		Edits:             toJSONEdits(doc.Edits),
+		Reactions:         toJSONReactions(doc.Reactions),
+		Comments:          toJSONComments(doc.Comments),
+		CommentsTotal:     doc.TotalComments,
+		CommentsFetched:   doc.FetchedComments,
+		CommentsTruncated: doc.CommentsTruncated,
+		Events:            toJSONEvents(doc.Events),
+		Anchor:            doc.Anchor,
+	}
+	if doc.Type == "issue" {
+		return marshalJSON(jsonIssue{
+			jsonDocument: base,
+			Assignees:    toJSONLabels(doc.Assignees),
+			Milestone:    toJSONMilestone(doc.Milestone),
+			StateReason:  doc.StateReason,
+		})
+	}
+	if doc.Type == "discussion" {
+		discussion := jsonDiscussion{
+			jsonDocument:   base,
+			Category:       toJSONCategory(doc.Category),
+			UpvoteCount:    doc.UpvoteCount,
+			AnswerChosenAt: formatJSONTime(doc.AnswerChosenAt),
+			AnswerChosenBy: doc.AnswerChosenBy,
+			Poll:           toJSONPoll(doc.Poll),
+		}
+		if doc.Answer != nil {
+			answer := toJSONComments([]github.Comment{*doc.Answer})[0]
+			discussion.Answer = &answer
+		}
+		return marshalJSON(discussion)
+	}
+	if doc.Type != "pull_request" {
+		return marshalJSON(base)
+	}
+
+	pr := jsonPullRequest{
+		jsonDocument:       base,
+		Assignees:          toJSONLabels(doc.Assignees),
+		Milestone:          toJSONMilestone(doc.Milestone),
+		IsDraft:            doc.IsDraft,
+		MergedAt:           formatJSONTime(doc.MergedAt),
+		MergedBy:           doc.MergedBy,
+		BaseRef:            doc.BaseRef,
+		HeadRef:            doc.HeadRef,
+		RequestedReviewers: toJSONLabels(doc.RequestedReviewers),
+		Reviews:            []jsonReview{},
+		ReviewThreads:      []jsonReviewThread{},
+		Files:              []jsonFile{},
+		DiffTooLarge:       doc.DiffTooLarge,
+	}
+
+	for _, review := range doc.Reviews {
+		pr.Reviews = append(pr.Reviews, jsonReview{
+			Author:      jsonUser{Login: review.Author, URL: review.AuthorURL, Type: review.AuthorType, Association: review.AuthorAssociation},
+			State:       review.State,
+			SubmittedAt: formatJSONTime(review.SubmittedAt),
+			Body:        review.Body,
+		})
+	}
+
+	for _, thread := range doc.ReviewThreads {
+		pr.ReviewThreads = append(pr.ReviewThreads, jsonReviewThread{
+			Path:       thread.Path,
+			Line:       thread.Line,
+			StartLine:  thread.StartLine,
+			DiffHunk:   thread.DiffHunk,
+			IsResolved: thread.IsResolved,
+			IsOutdated: thread.IsOutdated,
+			Comments:   toJSONComments(thread.Comments),
+		})
+	}
+
+	for _, f := range doc.Files {
+		patch, shown, total := truncatePatch(f.Patch, opts.MaxDiffLines)
+		pr.Files = append(pr.Files, jsonFile{
+			Path:           f.Path,
+			ChangeType:     f.ChangeType,
+			Additions:      f.Additions,
+			Deletions:      f.Deletions,
+			Patch:          patch,
+			PatchTruncated: shown < total,
+		})
+	}
+
+	return marshalJSON(pr)
+}
+
+// marshalJSON 以两个空格缩进序列化，不转义 HTML 字符，末尾带换行
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode json: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// toJSONReplies 转换回复列表，没有回复时返回 nil 以省略 replies 字段
+func toJSONReplies(replies []github.Comment) []jsonComment {
+	if len(replies) == 0 {
+		return nil
+	}
+	return toJSONComments(replies)
+}
+
+// toJSONComments 转换评论列表，没有评论时返回空数组
+func toJSONComments(comments []github.Comment) []jsonComment {
+	result := []jsonComment{}
+	for _, comment := range comments {
+		result = append(result, jsonComment{
+			Author:    jsonUser{Login: comment.Author, URL: comment.AuthorURL, Type: comment.AuthorType, Association: comment.AuthorAssociation},
+			CreatedAt: formatJSONTime(comment.CreatedAt),
+			URL:       comment.URL,
+			Body:      comment.Body,
+			Reactions: toJSONReactions(comment.Reactions),
+			IsAnswer:  comment.IsAnswer,
+			Replies:   toJSONReplies(comment.Replies),
+
+			IsMinimized:     comment.IsMinimized,
+			MinimizedReason: comment.MinimizedReason,
+
+			Edits: toJSONCommentEdits(comment.Edits),
+		})
+	}
+	return result
+}
+
+// toJSONEdits 转换修订记录，没有修订时返回空数组
+func toJSONEdits(edits []github.Edit) []jsonEdit {
+	result := []jsonEdit{}
+	for _, edit := range edits {
+		result = append(result, jsonEdit{
+			Editor:   edit.Editor,
+			EditedAt: formatJSONTime(edit.EditedAt),
+			Body:     edit.Body,
+			Deleted:  edit.Deleted,
+		})
+	}
+	return result
+}
+
+// toJSONCommentEdits 转换评论的修订记录，没有修订时返回 nil 以省略 edits 字段
+func toJSONCommentEdits(edits []github.Edit) []jsonEdit {
+	if len(edits) == 0 {
+		return nil
+	}
+	return toJSONEdits(edits)
+}
+
+// toJSONEvents 转换时间线事件，没有事件时返回空数组
+func toJSONEvents(events []github.Event) []jsonEvent {
+	result := []jsonEvent{}
+	for _, event := range events {
+		e := jsonEvent{
+			Type:          event.Type,
+			Actor:         jsonUser{Login: event.Actor, URL: event.ActorURL},
+			CreatedAt:     formatJSONTime(event.CreatedAt),
+			Label:         event.Label,
+			Assignee:      event.Assignee,
+			Milestone:     event.Milestone,
+			StateReason:   event.StateReason,
+			PreviousTitle: event.PreviousTitle,
+			CurrentTitle:  event.CurrentTitle,
+			Ref:           event.Ref,
+		}
+		if event.Source != nil {
+			e.Source = &jsonEventSource{Title: event.Source.Title, URL: event.Source.URL}
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// toJSONLabels 返回字符串列表（标签、指派人等），nil 时返回空数组
+func toJSONLabels(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
+
+// toJSONMilestone 转换里程碑，未设置时返回 nil
+func toJSONMilestone(m *github.Milestone) *jsonMilestone {
+	if m == nil {
+		return nil
+	}
+	return &jsonMilestone{Title: m.Title, DueOn: formatJSONTime(m.DueOn)}
+}
+
+// toJSONCategory 转换 Discussion 分类，nil 时返回 nil
+func toJSONCategory(c *github.DiscussionCategory) *jsonCategory {
+	if c == nil {
+		return nil
+	}
+	return &jsonCategory{Name: c.Name, Emoji: c.Emoji, IsAnswerable: c.IsAnswerable}
+}
+
+// toJSONPoll 转换 Discussion 投票，nil 时返回 nil
+func toJSONPoll(p *github.Poll) *jsonPoll {
+	if p == nil {
+		return nil
+	}
+	poll := &jsonPoll{Question: p.Question, TotalVotes: p.TotalVotes, Options: []jsonPollOption{}}
+	for _, option := range p.Options {
+		poll.Options = append(poll.Options, jsonPollOption{Option: option.Option, Votes: option.Votes})
+	}
+	return poll
+}
+
+// toJSONReactions 转换 Reactions 统计，nil 时各项均为 0
+func toJSONReactions(r *github.Reactions) jsonReactions {
+	if r == nil {
+		return jsonReactions{}
+	}
+	return jsonReactions{
+		ThumbsUp:   r.ThumbsUp,
+		ThumbsDown: r.ThumbsDown,
+		Laugh:      r.Laugh,
+		Hooray:     r.Hooray,
+		Confused:   r.Confused,
+		Heart:      r.Heart,
+		Rocket:     r.Rocket,
+		Eyes:       r.Eyes,
+	}
+}
+
+// formatJSONTime 格式化为 UTC 的 RFC 3339 时间，零值返回空字符串
+func formatJSONTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatTime(t)
+}
@@ -0,0 +1,303 @@
+package converter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestRenderJSON(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 20, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	issue := &github.Issue{
+		Thread: github.Thread{
+			Title:             "Crash <on> startup",
+			Body:              "Body & details",
+			Author:            "octocat",
+			AuthorURL:         "https://github.com/octocat",
+			AuthorType:        "User",
+			AuthorAssociation: "OWNER",
+			CreatedAt:         createdAt,
+			Status:            "open",
+			URL:               "https://github.com/owner/repo/issues/1",
+			Labels:            []string{"bug"},
+			Reactions:         &Reactions{ThumbsUp: 2},
+			Comments: []github.Comment{
+				{Author: "user1", AuthorURL: "https://github.com/user1", Body: "First", CreatedAt: createdAt},
+			},
+			TotalComments:     3,
+			CommentsTruncated: true,
+		},
+	}
+
+	result, err := renderJSON(IssueDocument(issue), DefaultOptions())
+	if err != nil {
+		t.Fatalf("renderJSON() error = %v", err)
+	}
+
+	output := string(result)
+	for _, expected := range []string{
+		`"title": "Crash <on> startup"`,
+		`"body": "Body & details"`,
+		`"created_at": "2024-01-01T12:00:00Z"`,
+		`"labels": [
+    "bug"
+  ]`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("renderJSON() missing %q, got:\n%s", expected, output)
+		}
+	}
+	if !strings.HasSuffix(output, "}\n") {
+		t.Errorf("renderJSON() should end with a newline, got:\n%s", output)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(result, &doc); err != nil {
+		t.Fatalf("renderJSON() produced invalid JSON: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected any
+	}{
+		{key: "schema_version", expected: float64(SchemaVersion)},
+		{key: "type", expected: "issue"},
+		{key: "url", expected: "https://github.com/owner/repo/issues/1"},
+		{key: "status", expected: "open"},
+		{key: "comments_total", expected: float64(3)},
+		{key: "comments_truncated", expected: true},
+	}
+	for _, tt := range tests {
+		if doc[tt.key] != tt.expected {
+			t.Errorf("renderJSON()[%q] = %v, want %v", tt.key, doc[tt.key], tt.expected)
+		}
+	}
+
+	author := doc["author"].(map[string]any)
+	if author["login"] != "octocat" || author["url"] != "https://github.com/octocat" || author["type"] != "User" || author["association"] != "OWNER" {
+		t.Errorf("renderJSON()[\"author\"] = %v, want octocat with url, type and association", author)
+	}
+	if reactions := doc["reactions"].(map[string]any); reactions["thumbs_up"] != float64(2) || reactions["eyes"] != float64(0) {
+		t.Errorf("renderJSON()[\"reactions\"] = %v, want thumbs_up 2 and all other keys 0", reactions)
+	}
+	comments := doc["comments"].([]any)
+	if len(comments) != 1 {
+		t.Fatalf("len(renderJSON()[\"comments\"]) = %d, want 1", len(comments))
+	}
+	if _, ok := comments[0].(map[string]any)["is_answer"]; ok {
+		t.Error("Issue comment should not contain is_answer")
+	}
+	if _, ok := doc["reviews"]; ok {
+		t.Error("Issue should not contain reviews")
+	}
+	if milestone, ok := doc["milestone"]; !ok || milestone != nil {
+		t.Errorf("renderJSON()[\"milestone\"] = %v, want null", milestone)
+	}
+}
+
+func TestRenderJSONEmptyLists(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func() ([]byte, error)
+		lists   []string
+	}{
+		{
+			name: "Issue",
+			convert: func() ([]byte, error) {
+				return renderJSON(IssueDocument(&github.Issue{Thread: github.Thread{Title: "Test"}}), DefaultOptions())
+			},
+			lists: []string{"labels", "comments", "events", "assignees"},
+		},
+		{
+			name: "Pull Request",
+			convert: func() ([]byte, error) {
+				return renderJSON(PullRequestDocument(&github.PullRequest{Thread: github.Thread{Title: "Test"}}), DefaultOptions())
+			},
+			lists: []string{"labels", "comments", "events", "assignees", "requested_reviewers", "reviews", "review_threads"},
+		},
+		{
+			name: "Discussion",
+			convert: func() ([]byte, error) {
+				return renderJSON(DiscussionDocument(&github.Discussion{Thread: github.Thread{Title: "Test"}}), DefaultOptions())
+			},
+			lists: []string{"labels", "comments", "events"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.convert()
+			if err != nil {
+				t.Fatalf("convert error = %v", err)
+			}
+
+			var doc map[string]any
+			if err := json.Unmarshal(result, &doc); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			for _, key := range tt.lists {
+				if list, ok := doc[key].([]any); !ok || len(list) != 0 {
+					t.Errorf("%q = %v, want empty array", key, doc[key])
+				}
+			}
+			if doc["created_at"] != "" {
+				t.Errorf("created_at = %v, want empty string for zero time", doc["created_at"])
+			}
+		})
+	}
+}
+
+func TestRenderJSONPR(t *testing.T) {
+	submittedAt := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	pr := &github.PullRequest{
+		Thread: github.Thread{
+			Title:  "Add feature",
+			Status: "merged",
+		},
+		Milestone:          &github.Milestone{Title: "v1.0"},
+		MergedAt:           submittedAt,
+		MergedBy:           "maintainer",
+		BaseRef:            "main",
+		HeadRef:            "feature",
+		RequestedReviewers: []string{"owner/core"},
+
+		Reviews: []github.Review{
+			{Author: "reviewer", State: "APPROVED", Body: "LGTM", SubmittedAt: submittedAt},
+		},
+		ReviewThreads: []github.ReviewThread{
+			{
+				Path:       "main.go",
+				Line:       12,
+				StartLine:  10,
+				DiffHunk:   "@@ -1 +1 @@",
+				IsResolved: true,
+				Comments:   []github.Comment{{Author: "reviewer", Body: "Why?", CreatedAt: submittedAt}},
+			},
+		},
+	}
+
+	result, err := renderJSON(PullRequestDocument(pr), DefaultOptions())
+	if err != nil {
+		t.Fatalf("renderJSON() error = %v", err)
+	}
+
+	var doc struct {
+		Type               string         `json:"type"`
+		Milestone          *jsonMilestone `json:"milestone"`
+		MergedAt           string         `json:"merged_at"`
+		MergedBy           string         `json:"merged_by"`
+		BaseRef            string         `json:"base_ref"`
+		HeadRef            string         `json:"head_ref"`
+		RequestedReviewers []string       `json:"requested_reviewers"`
+		Reviews            []struct {
+			Author      jsonUser `json:"author"`
+			State       string   `json:"state"`
+			SubmittedAt string   `json:"submitted_at"`
+		} `json:"reviews"`
+		ReviewThreads []jsonReviewThread `json:"review_threads"`
+	}
+	if err := json.Unmarshal(result, &doc); err != nil {
+		t.Fatalf("renderJSON() produced invalid JSON: %v", err)
+	}
+
+	if doc.Type != "pull_request" {
+		t.Errorf("type = %q, want pull_request", doc.Type)
+	}
+	if doc.Milestone == nil || doc.Milestone.Title != "v1.0" || doc.Milestone.DueOn != "" {
+		t.Errorf("milestone = %+v, want v1.0 without due date", doc.Milestone)
+	}
+	if doc.MergedAt != "2024-01-02T10:00:00Z" || doc.MergedBy != "maintainer" || doc.BaseRef != "main" || doc.HeadRef != "feature" {
+		t.Errorf("merge = %s by %q, %q <- %q, want 2024-01-02T10:00:00Z by maintainer, main <- feature", doc.MergedAt, doc.MergedBy, doc.BaseRef, doc.HeadRef)
+	}
+	if len(doc.RequestedReviewers) != 1 || doc.RequestedReviewers[0] != "owner/core" {
+		t.Errorf("requested_reviewers = %v, want [owner/core]", doc.RequestedReviewers)
+	}
+	if len(doc.Reviews) != 1 || doc.Reviews[0].State != "APPROVED" || doc.Reviews[0].SubmittedAt != "2024-01-02T10:00:00Z" {
+		t.Errorf("reviews = %+v, want one APPROVED review at 2024-01-02T10:00:00Z", doc.Reviews)
+	}
+	if len(doc.ReviewThreads) != 1 {
+		t.Fatalf("len(review_threads) = %d, want 1", len(doc.ReviewThreads))
+	}
+	thread := doc.ReviewThreads[0]
+	if thread.Path != "main.go" || thread.StartLine != 10 || thread.Line != 12 || !thread.IsResolved || len(thread.Comments) != 1 {
+		t.Errorf("review_threads[0] = %+v, want main.go lines 10-12 resolved with one comment", thread)
+	}
+}
+
+func TestRenderJSONDiscussion(t *testing.T) {
+	discussion := &github.Discussion{
+		Thread: github.Thread{
+			Title: "How to?",
+			Comments: []github.Comment{
+				{Author: "user1", Body: "Like this", IsAnswer: true, Replies: []github.Comment{{Author: "octocat", Body: "Thanks"}}},
+				{Author: "user2", Body: "No replies"},
+			},
+		},
+		Category:       &github.DiscussionCategory{Name: "Q&A", Emoji: ":pray:", IsAnswerable: true},
+		UpvoteCount:    2,
+		Answer:         &github.Comment{Author: "user1", Body: "Like this", IsAnswer: true},
+		AnswerChosenAt: time.Date(2024, 4, 3, 8, 0, 0, 0, time.UTC),
+		AnswerChosenBy: "octocat",
+		Poll:           &github.Poll{Question: "A or B?", TotalVotes: 1, Options: []github.PollOption{{Option: "A", Votes: 1}}},
+	}
+
+	result, err := renderJSON(DiscussionDocument(discussion), DefaultOptions())
+	if err != nil {
+		t.Fatalf("renderJSON() error = %v", err)
+	}
+
+	for _, expected := range []string{
+		`"category": {
+    "name": "Q&A",
+    "emoji": ":pray:",
+    "is_answerable": true
+  }`,
+		`"upvote_count": 2`,
+		`"answer_chosen_at": "2024-04-03T08:00:00Z"`,
+		`"answer_chosen_by": "octocat"`,
+		`"poll": {
+    "question": "A or B?",
+    "total_votes": 1,
+    "options": [
+      {
+        "option": "A",
+        "votes": 1
+      }
+    ]
+  }`,
+	} {
+		if !strings.Contains(string(result), expected) {
+			t.Errorf("renderJSON() missing %q, got:\n%s", expected, result)
+		}
+	}
+
+	var decoded struct {
+		Answer *struct {
+			Author struct{ Login string } `json:"author"`
+		} `json:"answer"`
+		Comments []struct {
+			IsAnswer bool `json:"is_answer"`
+			Replies  []struct {
+				Body string `json:"body"`
+			} `json:"replies"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if !decoded.Comments[0].IsAnswer {
+		t.Errorf("renderJSON() missing is_answer, got:\n%s", result)
+	}
+	if decoded.Answer == nil || decoded.Answer.Author.Login != "user1" {
+		t.Errorf("renderJSON() answer = %+v, want user1", decoded.Answer)
+	}
+	if len(decoded.Comments[0].Replies) != 1 || decoded.Comments[0].Replies[0].Body != "Thanks" {
+		t.Errorf("renderJSON() replies = %+v, want Thanks", decoded.Comments[0].Replies)
+	}
+	if strings.Count(string(result), `"replies"`) != 1 {
+		t.Errorf("renderJSON() should omit empty replies, got:\n%s", result)
+	}
+}
@@ -0,0 +1,122 @@
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// renderMetadata 渲染标题下方的元数据列表，只包含有值的项，没有任何项时返回空字符串
+// 每项一行，如 - **Closed:** as completed at 2024-01-05T12:00:00Z
+func renderMetadata(doc *Document) string {
+	var b strings.Builder
+	item := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "- **%s:** %s\n", name, value)
+		}
+	}
+
+	item("Category", categoryText(doc.Category))
+	item("Labels", joinCode(doc.Labels))
+	item("Assignees", joinLogins(doc.Assignees))
+	item("Milestone", milestoneText(doc.Milestone))
+	if doc.IsDraft {
+		item("Draft", "yes")
+	}
+	if doc.BaseRef != "" {
+		item("Branches", fmt.Sprintf("`%s` → `%s`", doc.HeadRef, doc.BaseRef))
+	}
+	item("Review requested", joinLogins(doc.RequestedReviewers))
+	item("Merged", mergedText(doc))
+	item("Answer chosen", answerChosenText(doc))
+	item("Closed", closedText(doc))
+	if doc.UpvoteCount > 0 {
+		item("Upvotes", strconv.Itoa(doc.UpvoteCount))
+	}
+	if doc.Locked {
+		item("Locked", "yes")
+	}
+	if !doc.UpdatedAt.IsZero() {
+		item("Updated", formatTime(doc.UpdatedAt))
+	}
+	return b.String()
+}
+
+// joinCode 将列表渲染为逗号分隔的行内代码，如 `bug`, `ui`
+func joinCode(list []string) string {
+	var parts []string
+	for _, s := range list {
+		parts = append(parts, fmt.Sprintf("`%s`", s))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// joinLogins 将登录名列表渲染为 @a, @b
+func joinLogins(logins []string) string {
+	var parts []string
+	for _, login := range logins {
+		parts = append(parts, "@"+login)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// milestoneText 返回里程碑描述，如 v1.0 (due 2024-03-01)，未设置时返回空字符串
+func milestoneText(m *github.Milestone) string {
+	if m == nil {
+		return ""
+	}
+	if m.DueOn.IsZero() {
+		return m.Title
+	}
+	return fmt.Sprintf("%s (due %s)", m.Title, m.DueOn.UTC().Format("2006-01-02"))
+}
+
+// mergedText 返回合并信息，如 by @octocat at 2024-02-03T12:00:00Z，未合并时返回空字符串
+func mergedText(doc *Document) string {
+	return byAtText(doc.MergedBy, doc.MergedAt)
+}
+
+// answerChosenText 返回采纳答案的信息，如 by @octocat at 2024-04-03T08:00:00Z，没有答案时返回空字符串
+func answerChosenText(doc *Document) string {
+	return byAtText(doc.AnswerChosenBy, doc.AnswerChosenAt)
+}
+
+// byAtText 返回 by @login at T，login 为空时省略 by 部分，at 为零值时返回空字符串
+func byAtText(login string, at time.Time) string {
+	if at.IsZero() {
+		return ""
+	}
+	if login == "" {
+		return "at " + formatTime(at)
+	}
+	return fmt.Sprintf("by @%s at %s", login, formatTime(at))
+}
+
+// closedText 返回关闭信息，如 as completed at 2024-01-05T12:00:00Z
+// 只在状态为 closed 时返回，已合并或重新打开时返回空字符串
+func closedText(doc *Document) string {
+	if doc.Status != "closed" || doc.ClosedAt.IsZero() {
+		return ""
+	}
+	if reason := stateReasonText(doc.StateReason); reason != "" {
+		return reason + " at " + formatTime(doc.ClosedAt)
+	}
+	return "at " + formatTime(doc.ClosedAt)
+}
+
+// stateReasonText 返回关闭原因的描述，如 as completed，未知原因返回空字符串
+func stateReasonText(reason string) string {
+	switch reason {
+	case "COMPLETED":
+		return "as completed"
+	case "NOT_PLANNED":
+		return "as not planned"
+	case "DUPLICATE":
+		return "as duplicate"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,117 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestRenderMetadata(t *testing.T) {
+	closedAt := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		doc      *Document
+		expected string
+	}{
+		{
+			name:     "没有元数据",
+			doc:      IssueDocument(&github.Issue{Thread: github.Thread{Title: "Empty", Status: "open"}}),
+			expected: "",
+		},
+		{
+			name: "重新打开的 Issue 不显示关闭信息",
+			doc: IssueDocument(&github.Issue{
+				Thread:      github.Thread{Status: "open", ClosedAt: closedAt},
+				StateReason: "REOPENED",
+			}),
+			expected: "",
+		},
+		{
+			name: "关闭原因未知",
+			doc: IssueDocument(&github.Issue{
+				Thread: github.Thread{Status: "closed", ClosedAt: closedAt, UpdatedAt: closedAt},
+			}),
+			expected: "- **Closed:** at 2024-01-05T12:00:00Z\n- **Updated:** 2024-01-05T12:00:00Z\n",
+		},
+		{
+			name: "Pull Request",
+			doc: PullRequestDocument(&github.PullRequest{
+				Thread:             github.Thread{Status: "open", Labels: []string{"enhancement"}},
+				Assignees:          []string{"octocat", "hubot"},
+				IsDraft:            true,
+				BaseRef:            "main",
+				HeadRef:            "feature",
+				RequestedReviewers: []string{"owner/core"},
+			}),
+			expected: "- **Labels:** `enhancement`\n- **Assignees:** @octocat, @hubot\n- **Draft:** yes\n" +
+				"- **Branches:** `feature` → `main`\n- **Review requested:** @owner/core\n",
+		},
+		{
+			name: "合并者未知",
+			doc: PullRequestDocument(&github.PullRequest{
+				Thread:   github.Thread{Status: "merged", ClosedAt: closedAt},
+				MergedAt: closedAt,
+			}),
+			expected: "- **Merged:** at 2024-01-05T12:00:00Z\n",
+		},
+		{
+			name: "Discussion",
+			doc: DiscussionDocument(&github.Discussion{
+				Thread:         github.Thread{Status: "open"},
+				Category:       &github.DiscussionCategory{Name: "Q&A", Emoji: ":pray:", IsAnswerable: true},
+				UpvoteCount:    3,
+				AnswerChosenAt: closedAt,
+				AnswerChosenBy: "octocat",
+			}),
+			expected: "- **Category:** :pray: Q&A\n- **Answer chosen:** by @octocat at 2024-01-05T12:00:00Z\n- **Upvotes:** 3\n",
+		},
+		{
+			name: "没有 emoji 的分类",
+			doc: DiscussionDocument(&github.Discussion{
+				Thread:   github.Thread{Status: "open"},
+				Category: &github.DiscussionCategory{Name: "General"},
+			}),
+			expected: "- **Category:** General\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := renderMetadata(tt.doc); result != tt.expected {
+				t.Errorf("renderMetadata() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderHTMLMetadata(t *testing.T) {
+	doc := PullRequestDocument(&github.PullRequest{
+		Thread:    github.Thread{Title: "Add retry", Status: "merged", UpdatedAt: time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)},
+		Milestone: &github.Milestone{Title: "v1.0", DueOn: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		MergedAt:  time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC),
+		MergedBy:  "maintainer",
+		BaseRef:   "main",
+		HeadRef:   "feature",
+	})
+
+	result, err := renderHTML(doc, DefaultOptions())
+	if err != nil {
+		t.Fatalf("renderHTML() error = %v", err)
+	}
+	for _, expected := range []string{
+		"<dt>Milestone</dt><dd>v1.0 (due 2024-03-01)</dd>",
+		"<dt>Branches</dt><dd><code>feature</code> → <code>main</code></dd>",
+		"<dt>Merged</dt><dd>by @maintainer at 2024-02-03T12:00:00Z</dd>",
+		`<dt>Updated</dt><dd><time datetime="2024-02-04T12:00:00Z">`,
+	} {
+		if !strings.Contains(string(result), expected) {
+			t.Errorf("renderHTML() missing %q, got:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(string(result), "<dt>Draft</dt>") {
+		t.Error("renderHTML() should not show Draft for a ready pull request")
+	}
+}
@@ -0,0 +1,78 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// MinimizedMode 被隐藏（minimized）的评论的导出方式
+type MinimizedMode string
+
+// 支持的隐藏评论导出方式
+const (
+	MinimizedCollapse MinimizedMode = "collapse" // 折叠到标注隐藏原因的 <details> 中（默认）
+	MinimizedOmit     MinimizedMode = "omit"     // 不导出
+	MinimizedInclude  MinimizedMode = "include"  // 与普通评论相同
+)
+
+// applyMinimized 按 opts.Minimized 处理被隐藏的评论，返回处理后的副本，不修改 doc
+// 只有 MinimizedOmit 会去掉评论、Discussion 回复和行内代码评审评论中被隐藏的评论（锚点评论除外），
+// 评论全部被去掉的行内代码评审讨论一并去掉；折叠在渲染时处理
+func applyMinimized(doc *Document, opts *Options) *Document {
+	if opts.Minimized != MinimizedOmit {
+		return doc
+	}
+
+	result := *doc
+	result.Comments = omitMinimized(doc.Comments, doc.Anchor)
+	result.ReviewThreads = nil
+	for _, thread := range doc.ReviewThreads {
+		thread.Comments = omitMinimized(thread.Comments, doc.Anchor)
+		if len(thread.Comments) > 0 {
+			result.ReviewThreads = append(result.ReviewThreads, thread)
+		}
+	}
+	return &result
+}
+
+// omitMinimized 返回去掉被隐藏评论及回复后的评论列表，保留锚点评论
+func omitMinimized(comments []github.Comment, anchor string) []github.Comment {
+	var result []github.Comment
+	for _, c := range comments {
+		if c.IsMinimized && !isAnchored(c, anchor) {
+			continue
+		}
+		if len(c.Replies) > 0 {
+			c.Replies = omitMinimized(c.Replies, anchor)
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// collapsed 判断评论是否需要折叠，opts.Minimized 为空时按 MinimizedCollapse 处理
+func collapsed(c github.Comment, opts *Options) bool {
+	return c.IsMinimized && (opts.Minimized == MinimizedCollapse || opts.Minimized == "")
+}
+
+// minimizedLabel 返回隐藏说明，如 Hidden as off-topic，原因未知时为 Hidden
+func minimizedLabel(reason string) string {
+	reason = strings.ReplaceAll(strings.ToLower(reason), "_", "-")
+	if reason == "" {
+		return "Hidden"
+	}
+	return "Hidden as " + reason
+}
+
+// renderCommentBody 返回评论正文，需要折叠时包裹在以隐藏说明为标题的 <details> 中
+func renderCommentBody(c github.Comment, opts *Options) string {
+	if !collapsed(c, opts) {
+		return c.Body
+	}
+	summary := "<details>\n<summary>" + minimizedLabel(c.MinimizedReason) + "</summary>\n"
+	if body := strings.TrimRight(c.Body, "\n"); body != "" {
+		summary += "\n" + body + "\n\n"
+	}
+	return summary + "</details>"
+}
@@ -0,0 +1,225 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestRenderCommentBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  github.Comment
+		mode     MinimizedMode
+		expected string
+	}{
+		{name: "普通评论", comment: github.Comment{Body: "Hi"}, mode: MinimizedCollapse, expected: "Hi"},
+		{
+			name:     "折叠",
+			comment:  github.Comment{Body: "+1\n", IsMinimized: true, MinimizedReason: "off-topic"},
+			mode:     MinimizedCollapse,
+			expected: "<details>\n<summary>Hidden as off-topic</summary>\n\n+1\n\n</details>",
+		},
+		{
+			name:     "默认折叠",
+			comment:  github.Comment{Body: "Buy now", IsMinimized: true, MinimizedReason: "SPAM"},
+			expected: "<details>\n<summary>Hidden as spam</summary>\n\nBuy now\n\n</details>",
+		},
+		{
+			name:     "原因未知且没有正文",
+			comment:  github.Comment{IsMinimized: true},
+			mode:     MinimizedCollapse,
+			expected: "<details>\n<summary>Hidden</summary>\n</details>",
+		},
+		{name: "原样导出", comment: github.Comment{Body: "+1", IsMinimized: true, MinimizedReason: "off-topic"}, mode: MinimizedInclude, expected: "+1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := renderCommentBody(tt.comment, &Options{Minimized: tt.mode}); result != tt.expected {
+				t.Errorf("renderCommentBody() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyMinimized(t *testing.T) {
+	doc := PullRequestDocument(&github.PullRequest{
+		Thread: github.Thread{
+			Comments: []github.Comment{
+				{Body: "First"},
+				{Body: "Spam", IsMinimized: true, MinimizedReason: "spam"},
+				{Body: "Linked", IsMinimized: true, URL: "https://github.com/owner/repo/pull/2#issuecomment-3"},
+			},
+		},
+		ReviewThreads: []github.ReviewThread{
+			{Path: "a.go", Comments: []github.Comment{{Body: "Outdated", IsMinimized: true, MinimizedReason: "outdated"}}},
+			{Path: "b.go", Comments: []github.Comment{{Body: "Why?"}, {Body: "Resolved", IsMinimized: true, MinimizedReason: "resolved"}}},
+		},
+	})
+	doc.Anchor = "issuecomment-3"
+
+	if result := applyMinimized(doc, &Options{Minimized: MinimizedCollapse}); result != doc {
+		t.Error("applyMinimized() should not change the document when collapsing")
+	}
+
+	result := applyMinimized(doc, &Options{Minimized: MinimizedOmit})
+	if len(result.Comments) != 2 || result.Comments[0].Body != "First" || result.Comments[1].Body != "Linked" {
+		t.Errorf("applyMinimized().Comments = %+v, want First and the anchored comment", result.Comments)
+	}
+	if len(result.ReviewThreads) != 1 || result.ReviewThreads[0].Path != "b.go" || len(result.ReviewThreads[0].Comments) != 1 {
+		t.Errorf("applyMinimized().ReviewThreads = %+v, want b.go with one comment", result.ReviewThreads)
+	}
+	if len(doc.Comments) != 3 || len(doc.ReviewThreads) != 2 {
+		t.Error("applyMinimized() should not modify the original document")
+	}
+}
+
+func TestApplyMinimizedReplies(t *testing.T) {
+	doc := DiscussionDocument(&github.Discussion{
+		Thread: github.Thread{
+			Comments: []github.Comment{
+				{Body: "Question", Replies: []github.Comment{{Body: "Off", IsMinimized: true}, {Body: "Reply"}}},
+			},
+		},
+	})
+
+	result := applyMinimized(doc, &Options{Minimized: MinimizedOmit})
+	if replies := result.Comments[0].Replies; len(replies) != 1 || replies[0].Body != "Reply" {
+		t.Errorf("applyMinimized() replies = %+v, want only Reply", replies)
+	}
+	if len(doc.Comments[0].Replies) != 2 {
+		t.Error("applyMinimized() should not modify the original replies")
+	}
+}
+
+func TestConvertMinimized(t *testing.T) {
+	doc := IssueDocument(&github.Issue{
+		Thread: github.Thread{
+			Title:  "Crash",
+			Status: "open",
+			Comments: []github.Comment{
+				{Author: "user1", Body: "Same here"},
+				{Author: "spammer", Body: "Buy **now**", IsMinimized: true, MinimizedReason: "spam"},
+			},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		opts       *Options
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:     "Markdown 折叠",
+			opts:     &Options{Format: FormatMarkdown, Minimized: MinimizedCollapse},
+			expected: []string{"### @spammer commented at 0001-01-01T00:00:00Z\n\n<details>\n<summary>Hidden as spam</summary>\n\nBuy **now**\n\n</details>\n"},
+		},
+		{
+			name:       "Markdown 不导出",
+			opts:       &Options{Format: FormatMarkdown, Minimized: MinimizedOmit},
+			expected:   []string{"@user1"},
+			unexpected: []string{"@spammer", "<details>"},
+		},
+		{
+			name:       "Markdown 原样导出",
+			opts:       &Options{Format: FormatMarkdown, Minimized: MinimizedInclude},
+			expected:   []string{"### @spammer commented at 0001-01-01T00:00:00Z\n\nBuy **now**\n"},
+			unexpected: []string{"<details>"},
+		},
+		{
+			name:     "HTML 折叠",
+			opts:     &Options{Format: FormatHTML, Minimized: MinimizedCollapse},
+			expected: []string{"<details class=\"minimized\">\n<summary>Hidden as spam</summary>\n<div class=\"markdown-body\">\n<p>Buy <strong>now</strong></p>\n</div>\n</details>"},
+		},
+		{
+			name:     "JSON",
+			opts:     &Options{Format: FormatJSON, Minimized: MinimizedCollapse},
+			expected: []string{`"is_minimized": true`, `"minimized_reason": "spam"`, `"body": "Buy **now**"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(doc, tt.opts)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(result), expected) {
+					t.Errorf("Convert() missing %q, got:\n%s", expected, result)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(string(result), unexpected) {
+					t.Errorf("Convert() should not contain %q, got:\n%s", unexpected, result)
+				}
+			}
+		})
+	}
+}
+
+// TestToMarkdownMinimized 测试 ToMarkdown 与 Convert 一样处理被隐藏的评论，并始终输出 Markdown
+func TestToMarkdownMinimized(t *testing.T) {
+	issue := &github.Issue{
+		Thread: github.Thread{
+			Title: "Crash",
+			Comments: []github.Comment{
+				{Author: "user1", Body: "Same here"},
+				{Author: "spammer", Body: "Buy now", IsMinimized: true, MinimizedReason: "spam"},
+			},
+		},
+	}
+
+	result, err := ToMarkdown(issue, &Options{Format: FormatJSON, Minimized: MinimizedOmit})
+	if err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	if !strings.Contains(string(result), "\n# Crash\n") || strings.Contains(string(result), "@spammer") {
+		t.Errorf("ToMarkdown() = %q, want Markdown without the hidden comment", result)
+	}
+
+}
+
+// TestConvertMinimizedTruncated 测试省略被隐藏的评论后，截断提示仍显示获取到的评论数
+func TestConvertMinimizedTruncated(t *testing.T) {
+	doc := IssueDocument(&github.Issue{
+		Thread: github.Thread{
+			Title:  "Crash",
+			Status: "open",
+			Comments: []github.Comment{
+				{Author: "user1", Body: "Same here"},
+				{Author: "spammer", Body: "Buy now", IsMinimized: true, MinimizedReason: "spam"},
+				{Author: "user2", Body: "+1"},
+			},
+			TotalComments:     10,
+			CommentsTruncated: true,
+		},
+	})
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{format: FormatMarkdown, expected: "> Showing 3 of 10 comments (truncated by max-comments limit)"},
+		{format: FormatHTML, expected: `<p class="notice">Showing 3 of 10 comments (truncated by max-comments limit)</p>`},
+		{format: FormatJSON, expected: `"comments_fetched": 3`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			result, err := Convert(doc, &Options{Format: tt.format, Minimized: MinimizedOmit})
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if !strings.Contains(string(result), tt.expected) {
+				t.Errorf("Convert() missing %q, got:\n%s", tt.expected, result)
+			}
+			if strings.Contains(string(result), "spammer") {
+				t.Errorf("Convert() should omit the hidden comment, got:\n%s", result)
+			}
+		})
+	}
+}
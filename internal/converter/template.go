@@ -0,0 +1,235 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// TemplateData 自定义模板（-template）的数据模型，Issue、Pull Request 和 Discussion 共用
+//
+// 可直接访问 Document 及 github.Thread 的字段，如 {{.Title}}、{{.Comments}}、{{.Reviews}}；
+// {{.Timeline}} 按时间顺序合并评论和时间线事件（启用 -enable-timeline 时）；
+// Discussion 评论的回复在 {{.Replies}} 中，被采纳的答案为 {{.Answer}}，分类和投票为 {{.Category}}、{{.Poll}}
+type TemplateData struct {
+	Document
+
+	EnableReactions bool // 是否启用了 -enable-reactions
+	EnableUserLinks bool // 是否启用了 -enable-user-links
+}
+
+// ParseTemplate 解析模板并注册辅助函数，source 为空时使用默认模板
+//
+// 辅助函数:
+//   - date T: 格式化为 UTC 的 RFC 3339 时间
+//   - user LOGIN URL: 渲染 @login，启用用户链接时渲染为 [@login](url)
+//   - badges TYPE ASSOCIATION: 渲染用户类型和角色标记，如 [bot]、(maintainer)，没有标记时为空字符串
+//   - reactions R: 渲染 Reactions 统计，如 "👍 5 ❤️ 3"
+//   - anchor URL: 返回评论链接中的锚点，如 issuecomment-456，可与 .Anchor 比较以突出显示评论
+//   - event E: 将 .Timeline 中的事件渲染为一行，如 @octocat added label `bug` at 2024-01-02T10:00:00Z
+//   - frontmatter DATA: 按 -frontmatter 和 -frontmatter-fields 渲染 Frontmatter，格式为 none 时为空字符串
+//   - metadata DATA: 渲染分类、标签、指派人、里程碑、分支、合并和关闭信息等元数据列表，没有元数据时为空字符串
+//   - yaml S: 为 YAML 字符串添加引号
+//   - fence S: 返回能包裹 S 的代码块围栏
+//   - showReview R / reviewVerb STATE / threadLocation T: Review 相关的展示辅助
+//   - files LIST: 将 .Files 渲染为变更统计和文件表格
+//   - diff F: 将文件的补丁渲染为 diff 代码块，超过 -max-diff-lines 时截断并附加提示，没有补丁时为空字符串
+//   - commentBody C: 返回评论正文，被隐藏的评论按 -minimized collapse 折叠到 <details> 中
+//   - editHistory EDITS: 按 -edit-history 渲染 .Edits 修订记录，未启用或没有修订时为空字符串
+//   - poll P: 将 Discussion 的 .Poll 渲染为问题、结果表格和总票数
+//   - include NAME DATA: 执行命名模板并返回结果字符串
+//   - trimRight S CUTSET: 去除 S 末尾属于 CUTSET 的字符
+//   - join LIST SEP: 用 SEP 连接字符串列表，如 {{join .Labels ", "}}
+func ParseTemplate(source string, opts *Options) (*template.Template, error) {
+	if source == "" {
+		source = DefaultTemplate
+	}
+
+	tmpl := template.New("markdown")
+	tmpl.Funcs(template.FuncMap{
+		"date": formatTime,
+		"user": func(login, url string) string {
+			return renderUser(login, url, opts.EnableUserLinks)
+		},
+		"badges":    renderBadges,
+		"reactions": renderReactions,
+		"anchor":    commentAnchor,
+		"event": func(e *github.Event) string {
+			return renderEvent(*e, opts.EnableUserLinks)
+		},
+		"frontmatter": func(data *TemplateData) string {
+			return renderFrontmatter(&data.Document, opts.Frontmatter, opts.FrontmatterFields)
+		},
+		"metadata": func(data *TemplateData) string {
+			return renderMetadata(&data.Document)
+		},
+		"yaml":           quoteYAML,
+		"fence":          codeFence,
+		"showReview":     showReview,
+		"reviewVerb":     reviewVerb,
+		"threadLocation": renderThreadLocation,
+		"files":          renderFileTable,
+		"diff": func(f github.ChangedFile) string {
+			return renderDiff(f, opts.MaxDiffLines)
+		},
+		"poll": renderPoll,
+		"editHistory": func(edits []github.Edit) string {
+			return renderEditHistory(edits, opts)
+		},
+		"commentBody": func(c github.Comment) string {
+			return renderCommentBody(c, opts)
+		},
+		"include": func(name string, data any) (string, error) {
+			var buf bytes.Buffer
+			if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		},
+		"trimRight": strings.TrimRight,
+		"join":      strings.Join,
+	})
+
+	if _, err := tmpl.Parse(source); err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderMarkdown 使用 opts.Template（为空时使用默认模板）将 Document 渲染为 Markdown
+func renderMarkdown(doc *Document, opts *Options) ([]byte, error) {
+	data := &TemplateData{
+		Document:        *doc,
+		EnableReactions: opts.EnableReactions,
+		EnableUserLinks: opts.EnableUserLinks,
+	}
+
+	tmpl, err := ParseTemplate(opts.Template, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DefaultTemplate 默认的 Markdown 模板，可作为自定义模板的起点
+const DefaultTemplate = `{{with frontmatter .}}{{.}}
+{{end}}# {{.Title}}
+
+{{with metadata .}}{{.}}
+{{end}}{{if .Body}}{{.Body}}
+{{end}}
+{{- with editHistory .Edits}}{{.}}
+{{end}}
+{{- with .Poll}}## Poll
+
+{{poll .}}
+{{end}}
+{{- if and .EnableReactions .Reactions}}## Reactions
+
+{{reactions .Reactions}}
+{{end}}
+{{- with .Answer}}---
+
+## Accepted Answer
+
+### {{user .Author .AuthorURL}}{{with badges .AuthorType .AuthorAssociation}} {{.}}{{end}} answered at {{date .CreatedAt}}
+
+{{with commentBody .}}{{.}}
+{{end}}
+{{- with editHistory .Edits}}{{.}}
+{{end}}
+{{- end}}
+{{- if or .Comments .Events}}---
+
+## Comments
+
+{{range $i, $item := .Timeline}}{{with .Event}}{{if $i}}
+{{end}}{{event .}}
+{{else with .Comment}}### {{user .Author .AuthorURL}}{{with badges .AuthorType .AuthorAssociation}} {{.}}{{end}} commented at {{date .CreatedAt}}
+
+{{with commentBody .}}{{.}}
+{{end}}
+{{- with editHistory .Edits}}{{.}}
+{{end}}
+{{- if .IsAnswer}}✅ **Answer**
+{{end}}
+{{- if and $.Anchor (eq (anchor .URL) $.Anchor)}}📌 **Linked comment**
+{{end}}
+{{- if and $.EnableReactions .Reactions}}{{reactions .Reactions}}
+{{end}}
+{{- range .Replies}}#### {{user .Author .AuthorURL}}{{with badges .AuthorType .AuthorAssociation}} {{.}}{{end}} replied at {{date .CreatedAt}}
+
+{{with commentBody .}}{{.}}
+{{end}}
+{{- with editHistory .Edits}}{{.}}
+{{end}}
+{{- if .IsAnswer}}✅ **Answer**
+{{end}}
+{{- if and $.Anchor (eq (anchor .URL) $.Anchor)}}📌 **Linked comment**
+{{end}}
+{{- if and $.EnableReactions .Reactions}}{{reactions .Reactions}}
+{{end}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .CommentsTruncated}}
+> Showing {{.FetchedComments}} of {{.TotalComments}} comments (truncated by max-comments limit)
+{{end}}
+{{- with include "review" .}}
+---
+
+## Review
+
+{{trimRight . "\n"}}
+{{end}}
+{{- if .Files}}
+---
+
+## Files Changed
+
+{{files .Files}}
+{{- if .DiffTooLarge}}
+> Diff not included: too large for the GitHub API
+{{end}}
+{{- range .Files}}{{$diff := diff .}}{{if $diff}}
+### ` + "`{{.Path}}`" + `
+
+{{$diff}}{{end}}{{end}}
+{{- end}}
+{{- define "review"}}
+{{- range .Reviews}}{{if showReview .}}### {{user .Author .AuthorURL}}{{with badges .AuthorType .AuthorAssociation}} {{.}}{{end}} {{reviewVerb .State}} at {{date .SubmittedAt}}
+
+{{if .Body}}{{.Body}}
+
+{{end}}
+{{- end}}
+{{- end}}
+{{- range .ReviewThreads}}### {{threadLocation .}}
+
+{{if .DiffHunk}}{{$fence := fence .DiffHunk}}{{$fence}}diff
+{{.DiffHunk}}
+{{$fence}}
+
+{{end}}
+{{- range .Comments}}#### {{user .Author .AuthorURL}}{{with badges .AuthorType .AuthorAssociation}} {{.}}{{end}} commented at {{date .CreatedAt}}
+
+{{with commentBody .}}{{.}}
+{{end}}
+{{- with editHistory .Edits}}{{.}}
+{{end}}
+{{- if and $.Anchor (eq (anchor .URL) $.Anchor)}}📌 **Linked comment**
+{{end}}
+{{- if and $.EnableReactions .Reactions}}{{reactions .Reactions}}
+{{end}}
+{{end}}
+{{- end}}
+{{- end}}`
@@ -0,0 +1,305 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// goldenIssue 覆盖元数据、Reactions、用户链接和标记、截断和以换行结尾的正文
+func goldenIssue() *github.Issue {
+	return &github.Issue{
+		Thread: github.Thread{
+			Title:     "Crash when 'config' is missing",
+			Body:      "Steps to reproduce:\n\n1. delete config\n2. run\n",
+			Author:    "octocat",
+			AuthorURL: "https://github.com/octocat",
+			CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			ClosedAt:  time.Date(2024, 1, 4, 8, 0, 0, 0, time.UTC),
+			Status:    "closed",
+			URL:       "https://github.com/owner/repo/issues/1",
+			Labels:    []string{"bug", "good first issue"},
+			Reactions: &github.Reactions{ThumbsUp: 3, Eyes: 1},
+			Comments: []github.Comment{
+				{Author: "user1", AuthorURL: "https://github.com/user1", Body: "Same here", CreatedAt: time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC), Reactions: &github.Reactions{Heart: 2}},
+				{Author: "user2", AuthorURL: "https://github.com/apps/user2", AuthorType: "Bot", CreatedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))},
+				{Author: "octocat", AuthorURL: "https://github.com/octocat", AuthorAssociation: "OWNER", Body: "Fixed in #2\n", CreatedAt: time.Date(2024, 1, 4, 8, 0, 0, 0, time.UTC)},
+			},
+			TotalComments:     10,
+			CommentsTruncated: true,
+		},
+		Assignees:   []string{"octocat"},
+		Milestone:   &github.Milestone{Title: "v1.0", DueOn: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		StateReason: "COMPLETED",
+	}
+}
+
+// goldenPR 覆盖合并信息、Review 总结、被跳过的 Review、行内讨论和变更文件，最后一条回复以空行结尾
+func goldenPR() *github.PullRequest {
+	return &github.PullRequest{
+		Thread: github.Thread{
+			Title:     "Add \"retry\" support",
+			Body:      "Closes #1",
+			Author:    "octocat",
+			AuthorURL: "https://github.com/octocat",
+			CreatedAt: time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+			Status:    "merged",
+			URL:       "https://github.com/owner/repo/pull/2",
+			Comments: []github.Comment{
+				{Author: "user1", AuthorURL: "https://github.com/user1", AuthorAssociation: "FIRST_TIME_CONTRIBUTOR", Body: "Nice!", CreatedAt: time.Date(2024, 2, 2, 9, 0, 0, 0, time.UTC)},
+			},
+		},
+		MergedAt: time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC),
+		MergedBy: "reviewer",
+		BaseRef:  "main",
+		HeadRef:  "retry",
+
+		Reviews: []github.Review{
+			{Author: "reviewer", AuthorURL: "https://github.com/reviewer", State: "COMMENTED", SubmittedAt: time.Date(2024, 2, 2, 10, 0, 0, 0, time.UTC)},
+			{Author: "reviewer", AuthorURL: "https://github.com/reviewer", AuthorAssociation: "MEMBER", State: "CHANGES_REQUESTED", Body: "Please add tests", SubmittedAt: time.Date(2024, 2, 2, 11, 0, 0, 0, time.UTC)},
+			{Author: "pending", AuthorURL: "https://github.com/pending", State: "PENDING", Body: "draft"},
+			{Author: "reviewer", AuthorURL: "https://github.com/reviewer", State: "APPROVED", SubmittedAt: time.Date(2024, 2, 3, 11, 0, 0, 0, time.UTC)},
+		},
+		ReviewThreads: []github.ReviewThread{
+			{
+				Path:       "retry.go",
+				Line:       12,
+				StartLine:  10,
+				DiffHunk:   "@@ -1,3 +1,4 @@\n+// ```go\n func retry() {}",
+				IsResolved: true,
+				Comments: []github.Comment{
+					{Author: "reviewer", AuthorURL: "https://github.com/reviewer", Body: "Why?", CreatedAt: time.Date(2024, 2, 2, 11, 0, 0, 0, time.UTC), Reactions: &github.Reactions{Confused: 1}},
+					{Author: "octocat", AuthorURL: "https://github.com/octocat", Body: "Because.", CreatedAt: time.Date(2024, 2, 2, 12, 0, 0, 0, time.UTC)},
+				},
+			},
+			{
+				Path:       "README.md",
+				IsOutdated: true,
+				Comments: []github.Comment{
+					{Author: "reviewer", AuthorURL: "https://github.com/reviewer", Body: "Typo\n\n", CreatedAt: time.Date(2024, 2, 2, 13, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+		Files: []github.ChangedFile{
+			{Path: "retry.go", ChangeType: "MODIFIED", Additions: 2, Deletions: 0, Patch: "@@ -1,3 +1,4 @@\n+// ```go\n func retry() {}"},
+			{Path: "logo.png", ChangeType: "ADDED"},
+		},
+	}
+}
+
+// goldenPRReviewsOnly 只有 Review 总结，最后一个 Review 正文以换行结尾
+func goldenPRReviewsOnly() *github.PullRequest {
+	return &github.PullRequest{
+		Thread: github.Thread{
+			Title:     "Docs",
+			Author:    "octocat",
+			AuthorURL: "https://github.com/octocat",
+			CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			Status:    "open",
+			URL:       "https://github.com/owner/repo/pull/3",
+			Locked:    true,
+		},
+		IsDraft:            true,
+		RequestedReviewers: []string{"reviewer", "owner/docs"},
+		Files:              []github.ChangedFile{{Path: "docs/guide.md", ChangeType: "RENAMED", Additions: 1, Deletions: 1}},
+		DiffTooLarge:       true,
+
+		Reviews: []github.Review{
+			{Author: "reviewer", AuthorURL: "https://github.com/reviewer", State: "APPROVED", Body: "LGTM\n", SubmittedAt: time.Date(2024, 3, 2, 11, 0, 0, 0, time.UTC)},
+			{Author: "reviewer", AuthorURL: "https://github.com/reviewer", State: "COMMENTED", SubmittedAt: time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+}
+
+// goldenDiscussion 覆盖分类、投票、Answer 标记、被采纳的答案和评论的回复
+func goldenDiscussion() *github.Discussion {
+	answer := github.Comment{Author: "expert", AuthorURL: "https://github.com/expert", Body: "Use a config file.", CreatedAt: time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC), IsAnswer: true, Reactions: &github.Reactions{Rocket: 1}}
+	answer.Replies = []github.Comment{
+		{Author: "octocat", AuthorURL: "https://github.com/octocat", Body: "Which file?", CreatedAt: time.Date(2024, 4, 2, 9, 30, 0, 0, time.UTC)},
+		{Author: "expert", AuthorURL: "https://github.com/expert", Body: "`config.yaml` in the repo root.", CreatedAt: time.Date(2024, 4, 2, 9, 45, 0, 0, time.UTC), Reactions: &github.Reactions{Heart: 1}},
+	}
+
+	return &github.Discussion{
+		Thread: github.Thread{
+			Title:     "How to configure?",
+			Body:      "Question body",
+			Author:    "octocat",
+			AuthorURL: "https://github.com/octocat",
+			CreatedAt: time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC),
+			Status:    "open",
+			URL:       "https://github.com/owner/repo/discussions/4",
+			Comments: []github.Comment{
+				answer,
+				{Author: "octocat", AuthorURL: "https://github.com/octocat", Body: "Thanks!", CreatedAt: time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+		Category:       &github.DiscussionCategory{Name: "Q&A", Emoji: ":pray:", IsAnswerable: true},
+		UpvoteCount:    3,
+		Answer:         &answer,
+		AnswerChosenAt: time.Date(2024, 4, 3, 8, 0, 0, 0, time.UTC),
+		AnswerChosenBy: "octocat",
+		Poll: &github.Poll{
+			Question:   "Which format?",
+			TotalVotes: 3,
+			Options:    []github.PollOption{{Option: "YAML", Votes: 2}, {Option: "TOML", Votes: 1}},
+		},
+	}
+}
+
+func TestToMarkdownGolden(t *testing.T) {
+	options := []struct {
+		suffix string
+		opts   *Options
+	}{
+		{suffix: "", opts: DefaultOptions()},
+		{suffix: "-all", opts: &Options{EnableReactions: true, EnableUserLinks: true}},
+	}
+
+	tests := []struct {
+		name    string
+		convert func(opts *Options) ([]byte, error)
+	}{
+		{name: "issue", convert: func(opts *Options) ([]byte, error) { return ToMarkdown(goldenIssue(), opts) }},
+		{name: "pull", convert: func(opts *Options) ([]byte, error) { return ToMarkdownPR(goldenPR(), opts) }},
+		{name: "pull-reviews-only", convert: func(opts *Options) ([]byte, error) { return ToMarkdownPR(goldenPRReviewsOnly(), opts) }},
+		{name: "discussion", convert: func(opts *Options) ([]byte, error) { return ToMarkdownDiscussion(goldenDiscussion(), opts) }},
+	}
+
+	for _, tt := range tests {
+		for _, o := range options {
+			name := tt.name + o.suffix
+			t.Run(name, func(t *testing.T) {
+				result, err := tt.convert(o.opts)
+				if err != nil {
+					t.Fatalf("convert error = %v", err)
+				}
+
+				golden := filepath.Join("testdata", name+".md")
+				if os.Getenv("UPDATE_GOLDEN") != "" {
+					if err := os.WriteFile(golden, result, 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				expected, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("failed to read golden file: %v", err)
+				}
+				if string(result) != string(expected) {
+					t.Errorf("output mismatch with %s:\nGot:\n%s\n\nWant:\n%s", golden, result, expected)
+				}
+			})
+		}
+	}
+}
+
+func TestCustomTemplate(t *testing.T) {
+	issue := goldenIssue()
+	issue.Labels = []string{"bug", "p1"}
+
+	tests := []struct {
+		name     string
+		template string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "字段和辅助函数",
+			template: "{{.Type}}: {{.Title}} by {{user .Author .AuthorURL}} on {{date .CreatedAt}} [{{join .Labels \", \"}}]",
+			expected: "issue: Crash when 'config' is missing by @octocat on 2024-01-01T12:00:00Z [bug, p1]",
+		},
+		{
+			name:     "用户链接和 Reactions",
+			template: "{{user .Author .AuthorURL}} {{reactions .Reactions}}",
+			opts:     Options{EnableUserLinks: true},
+			expected: "[@octocat](https://github.com/octocat) 👍 3 👀 1",
+		},
+		{
+			name:     "遍历评论",
+			template: "{{range .Comments}}- {{.Author}}{{if .Reactions}} ({{reactions .Reactions}}){{end}}\n{{end}}{{if .CommentsTruncated}}{{len .Comments}}/{{.TotalComments}}{{end}}",
+			expected: "- user1 (❤️ 2)\n- user2\n- octocat\n3/10",
+		},
+		{
+			name:     "include 和 trimRight",
+			template: `{{define "t"}}{{.Title}}!!!{{end}}{{trimRight (include "t" .) "!"}}`,
+			expected: "Crash when 'config' is missing",
+		},
+		{
+			name:     "yaml 引号",
+			template: "title: {{yaml .Title}}",
+			expected: "title: 'Crash when ''config'' is missing'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Template = tt.template
+
+			result, err := ToMarkdown(issue, &opts)
+			if err != nil {
+				t.Fatalf("ToMarkdown() error = %v", err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("ToMarkdown() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCustomTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "语法错误", template: "{{.Title", expected: "failed to parse template"},
+		{name: "未知函数", template: "{{nope .Title}}", expected: "failed to parse template"},
+		{name: "未知字段", template: "{{.Nope}}", expected: "failed to execute template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ToMarkdown(goldenIssue(), &Options{Template: tt.template})
+			if err == nil {
+				t.Fatalf("ToMarkdown() with template %q expected error, got nil", tt.template)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ToMarkdown() error = %q, want containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToMarkdownFrontmatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   Options
+		prefix string
+	}{
+		{name: "YAML", opts: Options{Frontmatter: FrontmatterYAML}, prefix: "---\ntitle: 'Crash when ''config'' is missing'\n"},
+		{name: "TOML", opts: Options{Frontmatter: FrontmatterTOML}, prefix: "+++\ntitle = \"Crash when 'config' is missing\"\n"},
+		{name: "JSON", opts: Options{Frontmatter: FrontmatterJSON}, prefix: "{\n  \"title\": \"Crash when 'config' is missing\",\n"},
+		{name: "None", opts: Options{Frontmatter: FrontmatterNone}, prefix: "# Crash when 'config' is missing\n\n- **Labels:**"},
+		{
+			name:   "字段选择",
+			opts:   Options{FrontmatterFields: []FrontmatterField{{Name: "title", Key: "name"}}},
+			prefix: "---\nname: 'Crash when ''config'' is missing'\n---\n\n# Crash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ToMarkdown(goldenIssue(), &tt.opts)
+			if err != nil {
+				t.Fatalf("ToMarkdown() error = %v", err)
+			}
+			if !strings.HasPrefix(string(result), tt.prefix) {
+				t.Errorf("ToMarkdown() should start with %q, got:\n%s", tt.prefix, result)
+			}
+		})
+	}
+}
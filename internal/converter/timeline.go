@@ -0,0 +1,98 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// TimelineItem 时间线中的一项，Comment 和 Event 只有一个非 nil
+type TimelineItem struct {
+	Comment *github.Comment
+	Event   *github.Event
+}
+
+// Timeline 按时间顺序合并 Comments 和 Events，时间相同时评论在前
+// 没有 Events 时与 Comments 的顺序一致
+func (d *Document) Timeline() []TimelineItem {
+	items := make([]TimelineItem, 0, len(d.Comments)+len(d.Events))
+	i, j := 0, 0
+	for i < len(d.Comments) || j < len(d.Events) {
+		if j == len(d.Events) || (i < len(d.Comments) && !d.Events[j].CreatedAt.Before(d.Comments[i].CreatedAt)) {
+			items = append(items, TimelineItem{Comment: &d.Comments[i]})
+			i++
+		} else {
+			items = append(items, TimelineItem{Event: &d.Events[j]})
+			j++
+		}
+	}
+	return items
+}
+
+// renderEvent 将时间线事件渲染为一行 Markdown，如 @octocat added label `bug` at 2024-01-02T10:00:00Z
+func renderEvent(e github.Event, enableLinks bool) string {
+	return fmt.Sprintf("%s %s at %s", renderUser(e.Actor, e.ActorURL, enableLinks), eventAction(e), formatTime(e.CreatedAt))
+}
+
+// eventAction 返回事件的动作描述，不含操作者和时间
+func eventAction(e github.Event) string {
+	switch e.Type {
+	case github.EventLabeled:
+		return fmt.Sprintf("added label `%s`", e.Label)
+	case github.EventUnlabeled:
+		return fmt.Sprintf("removed label `%s`", e.Label)
+	case github.EventAssigned:
+		if e.Assignee == e.Actor {
+			return "self-assigned this"
+		}
+		return fmt.Sprintf("assigned @%s", e.Assignee)
+	case github.EventUnassigned:
+		if e.Assignee == e.Actor {
+			return "removed their assignment"
+		}
+		return fmt.Sprintf("unassigned @%s", e.Assignee)
+	case github.EventClosed:
+		action := "closed this"
+		if reason := stateReasonText(e.StateReason); reason != "" {
+			action += " " + reason
+		}
+		if e.Source != nil {
+			action += " in " + eventLink(e.Source)
+		}
+		return action
+	case github.EventReopened:
+		return "reopened this"
+	case github.EventReferenced:
+		if e.Source != nil {
+			return "referenced this in commit " + eventLink(e.Source)
+		}
+		return "referenced this in a commit"
+	case github.EventCrossReferenced:
+		if e.Source != nil {
+			return "mentioned this in " + eventLink(e.Source)
+		}
+		return "mentioned this"
+	case github.EventRenamed:
+		return fmt.Sprintf("changed the title from %q to %q", e.PreviousTitle, e.CurrentTitle)
+	case github.EventMilestoned:
+		return fmt.Sprintf("added this to the `%s` milestone", e.Milestone)
+	case github.EventDemilestoned:
+		return fmt.Sprintf("removed this from the `%s` milestone", e.Milestone)
+	case github.EventMerged:
+		action := "merged this"
+		if e.Source != nil {
+			action = "merged commit " + eventLink(e.Source)
+		}
+		if e.Ref != "" {
+			action += fmt.Sprintf(" into `%s`", e.Ref)
+		}
+		return action
+	default:
+		return e.Type
+	}
+}
+
+// eventLink 渲染事件关联对象的 Markdown 链接
+func eventLink(source *github.EventSource) string {
+	return fmt.Sprintf("[%s](%s)", source.Title, source.URL)
+}
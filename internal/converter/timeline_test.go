@@ -0,0 +1,128 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// timelineDocument 返回包含两条评论和三个时间线事件的 Issue
+func timelineDocument() *Document {
+	at := func(day int) time.Time {
+		return time.Date(2024, 1, day, 10, 0, 0, 0, time.UTC)
+	}
+	return IssueDocument(&github.Issue{
+		Thread: github.Thread{
+			Title: "Timeline",
+			Comments: []github.Comment{
+				{Author: "user1", Body: "First", CreatedAt: at(2)},
+				{Author: "user2", Body: "Second", CreatedAt: at(4)},
+			},
+			Events: []github.Event{
+				{Type: github.EventLabeled, Actor: "octocat", CreatedAt: at(1), Label: "bug"},
+				{Type: github.EventCrossReferenced, Actor: "hubot", CreatedAt: at(2),
+					Source: &github.EventSource{Title: "Fix bug", URL: "https://github.com/owner/repo/pull/2"}},
+				{Type: github.EventClosed, Actor: "hubot", CreatedAt: at(5), StateReason: "COMPLETED"},
+			},
+		},
+	})
+}
+
+func TestDocumentTimeline(t *testing.T) {
+	var order []string
+	for _, item := range timelineDocument().Timeline() {
+		if item.Comment != nil {
+			order = append(order, item.Comment.Body)
+		} else {
+			order = append(order, item.Event.Type)
+		}
+	}
+
+	// 时间相同时评论在前
+	expected := "labeled,First,cross_referenced,Second,closed"
+	if strings.Join(order, ",") != expected {
+		t.Errorf("Timeline() = %v, want %s", order, expected)
+	}
+}
+
+func TestRenderEvent(t *testing.T) {
+	at := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	commit := &github.EventSource{Title: "abc1234", URL: "https://github.com/owner/repo/commit/abc1234"}
+
+	tests := []struct {
+		name     string
+		event    github.Event
+		expected string
+	}{
+		{name: "添加标签", event: github.Event{Type: github.EventLabeled, Label: "bug"}, expected: "@octocat added label `bug` at 2024-01-02T10:00:00Z"},
+		{name: "移除标签", event: github.Event{Type: github.EventUnlabeled, Label: "bug"}, expected: "@octocat removed label `bug` at 2024-01-02T10:00:00Z"},
+		{name: "指派", event: github.Event{Type: github.EventAssigned, Assignee: "hubot"}, expected: "@octocat assigned @hubot at 2024-01-02T10:00:00Z"},
+		{name: "指派自己", event: github.Event{Type: github.EventAssigned, Assignee: "octocat"}, expected: "@octocat self-assigned this at 2024-01-02T10:00:00Z"},
+		{name: "取消指派", event: github.Event{Type: github.EventUnassigned, Assignee: "hubot"}, expected: "@octocat unassigned @hubot at 2024-01-02T10:00:00Z"},
+		{name: "关闭", event: github.Event{Type: github.EventClosed, StateReason: "NOT_PLANNED"}, expected: "@octocat closed this as not planned at 2024-01-02T10:00:00Z"},
+		{name: "由 commit 关闭", event: github.Event{Type: github.EventClosed, StateReason: "COMPLETED", Source: commit},
+			expected: "@octocat closed this as completed in [abc1234](https://github.com/owner/repo/commit/abc1234) at 2024-01-02T10:00:00Z"},
+		{name: "重新打开", event: github.Event{Type: github.EventReopened}, expected: "@octocat reopened this at 2024-01-02T10:00:00Z"},
+		{name: "commit 引用", event: github.Event{Type: github.EventReferenced, Source: commit},
+			expected: "@octocat referenced this in commit [abc1234](https://github.com/owner/repo/commit/abc1234) at 2024-01-02T10:00:00Z"},
+		{name: "交叉引用", event: github.Event{Type: github.EventCrossReferenced, Source: &github.EventSource{Title: "Fix bug", URL: "https://github.com/owner/repo/pull/2"}},
+			expected: "@octocat mentioned this in [Fix bug](https://github.com/owner/repo/pull/2) at 2024-01-02T10:00:00Z"},
+		{name: "修改标题", event: github.Event{Type: github.EventRenamed, PreviousTitle: "Old", CurrentTitle: "New"}, expected: `@octocat changed the title from "Old" to "New" at 2024-01-02T10:00:00Z`},
+		{name: "设置里程碑", event: github.Event{Type: github.EventMilestoned, Milestone: "v1.0"}, expected: "@octocat added this to the `v1.0` milestone at 2024-01-02T10:00:00Z"},
+		{name: "移除里程碑", event: github.Event{Type: github.EventDemilestoned, Milestone: "v1.0"}, expected: "@octocat removed this from the `v1.0` milestone at 2024-01-02T10:00:00Z"},
+		{name: "合并", event: github.Event{Type: github.EventMerged, Ref: "main", Source: commit},
+			expected: "@octocat merged commit [abc1234](https://github.com/owner/repo/commit/abc1234) into `main` at 2024-01-02T10:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.Actor = "octocat"
+			tt.event.CreatedAt = at
+			if result := renderEvent(tt.event, false); result != tt.expected {
+				t.Errorf("renderEvent() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertTimeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		expected []string
+	}{
+		{
+			name:   "Markdown",
+			format: FormatMarkdown,
+			expected: []string{"## Comments\n\n@octocat added label `bug` at 2024-01-01T10:00:00Z\n### @user1 commented at 2024-01-02T10:00:00Z\n\nFirst\n\n" +
+				"@hubot mentioned this in [Fix bug](https://github.com/owner/repo/pull/2) at 2024-01-02T10:00:00Z\n### @user2 commented at 2024-01-04T10:00:00Z\n\nSecond\n\n" +
+				"@hubot closed this as completed at 2024-01-05T10:00:00Z\n"},
+		},
+		{
+			name:     "HTML",
+			format:   FormatHTML,
+			expected: []string{`<div class="event"><p>@octocat added label <code>bug</code> at 2024-01-01T10:00:00Z</p>`, `id="comment-1"`, `id="comment-2"`},
+		},
+		{
+			name:     "JSON",
+			format:   FormatJSON,
+			expected: []string{`"type": "cross_referenced"`, `"url": "https://github.com/owner/repo/pull/2"`, `"state_reason": "COMPLETED"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(timelineDocument(), &Options{Format: tt.format})
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(result), expected) {
+					t.Errorf("Convert() missing %q, got:\n%s", expected, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadURLList 读取以换行分隔的 URL 列表
+// 忽略空行以及以 # 开头的注释行，行内 " #" 之后的内容也视为注释；
+// # 后紧跟数字的行是 #N 形式的简写引用，不是注释
+func ReadURLList(r io.Reader) ([]string, error) {
+	var urls []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if isComment(line) {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URL list: %w", err)
+	}
+
+	return urls, nil
+}
+
+// isComment 判断是否为注释行：以 # 开头且 # 后不是数字（#12 是简写引用）
+func isComment(line string) bool {
+	if !strings.HasPrefix(line, "#") {
+		return false
+	}
+	rest := line[1:]
+	return rest == "" || rest[0] < '0' || rest[0] > '9'
+}
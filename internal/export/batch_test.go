@@ -0,0 +1,41 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadURLList(t *testing.T) {
+	input := `# 待归档的 Issue
+https://github.com/owner/repo/issues/1
+
+  https://github.com/owner/repo/pull/2  # 发布前的讨论
+https://github.com/owner/repo/issues/3#issuecomment-4
+	# 缩进的注释
+#
+#12
+!34  # 简写引用
+#todo
+`
+
+	urls, err := ReadURLList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadURLList() unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"https://github.com/owner/repo/issues/1",
+		"https://github.com/owner/repo/pull/2",
+		"https://github.com/owner/repo/issues/3#issuecomment-4",
+		"#12",
+		"!34",
+	}
+	if len(urls) != len(expected) {
+		t.Fatalf("ReadURLList() = %q, want %q", urls, expected)
+	}
+	for i := range urls {
+		if urls[i] != expected[i] {
+			t.Errorf("ReadURLList()[%d] = %q, want %q", i, urls[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wangyulu/issue2md2/internal/assets"
+	"github.com/wangyulu/issue2md2/internal/converter"
+	"github.com/wangyulu/issue2md2/internal/github"
+	"github.com/wangyulu/issue2md2/internal/parser"
+)
+
+// Resolve 通过 API 确定简写引用 owner/repo#N 的实际类型并写回 resource.Type，其他资源不变
+func Resolve(ctx context.Context, client *github.Client, resource *parser.Resource) error {
+	if resource.Type != parser.ResourceTypeAmbiguous {
+		return nil
+	}
+
+	typ, err := client.ResolveNumber(ctx, resource.Owner, resource.Repo, resource.Number)
+	if err != nil {
+		return err
+	}
+	resource.Type = parser.ResourceType(typ)
+	return nil
+}
+
+// Fetch 获取资源数据并转换为统一的 converter.Document，URL 中的评论锚点记录到 Document.Anchor
+// 简写引用会先通过 Resolve 确定类型
+func Fetch(ctx context.Context, client *github.Client, resource *parser.Resource) (*converter.Document, error) {
+	if err := Resolve(ctx, client, resource); err != nil {
+		return nil, err
+	}
+
+	var doc *converter.Document
+	switch resource.Type {
+	case parser.ResourceTypeIssue:
+		issue, err := client.FetchIssue(ctx, resource.Owner, resource.Repo, resource.Number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch issue: %w", err)
+		}
+		doc = converter.IssueDocument(issue)
+
+	case parser.ResourceTypePullRequest:
+		pr, err := client.FetchPullRequest(ctx, resource.Owner, resource.Repo, resource.Number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pull request: %w", err)
+		}
+		doc = converter.PullRequestDocument(pr)
+
+	case parser.ResourceTypeDiscussion:
+		discussion, err := client.FetchDiscussion(ctx, resource.Owner, resource.Repo, resource.Number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch discussion: %w", err)
+		}
+		doc = converter.DiscussionDocument(discussion)
+
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %s", resource.Type)
+	}
+
+	doc.Anchor = resource.Anchor
+	return doc, nil
+}
+
+// Render 获取资源数据并按 opts.Format 转换为对应格式
+func Render(ctx context.Context, client *github.Client, resource *parser.Resource, opts *converter.Options) ([]byte, error) {
+	doc, err := Fetch(ctx, client, resource)
+	if err != nil {
+		return nil, err
+	}
+	return convert(doc, resource, opts)
+}
+
+// WriteFile 获取资源数据，按 opts.Format 转换后写入 path
+// downloader 不为 nil 时，先将正文中的图片和附件下载到 path 所在目录的 assets 子目录，并改写为相对链接；
+// 只下载按锚点和 -minimized 筛选后实际输出的内容中引用的文件
+func WriteFile(ctx context.Context, client *github.Client, resource *parser.Resource, path string, downloader *assets.Downloader, opts *converter.Options) error {
+	doc, err := Fetch(ctx, client, resource)
+	if err != nil {
+		return err
+	}
+
+	if downloader != nil {
+		if doc, err = converter.Prepare(doc, opts); err != nil {
+			return fmt.Errorf("failed to convert %s: %w", resource.Type, err)
+		}
+		if err := localizeAssets(ctx, downloader, doc, resource.Host, filepath.Dir(path)); err != nil {
+			return err
+		}
+	}
+
+	output, err := convert(doc, resource, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(path, output, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// convert 按 opts.Format 转换 Document
+func convert(doc *converter.Document, resource *parser.Resource, opts *converter.Options) ([]byte, error) {
+	output, err := converter.Convert(doc, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", resource.Type, err)
+	}
+	return output, nil
+}
+
+// localizeAssets 下载主楼、评论（含回复和被采纳的答案）、Review 正文及其修订记录中引用的图片和附件到 dir，并改写链接
+func localizeAssets(ctx context.Context, downloader *assets.Downloader, doc *converter.Document, host, dir string) error {
+	localize := func(body *string) error {
+		localized, err := downloader.Localize(ctx, *body, host, dir)
+		if err != nil {
+			return err
+		}
+		*body = localized
+		return nil
+	}
+
+	bodies := []*string{&doc.Body}
+	bodies = appendEditBodies(bodies, doc.Edits)
+	addComment := func(comment *github.Comment) {
+		bodies = append(bodies, &comment.Body)
+		bodies = appendEditBodies(bodies, comment.Edits)
+	}
+	for i := range doc.Comments {
+		addComment(&doc.Comments[i])
+		for j := range doc.Comments[i].Replies {
+			addComment(&doc.Comments[i].Replies[j])
+		}
+	}
+	if doc.Answer != nil {
+		addComment(doc.Answer)
+	}
+	for i := range doc.Reviews {
+		bodies = append(bodies, &doc.Reviews[i].Body)
+	}
+	for i := range doc.ReviewThreads {
+		for j := range doc.ReviewThreads[i].Comments {
+			addComment(&doc.ReviewThreads[i].Comments[j])
+		}
+	}
+
+	for _, body := range bodies {
+		if err := localize(body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendEditBodies 将修订记录中每个版本的正文追加到 bodies
+func appendEditBodies(bodies []*string, edits []github.Edit) []*string {
+	for i := range edits {
+		bodies = append(bodies, &edits[i].Body)
+	}
+	return bodies
+}
+
+// 文件名模板
+const (
+	// DefaultFileNamePattern 导出单个仓库时的默认文件名模板
+	DefaultFileNamePattern = "{type}-{number}.{ext}"
+	// DefaultBatchFileNamePattern 批量导出多个仓库的 URL 时的默认文件名模板
+	DefaultBatchFileNamePattern = "{owner}-{repo}-{type}-{number}.{ext}"
+)
+
+// Result 单个资源的导出结果
+type Result struct {
+	URL  string // 资源 URL
+	Path string // 成功时的输出文件路径
+	Err  error  // 失败原因，成功时为 nil
+}
+
+// ClientFunc 返回访问指定主机的 GitHub 客户端
+// 并发导出时会被多个 goroutine 同时调用，实现需要保证并发安全
+type ClientFunc func(host string) *github.Client
+
+// DefaultConcurrency 并发导出时默认的 worker 数量
+const DefaultConcurrency = 4
+
+// ToDir 使用 concurrency 个 worker 并发导出资源到 dir，文件名由 pattern 生成
+// 单个资源失败不会中断其余资源，结果与 resources 顺序一致
+// ctx 取消后尚未开始的资源直接记为失败；downloader 不为 nil 时同时下载图片和附件
+func ToDir(ctx context.Context, clientFor ClientFunc, resources []*parser.Resource, dir, pattern string, concurrency int, downloader *assets.Downloader, opts *converter.Options) []Result {
+	results := make([]Result, len(resources))
+
+	forEach(len(resources), concurrency, func(i int) {
+		results[i] = toFile(ctx, clientFor, resources[i], dir, pattern, downloader, opts)
+	})
+
+	return results
+}
+
+// toFile 导出单个资源到 dir 下由 pattern 生成的文件
+func toFile(ctx context.Context, clientFor ClientFunc, resource *parser.Resource, dir, pattern string, downloader *assets.Downloader, opts *converter.Options) Result {
+	result := Result{URL: resource.Original}
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	// 文件名中的 {type} 需要先确定简写引用的类型
+	client := clientFor(resource.Host)
+	if err := Resolve(ctx, client, resource); err != nil {
+		result.Err = err
+		return result
+	}
+
+	path := filepath.Join(dir, FormatFileName(pattern, resource, opts.Format.Extension()))
+	if err := WriteFile(ctx, client, resource, path, downloader, opts); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Path = path
+	return result
+}
+
+// forEach 使用最多 concurrency 个 goroutine 对 [0, n) 的每个下标调用 fn，全部完成后返回
+func forEach(n, concurrency int, fn func(i int)) {
+	concurrency = max(min(concurrency, n), 1)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Go(func() {
+			for i := range jobs {
+				fn(i)
+			}
+		})
+	}
+
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// FileName 返回资源使用默认模板导出为 Markdown 时的文件名，如 issue-123.md、pull-42.md、discussion-7.md
+func FileName(resource *parser.Resource) string {
+	return FormatFileName(DefaultFileNamePattern, resource, converter.FormatMarkdown.Extension())
+}
+
+// FormatFileName 按模板生成文件名，支持的占位符:
+//   - {host}: 主机名
+//   - {owner}: 仓库所有者
+//   - {repo}: 仓库名
+//   - {type}: issue、pull 或 discussion
+//   - {number}: 编号
+//   - {ext}: 输出格式的扩展名 ext，如 md、html
+func FormatFileName(pattern string, resource *parser.Resource, ext string) string {
+	typ := string(resource.Type)
+	if resource.Type == parser.ResourceTypePullRequest {
+		typ = "pull"
+	}
+
+	return strings.NewReplacer(
+		"{host}", resource.Host,
+		"{owner}", resource.Owner,
+		"{repo}", resource.Repo,
+		"{type}", typ,
+		"{number}", strconv.Itoa(resource.Number),
+		"{ext}", ext,
+	).Replace(pattern)
+}
@@ -0,0 +1,372 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/assets"
+	"github.com/wangyulu/issue2md2/internal/converter"
+	"github.com/wangyulu/issue2md2/internal/github"
+	"github.com/wangyulu/issue2md2/internal/parser"
+)
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *parser.Resource
+		expected string
+	}{
+		{name: "Issue", resource: &parser.Resource{Type: parser.ResourceTypeIssue, Number: 123}, expected: "issue-123.md"},
+		{name: "Pull Request", resource: &parser.Resource{Type: parser.ResourceTypePullRequest, Number: 42}, expected: "pull-42.md"},
+		{name: "Discussion", resource: &parser.Resource{Type: parser.ResourceTypeDiscussion, Number: 7}, expected: "discussion-7.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := FileName(tt.resource); result != tt.expected {
+				t.Errorf("FileName() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatFileName(t *testing.T) {
+	resource := &parser.Resource{
+		Type:   parser.ResourceTypePullRequest,
+		Host:   "github.com",
+		Owner:  "owner",
+		Repo:   "repo",
+		Number: 42,
+	}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		ext      string
+		expected string
+	}{
+		{name: "默认模板", pattern: DefaultFileNamePattern, expected: "pull-42.md"},
+		{name: "批量模板", pattern: DefaultBatchFileNamePattern, expected: "owner-repo-pull-42.md"},
+		{name: "子目录", pattern: "{host}/{owner}/{repo}/{number}.md", expected: "github.com/owner/repo/42.md"},
+		{name: "HTML 扩展名", pattern: DefaultFileNamePattern, ext: "html", expected: "pull-42.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext := tt.ext
+			if ext == "" {
+				ext = "md"
+			}
+			if result := FormatFileName(tt.pattern, resource, ext); result != tt.expected {
+				t.Errorf("FormatFileName(%q) = %q, want %q", tt.pattern, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteFileDownloadAssets(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user-attachments/assets/screenshot" {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"repository":{"issue":{
+			"title":"Test Issue","body":"![screenshot](%s/user-attachments/assets/screenshot)","closed":false,"createdAt":"2024-01-01T12:00:00Z",
+			"url":"https://github.com/owner/repo/issues/1","author":{"login":"octocat","url":""},"reactionGroups":[],
+			"comments":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[]}}}}}`, server.URL)
+	}))
+	defer server.Close()
+
+	resource := &parser.Resource{Type: parser.ResourceTypeIssue, Host: strings.TrimPrefix(server.URL, "http://"), Owner: "owner", Repo: "repo", Number: 1}
+	client := github.NewClient(github.WithEndpoint(server.URL + "/graphql"))
+	path := filepath.Join(t.TempDir(), "notes", "issue-1.md")
+
+	if err := WriteFile(context.Background(), client, resource, path, assets.NewDownloader(), converter.DefaultOptions()); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(path), assets.DirName))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("assets directory entries = %v, err = %v, want 1 file", entries, err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	want := "![screenshot](" + assets.DirName + "/" + entries[0].Name() + ")"
+	if !strings.Contains(string(content), want) {
+		t.Errorf("output file missing localized link %q, got:\n%s", want, content)
+	}
+}
+
+func TestWriteFileSkipsOmittedAssets(t *testing.T) {
+	var downloaded []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/user-attachments/") {
+			downloaded = append(downloaded, r.URL.Path)
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte(r.URL.Path))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"repository":{"issue":{
+			"title":"Test Issue","body":"![screenshot](%[1]s/user-attachments/assets/screenshot)","closed":false,"createdAt":"2024-01-01T12:00:00Z",
+			"url":"https://github.com/owner/repo/issues/1","author":{"login":"octocat","url":""},"reactionGroups":[],
+			"comments":{"totalCount":1,"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[
+				{"body":"![spam](%[1]s/user-attachments/assets/spam)","createdAt":"2024-01-02T10:00:00Z","author":{"login":"spammer","url":""},"isMinimized":true,"minimizedReason":"spam","reactionGroups":[]}
+			]}}}}}`, server.URL)
+	}))
+	defer server.Close()
+
+	resource := &parser.Resource{Type: parser.ResourceTypeIssue, Host: strings.TrimPrefix(server.URL, "http://"), Owner: "owner", Repo: "repo", Number: 1}
+	client := github.NewClient(github.WithEndpoint(server.URL + "/graphql"))
+	path := filepath.Join(t.TempDir(), "issue-1.md")
+	opts := converter.DefaultOptions()
+	opts.Minimized = converter.MinimizedOmit
+
+	if err := WriteFile(context.Background(), client, resource, path, assets.NewDownloader(), opts); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if len(downloaded) != 1 || downloaded[0] != "/user-attachments/assets/screenshot" {
+		t.Errorf("downloaded = %v, want only the body screenshot", downloaded)
+	}
+}
+
+func TestLocalizeAssetsEdits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	image := func(name string) string {
+		return "![" + name + "](" + server.URL + "/user-attachments/assets/" + name + ")"
+	}
+	edited := func(name string) github.Comment {
+		return github.Comment{Body: image(name), Edits: []github.Edit{{Body: image(name + "-old")}}}
+	}
+	reply := edited("reply")
+	answer := edited("answer")
+	doc := &converter.Document{
+		Thread: github.Thread{
+			Body:  image("body"),
+			Edits: []github.Edit{{Body: image("body-old")}},
+			Comments: []github.Comment{
+				edited("comment"),
+				{Replies: []github.Comment{reply}},
+			},
+		},
+		Answer:        &answer,
+		ReviewThreads: []github.ReviewThread{{Comments: []github.Comment{edited("thread")}}},
+	}
+
+	dir := t.TempDir()
+	if err := localizeAssets(context.Background(), assets.NewDownloader(), doc, strings.TrimPrefix(server.URL, "http://"), dir); err != nil {
+		t.Fatalf("localizeAssets() error = %v", err)
+	}
+
+	editBodies := map[string]string{
+		"body":    doc.Edits[0].Body,
+		"comment": doc.Comments[0].Edits[0].Body,
+		"reply":   doc.Comments[1].Replies[0].Edits[0].Body,
+		"answer":  doc.Answer.Edits[0].Body,
+		"thread":  doc.ReviewThreads[0].Comments[0].Edits[0].Body,
+	}
+	for name, body := range editBodies {
+		if strings.Contains(body, server.URL) || !strings.Contains(body, "]("+assets.DirName+"/") {
+			t.Errorf("%s edit body = %q, want localized link", name, body)
+		}
+	}
+	if entries, err := os.ReadDir(filepath.Join(dir, assets.DirName)); err != nil || len(entries) != 10 {
+		t.Errorf("assets directory entries = %d, err = %v, want 10 files", len(entries), err)
+	}
+}
+
+func TestToDir(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["number"] == float64(404) {
+			w.Write([]byte(`{"data":{"repository":{"issue":null}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"repository":{"issue":{
+			"title":"Test Issue","body":"Issue body","closed":false,"createdAt":"2024-01-01T12:00:00Z",
+			"url":"https://github.com/owner/repo/issues/1","author":{"login":"octocat","url":""},"reactionGroups":[],
+			"comments":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[]}}}}}`))
+	}))
+	defer server.Close()
+
+	client := github.NewClient(github.WithEndpoint(server.URL))
+	clientFor := func(host string) *github.Client { return client }
+
+	resources := []*parser.Resource{
+		{Type: parser.ResourceTypeIssue, Host: "github.com", Owner: "owner", Repo: "repo", Number: 1, Original: "https://github.com/owner/repo/issues/1"},
+		{Type: parser.ResourceTypeIssue, Host: "github.com", Owner: "owner", Repo: "repo", Number: 404, Original: "https://github.com/owner/repo/issues/404"},
+	}
+
+	dir := t.TempDir()
+	results := ToDir(context.Background(), clientFor, resources, dir, "{owner}/{type}-{number}.md", 2, nil, converter.DefaultOptions())
+
+	if len(results) != 2 {
+		t.Fatalf("len(ToDir()) = %d, want 2", len(results))
+	}
+
+	if results[0].Err != nil {
+		t.Fatalf("results[0].Err = %v, want nil", results[0].Err)
+	}
+	if results[0].Path != filepath.Join(dir, "owner", "issue-1.md") {
+		t.Errorf("results[0].Path = %q, want %q", results[0].Path, filepath.Join(dir, "owner", "issue-1.md"))
+	}
+	content, err := os.ReadFile(results[0].Path)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(content), "# Test Issue") {
+		t.Errorf("output file missing title, got:\n%s", content)
+	}
+
+	if results[1].Err == nil {
+		t.Error("results[1].Err = nil, want not found error")
+	}
+	if results[1].URL != "https://github.com/owner/repo/issues/404" {
+		t.Errorf("results[1].URL = %q, want the failed resource URL", results[1].URL)
+	}
+}
+
+func TestToDirResolvesReference(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(req.Query, "issueOrPullRequest") {
+			w.Write([]byte(`{"data":{"repository":{"issueOrPullRequest":{"__typename":"Issue"},"discussion":null}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"repository":{"issue":{
+			"title":"Referenced","body":"","closed":false,"createdAt":"2024-01-01T12:00:00Z",
+			"url":"https://github.com/owner/repo/issues/5","author":{"login":"octocat","url":""},"reactionGroups":[],
+			"comments":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[]}}}}}`))
+	}))
+	defer server.Close()
+
+	client := github.NewClient(github.WithEndpoint(server.URL))
+	clientFor := func(host string) *github.Client { return client }
+
+	resource, err := parser.ParseURL("owner/repo#5")
+	if err != nil {
+		t.Fatalf("ParseURL() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	results := ToDir(context.Background(), clientFor, []*parser.Resource{resource}, dir, DefaultFileNamePattern, 1, nil, converter.DefaultOptions())
+
+	if results[0].Err != nil {
+		t.Fatalf("results[0].Err = %v, want nil", results[0].Err)
+	}
+	if results[0].Path != filepath.Join(dir, "issue-5.md") {
+		t.Errorf("results[0].Path = %q, want %q", results[0].Path, filepath.Join(dir, "issue-5.md"))
+	}
+	if resource.Type != parser.ResourceTypeIssue {
+		t.Errorf("resource.Type = %q, want %q", resource.Type, parser.ResourceTypeIssue)
+	}
+}
+
+func TestToDirCanceled(t *testing.T) {
+	client := github.NewClient(github.WithEndpoint("http://127.0.0.1:0"))
+	clientFor := func(host string) *github.Client { return client }
+
+	var resources []*parser.Resource
+	for i := 1; i <= 5; i++ {
+		resources = append(resources, &parser.Resource{
+			Type:     parser.ResourceTypeIssue,
+			Owner:    "owner",
+			Repo:     "repo",
+			Number:   i,
+			Original: fmt.Sprintf("https://github.com/owner/repo/issues/%d", i),
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := ToDir(ctx, clientFor, resources, t.TempDir(), DefaultFileNamePattern, 3, nil, converter.DefaultOptions())
+
+	if len(results) != len(resources) {
+		t.Fatalf("len(ToDir()) = %d, want %d", len(results), len(resources))
+	}
+	for i, result := range results {
+		if result.URL != resources[i].Original {
+			t.Errorf("results[%d].URL = %q, want %q", i, result.URL, resources[i].Original)
+		}
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("results[%d].Err = %v, want context.Canceled", i, result.Err)
+		}
+	}
+}
+
+func TestForEach(t *testing.T) {
+	tests := []struct {
+		name        string
+		n           int
+		concurrency int
+	}{
+		{name: "单个 worker", n: 10, concurrency: 1},
+		{name: "多个 worker", n: 10, concurrency: 3},
+		{name: "worker 多于任务", n: 2, concurrency: 8},
+		{name: "并发数非法时按 1 处理", n: 3, concurrency: 0},
+		{name: "没有任务", n: 0, concurrency: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			running, maxRunning := 0, 0
+			visited := make([]int, tt.n)
+
+			forEach(tt.n, tt.concurrency, func(i int) {
+				mu.Lock()
+				running++
+				maxRunning = max(maxRunning, running)
+				visited[i]++
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+			})
+
+			for i, count := range visited {
+				if count != 1 {
+					t.Errorf("index %d visited %d times, want 1", i, count)
+				}
+			}
+			if limit := max(tt.concurrency, 1); maxRunning > limit {
+				t.Errorf("forEach() ran %d calls concurrently, want at most %d", maxRunning, limit)
+			}
+		})
+	}
+}
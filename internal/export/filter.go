@@ -0,0 +1,79 @@
+package export
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+// Filter 仓库导出的筛选条件，零值表示不筛选
+type Filter struct {
+	States        []string  // open, closed, merged；closed 同时匹配已合并的 Pull Request
+	Labels        []string  // 必须包含全部标签（不区分大小写）
+	Author        string    // 作者登录名（不区分大小写）
+	CreatedAfter  time.Time // 创建时间不早于该时间
+	CreatedBefore time.Time // 创建时间早于该时间
+	UpdatedAfter  time.Time // 更新时间不早于该时间
+	UpdatedBefore time.Time // 更新时间早于该时间
+}
+
+// Match 判断条目是否满足全部筛选条件
+func (f *Filter) Match(item github.ItemSummary) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.States) > 0 && !f.matchState(item.Status) {
+		return false
+	}
+
+	for _, label := range f.Labels {
+		if !slices.ContainsFunc(item.Labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+			return false
+		}
+	}
+
+	if f.Author != "" && !strings.EqualFold(f.Author, item.Author) {
+		return false
+	}
+
+	return inRange(item.CreatedAt, f.CreatedAfter, f.CreatedBefore) &&
+		inRange(item.UpdatedAt, f.UpdatedAfter, f.UpdatedBefore)
+}
+
+// ListOptions 返回交给 GitHub API 在服务端筛选的条件，nil 表示不筛选
+// 服务端筛选只是缩小范围，列出的条目仍需经过 Match 筛选（如标签需全部包含、Pull Request 的作者和时间范围）
+func (f *Filter) ListOptions() *github.ListOptions {
+	if f == nil {
+		return nil
+	}
+	return &github.ListOptions{
+		States: f.States,
+		Labels: f.Labels,
+		Author: f.Author,
+		Since:  f.UpdatedAfter,
+	}
+}
+
+// matchState 判断状态是否在筛选列表中
+func (f *Filter) matchState(status string) bool {
+	for _, state := range f.States {
+		if strings.EqualFold(state, status) || (strings.EqualFold(state, "closed") && status == "merged") {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange 判断 t 是否在 [after, before) 内，零值表示不限制
+func inRange(t, after, before time.Time) bool {
+	if !after.IsZero() && t.Before(after) {
+		return false
+	}
+	if !before.IsZero() && !t.Before(before) {
+		return false
+	}
+	return true
+}
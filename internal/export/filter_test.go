@@ -0,0 +1,73 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+)
+
+func TestFilterMatch(t *testing.T) {
+	item := github.ItemSummary{
+		Type:      "pull_request",
+		Number:    42,
+		Author:    "octocat",
+		Status:    "merged",
+		Labels:    []string{"bug", "Priority: High"},
+		CreatedAt: time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		filter   *Filter
+		expected bool
+	}{
+		{name: "nil 筛选条件", filter: nil, expected: true},
+		{name: "空筛选条件", filter: &Filter{}, expected: true},
+		{name: "状态匹配", filter: &Filter{States: []string{"merged"}}, expected: true},
+		{name: "closed 匹配已合并", filter: &Filter{States: []string{"closed"}}, expected: true},
+		{name: "状态不匹配", filter: &Filter{States: []string{"open"}}, expected: false},
+		{name: "标签不区分大小写", filter: &Filter{Labels: []string{"BUG", "priority: high"}}, expected: true},
+		{name: "缺少标签", filter: &Filter{Labels: []string{"bug", "docs"}}, expected: false},
+		{name: "作者匹配", filter: &Filter{Author: "OctoCat"}, expected: true},
+		{name: "作者不匹配", filter: &Filter{Author: "someone"}, expected: false},
+		{
+			name:     "创建时间在范围内",
+			filter:   &Filter{CreatedAfter: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), CreatedBefore: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+			expected: true,
+		},
+		{
+			name:     "创建时间早于下限",
+			filter:   &Filter{CreatedAfter: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+			expected: false,
+		},
+		{
+			name:     "更新时间上限不包含边界",
+			filter:   &Filter{UpdatedBefore: time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC)},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.filter.Match(item); result != tt.expected {
+				t.Errorf("Filter.Match() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterListOptions(t *testing.T) {
+	var nilFilter *Filter
+	if opts := nilFilter.ListOptions(); opts != nil {
+		t.Errorf("nil Filter.ListOptions() = %+v, want nil", opts)
+	}
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	filter := &Filter{States: []string{"open"}, Labels: []string{"bug"}, Author: "octocat", UpdatedAfter: since, CreatedAfter: time.Now()}
+	opts := filter.ListOptions()
+	if len(opts.States) != 1 || len(opts.Labels) != 1 || opts.Author != "octocat" || !opts.Since.Equal(since) {
+		t.Errorf("Filter.ListOptions() = %+v, want states, labels, author and updated-after", opts)
+	}
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wangyulu/issue2md2/internal/github"
+	"github.com/wangyulu/issue2md2/internal/parser"
+)
+
+// ListRepository 列出仓库中满足筛选条件的资源，按资源类型分组、组内按创建时间正序
+// API 支持的条件在服务端筛选以减少请求，其余条件在本地筛选
+func ListRepository(ctx context.Context, client *github.Client, repo *parser.Repository, filter *Filter) ([]*parser.Resource, error) {
+	var resources []*parser.Resource
+
+	for _, typ := range repo.Types {
+		var items []github.ItemSummary
+		var err error
+
+		switch typ {
+		case parser.ResourceTypeIssue:
+			items, err = client.ListIssues(ctx, repo.Owner, repo.Repo, filter.ListOptions())
+		case parser.ResourceTypePullRequest:
+			items, err = client.ListPullRequests(ctx, repo.Owner, repo.Repo, filter.ListOptions())
+		case parser.ResourceTypeDiscussion:
+			items, err = client.ListDiscussions(ctx, repo.Owner, repo.Repo, filter.ListOptions())
+		default:
+			return nil, fmt.Errorf("unsupported resource type: %s", typ)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if !filter.Match(item) {
+				continue
+			}
+			resources = append(resources, &parser.Resource{
+				Type:     typ,
+				Host:     repo.Host,
+				Owner:    repo.Owner,
+				Repo:     repo.Repo,
+				Number:   item.Number,
+				Original: item.URL,
+			})
+		}
+	}
+
+	return resources, nil
+}
@@ -2,90 +2,356 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/shurcooL/githubv4"
 )
 
+// DefaultPageSize 每次请求获取的评论数（GitHub GraphQL 允许的最大值）
+const DefaultPageSize = 100
+
+// DefaultEndpoint github.com 的 GraphQL API 地址
+const DefaultEndpoint = "https://api.github.com/graphql"
+
+// DefaultUserAgent 默认的 User-Agent
+const DefaultUserAgent = "issue2md"
+
 // Client GitHub API 客户端
 type Client struct {
-	ghClient *githubv4.Client
+	ghClient    *githubv4.Client
+	restClient  *http.Client  // 带认证和重试的 HTTP 客户端，用于获取 diff 等 REST API 请求
+	endpoint    string        // GraphQL API 地址
+	token       string        // Personal Access Token，为空时匿名访问
+	httpClient  *http.Client  // 底层 HTTP 客户端，为空时使用默认客户端
+	userAgent   string        // 请求的 User-Agent
+	timeout     time.Duration // 单次 API 调用超时（包含重试等待），0 表示不限制
+	pageSize    int           // 每页评论数，1-100
+	maxComments int           // 最多获取的评论数，0 表示不限制
+	timeline    bool          // 是否获取 Issue 和 Pull Request 的时间线事件
+	files       bool          // 是否获取 Pull Request 的变更文件列表
+	diff        bool          // 是否获取 Pull Request 的 unified diff（同时获取变更文件列表）
+	editHistory bool          // 是否获取正文和评论的修订记录
+
+	maxRetries   int           // 限流或临时错误时的最大重试次数，0 表示不重试
+	maxRetryWait time.Duration // 单次重试最长等待时间
+}
+
+// Option 客户端配置选项
+type Option func(*Client)
+
+// WithToken 设置用于认证的 Personal Access Token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithEndpoint 设置 GraphQL API 地址，用于 GitHub Enterprise Server 或本地测试服务
+func WithEndpoint(url string) Option {
+	return func(c *Client) {
+		c.endpoint = url
+	}
+}
+
+// WithHTTPClient 设置底层 HTTP 客户端，其 Transport 会被包装以添加认证信息
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent 设置请求的 User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout 设置单次 API 调用的超时时间（包含重试等待），0 表示不限制
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetry 设置限流或临时错误时的最大重试次数和单次最长等待时间
+// maxRetries 为 0 时不重试；需要等待的时间超过 maxWait 时直接返回 RateLimitError
+func WithRetry(maxRetries int, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.maxRetryWait = maxWait
+	}
+}
+
+// WithPageSize 设置分页获取评论时每页的数量（1-100）
+func WithPageSize(n int) Option {
+	return func(c *Client) {
+		c.pageSize = n
+	}
+}
+
+// WithMaxComments 设置最多获取的评论数，0 表示不限制
+func WithMaxComments(n int) Option {
+	return func(c *Client) {
+		c.maxComments = n
+	}
+}
+
+// WithTimeline 设置是否获取 Issue 和 Pull Request 的时间线事件（标签变更、关闭、引用等）
+func WithTimeline(enabled bool) Option {
+	return func(c *Client) {
+		c.timeline = enabled
+	}
+}
+
+// WithFiles 设置是否获取 Pull Request 的变更文件列表（路径、增删行数、变更类型）
+func WithFiles(enabled bool) Option {
+	return func(c *Client) {
+		c.files = enabled
+	}
+}
+
+// WithDiff 设置是否获取 Pull Request 的 unified diff 并按文件拆分到 ChangedFile.Patch
+// 启用后同时获取变更文件列表
+func WithDiff(enabled bool) Option {
+	return func(c *Client) {
+		c.diff = enabled
+	}
+}
+
+// WithEditHistory 设置是否获取正文和评论的修订记录（userContentEdits）
+func WithEditHistory(enabled bool) Option {
+	return func(c *Client) {
+		c.editHistory = enabled
+	}
 }
 
 // NewClient 创建 GitHub API 客户端
-func NewClient() *Client {
-	token := os.Getenv("GITHUB_TOKEN")
-	var httpClient *http.Client
-
-	if token != "" {
-		httpClient = &http.Client{
-			Transport: &authenticatedTransport{
-				token:     token,
-				transport: http.DefaultTransport,
-			},
-		}
+// 未指定选项时匿名访问 github.com
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		endpoint:     DefaultEndpoint,
+		userAgent:    DefaultUserAgent,
+		pageSize:     DefaultPageSize,
+		maxRetries:   DefaultMaxRetries,
+		maxRetryWait: DefaultMaxRetryWait,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.endpoint == "" {
+		c.endpoint = DefaultEndpoint
+	}
+	if c.pageSize <= 0 || c.pageSize > DefaultPageSize {
+		c.pageSize = DefaultPageSize
+	}
+	if c.maxComments < 0 {
+		c.maxComments = 0
+	}
+	if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.maxRetryWait < 0 {
+		c.maxRetryWait = 0
+	}
+
+	// 复制调用方的 HTTP 客户端，避免修改其 Transport
+	httpClient := &http.Client{}
+	if c.httpClient != nil {
+		*httpClient = *c.httpClient
+	}
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	httpClient.Transport = newRetryTransport(&authenticatedTransport{
+		token:     c.token,
+		userAgent: c.userAgent,
+		transport: transport,
+	}, c.maxRetries, c.maxRetryWait)
+	if c.timeout > 0 {
+		httpClient.Timeout = c.timeout
+	}
+
+	c.restClient = httpClient
+	c.ghClient = githubv4.NewEnterpriseClient(c.endpoint, httpClient)
+
+	return c
+}
 
-	return &Client{
-		ghClient: githubv4.NewClient(httpClient),
+// GraphQLEndpoint 返回指定主机对应的 GraphQL API 地址
+// github.com 使用 api.github.com，其他主机按 GitHub Enterprise Server 约定使用 /api/graphql
+func GraphQLEndpoint(host string) string {
+	host = strings.ToLower(host)
+	if host == "" || host == "github.com" {
+		return DefaultEndpoint
 	}
+	return "https://" + host + "/api/graphql"
 }
 
-// authenticatedTransport 添加 Authorization header
+// authenticatedTransport 添加 Authorization 和 User-Agent header
 type authenticatedTransport struct {
 	token     string
+	userAgent string
 	transport http.RoundTripper
 }
 
 func (t *authenticatedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Set("Authorization", "Bearer "+t.token)
+	// RoundTripper 不应修改原始请求
+	req = req.Clone(req.Context())
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
 	return t.transport.RoundTrip(req)
 }
 
-// FetchIssue 获取指定 Issue 的完整数据
-func (c *Client) FetchIssue(owner, repo string, number int) (*Issue, error) {
-	ctx := context.Background()
+// actor GraphQL 作者信息，Typename 为 User、Bot、Mannequin 或 Organization
+type actor struct {
+	Login    string
+	URL      string
+	Typename string `graphql:"__typename"`
+}
+
+// reactionGroup GraphQL 按表情分组的 Reactions 统计
+type reactionGroup struct {
+	Content  string
+	Reactors struct {
+		TotalCount int `graphql:"totalCount"`
+	}
+}
+
+// pageInfo GraphQL 分页信息
+type pageInfo struct {
+	HasNextPage bool
+	EndCursor   githubv4.String
+}
+
+// connection GraphQL 分页连接
+type connection[T any] struct {
+	TotalCount int `graphql:"totalCount"`
+	PageInfo   pageInfo
+	Nodes      []T `graphql:"nodes"`
+}
+
+// labelConnection 标签列表，只取前 100 个
+type labelConnection struct {
+	Nodes []struct {
+		Name string
+	} `graphql:"nodes"`
+}
+
+// assigneeConnection 指派人列表，只取前 100 个
+type assigneeConnection struct {
+	Nodes []struct {
+		Login string
+	} `graphql:"nodes"`
+}
+
+// reviewRequestConnection Pull Request 的 Review 请求
+type reviewRequestConnection struct {
+	Nodes []struct {
+		RequestedReviewer *struct {
+			Actor struct {
+				Login string
+			} `graphql:"... on Actor"`
+			Team struct {
+				CombinedSlug string
+			} `graphql:"... on Team"`
+		}
+	} `graphql:"nodes"`
+}
+
+// milestoneNode 里程碑
+type milestoneNode struct {
+	Title string
+	DueOn *string
+}
+
+// discussionCategoryNode Discussion 分类
+type discussionCategoryNode struct {
+	Name         string
+	Emoji        string
+	IsAnswerable bool
+}
+
+// pollNode Discussion 投票，选项只取前 100 个
+type pollNode struct {
+	Question       string
+	TotalVoteCount int
+	Options        struct {
+		Nodes []struct {
+			Option         string
+			TotalVoteCount int
+		} `graphql:"nodes"`
+	} `graphql:"options(first: 100)"`
+}
+
+// commentNode Issue / Pull Request 评论节点
+type commentNode struct {
+	ID                string
+	Body              string
+	CreatedAt         string
+	URL               string
+	Author            *actor
+	AuthorAssociation string
+	IsMinimized       bool
+	MinimizedReason   *string
+	ReactionGroups    []reactionGroup
+}
+
+// discussionReplyNode Discussion 评论的回复节点
+type discussionReplyNode struct {
+	commentNode
+	IsAnswer bool `graphql:"isAnswer"`
+}
+
+// discussionCommentNode Discussion 顶层评论节点，回复只取首页，其余由 fetchDiscussionReplies 获取
+type discussionCommentNode struct {
+	commentNode
+	IsAnswer bool                            `graphql:"isAnswer"`
+	Replies  connection[discussionReplyNode] `graphql:"replies(first: $repliesFirst)"`
+}
 
+// FetchIssue 获取指定 Issue 的完整数据
+func (c *Client) FetchIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
 	// GraphQL 查询
 	var q struct {
 		Repository struct {
 			Issue *struct {
-				Title      string
-				Body       *string
-				Closed     bool
-				CreatedAt  string
-				URL        string
-				Author     *struct {
-					Login     string
-					AvatarURL string
-				}
-				Reactions *struct {
-					TotalCount int `graphql:"totalCount"`
-				}
-				Comments struct {
-					Nodes []struct {
-						Body      string
-						CreatedAt string
-						Author    *struct {
-							Login     string
-							AvatarURL string
-						}
-						Reactions *struct {
-							TotalCount int `graphql:"totalCount"`
-						}
-					} `graphql:"nodes"`
-				} `graphql:"comments(first: 100)"`
+				ID                string
+				Title             string
+				Body              *string
+				Closed            bool
+				StateReason       *string
+				CreatedAt         string
+				ClosedAt          *string
+				UpdatedAt         string
+				Locked            bool
+				URL               string
+				Author            *actor
+				AuthorAssociation string
+				Labels            labelConnection    `graphql:"labels(first: 100)"`
+				Assignees         assigneeConnection `graphql:"assignees(first: 100)"`
+				Milestone         *milestoneNode
+				ReactionGroups    []reactionGroup
+				Comments          connection[commentNode] `graphql:"comments(first: $commentsFirst, after: $commentsCursor)"`
 			} `graphql:"issue(number: $number)"`
 		} `graphql:"repository(owner: $owner, name: $name)"`
 	}
 
 	variables := map[string]interface{}{
-		"owner":  githubv4.String(owner),
-		"name":   githubv4.String(repo),
-		"number": githubv4.Int(number),
+		"owner":          githubv4.String(owner),
+		"name":           githubv4.String(repo),
+		"number":         githubv4.Int(number),
+		"commentsFirst":  githubv4.Int(nextPageSize(c.pageSize, c.maxComments, 0)),
+		"commentsCursor": (*githubv4.String)(nil),
 	}
 
 	err := c.ghClient.Query(ctx, &q, variables)
@@ -99,74 +365,115 @@ func (c *Client) FetchIssue(owner, repo string, number int) (*Issue, error) {
 
 	issueData := q.Repository.Issue
 
+	// 翻页获取剩余评论
+	nodes, truncated, err := paginate(c.pageSize, c.maxComments, issueData.Comments, func(cursor githubv4.String, first int) (connection[commentNode], error) {
+		var pq struct {
+			Repository struct {
+				Issue *struct {
+					Comments connection[commentNode] `graphql:"comments(first: $commentsFirst, after: $commentsCursor)"`
+				} `graphql:"issue(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["commentsFirst"] = githubv4.Int(first)
+		variables["commentsCursor"] = githubv4.NewString(cursor)
+		if err := c.ghClient.Query(ctx, &pq, variables); err != nil {
+			return connection[commentNode]{}, err
+		}
+		if pq.Repository.Issue == nil {
+			return connection[commentNode]{}, fmt.Errorf("resource not found: %s/%s/issues/%d", owner, repo, number)
+		}
+		return pq.Repository.Issue.Comments, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue comments: %w", err)
+	}
+
 	// 构建 Issue 对象
 	issue := &Issue{
-		Title:     issueData.Title,
-		Body:      toString(issueData.Body),
-		Author:    toLogin(issueData.Author),
-		AuthorURL: toAvatarURL(issueData.Author),
-		CreatedAt: toTime(issueData.CreatedAt),
-		Status:    toStatus(issueData.Closed),
-		URL:       issueData.URL,
+		Thread: Thread{
+			ID:                issueData.ID,
+			Title:             issueData.Title,
+			Body:              toString(issueData.Body),
+			Author:            toLogin(issueData.Author),
+			AuthorURL:         toProfileURL(issueData.Author),
+			AuthorType:        toActorType(issueData.Author),
+			AuthorAssociation: issueData.AuthorAssociation,
+			CreatedAt:         toTime(issueData.CreatedAt),
+			ClosedAt:          toTime(toString(issueData.ClosedAt)),
+			UpdatedAt:         toTime(issueData.UpdatedAt),
+			Locked:            issueData.Locked,
+			Status:            toStatus(issueData.Closed),
+			URL:               issueData.URL,
+			Labels:            toLabels(issueData.Labels),
+			Reactions:         toReactions(issueData.ReactionGroups),
+			TotalComments:     issueData.Comments.TotalCount,
+			CommentsTruncated: truncated,
+		},
+		Assignees:   toAssignees(issueData.Assignees),
+		Milestone:   toMilestone(issueData.Milestone),
+		StateReason: toString(issueData.StateReason),
 	}
 
 	// Comments
-	for _, node := range issueData.Comments.Nodes {
-		comment := Comment{
-			Body:      node.Body,
-			CreatedAt: toTime(node.CreatedAt),
-			Author:    toLogin(node.Author),
-			AuthorURL: toAvatarURL(node.Author),
+	for _, node := range nodes {
+		issue.Comments = append(issue.Comments, toComment(node))
+	}
+
+	if c.timeline {
+		issue.Events, err = c.fetchIssueTimeline(ctx, owner, repo, number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch issue timeline: %w", err)
 		}
+	}
 
-		issue.Comments = append(issue.Comments, comment)
+	if c.editHistory {
+		if err := c.fetchThreadEdits(ctx, &issue.Thread, issue.Comments); err != nil {
+			return nil, fmt.Errorf("failed to fetch issue edit history: %w", err)
+		}
 	}
 
 	return issue, nil
 }
 
 // FetchPullRequest 获取指定 Pull Request 的完整数据
-func (c *Client) FetchPullRequest(owner, repo string, number int) (*PullRequest, error) {
-	ctx := context.Background()
-
+func (c *Client) FetchPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
 	// GraphQL 查询
 	var q struct {
 		Repository struct {
 			PullRequest *struct {
-				Title      string
-				Body       *string
-				State      string
-				Merged     bool
-				CreatedAt  string
-				URL        string
-				Author     *struct {
-					Login     string
-					AvatarURL string
-				}
-				Reactions *struct {
-					TotalCount int `graphql:"totalCount"`
-				}
-				Comments struct {
-					Nodes []struct {
-						Body      string
-						CreatedAt string
-						Author    *struct {
-							Login     string
-							AvatarURL string
-						}
-						Reactions *struct {
-							TotalCount int `graphql:"totalCount"`
-						}
-					} `graphql:"nodes"`
-				} `graphql:"comments(first: 100)"`
+				ID                string
+				Title             string
+				Body              *string
+				State             string
+				Merged            bool
+				IsDraft           bool
+				CreatedAt         string
+				ClosedAt          *string
+				MergedAt          *string
+				UpdatedAt         string
+				Locked            bool
+				URL               string
+				Author            *actor
+				AuthorAssociation string
+				MergedBy          *actor
+				BaseRefName       string
+				HeadRefName       string
+				Labels            labelConnection         `graphql:"labels(first: 100)"`
+				Assignees         assigneeConnection      `graphql:"assignees(first: 100)"`
+				ReviewRequests    reviewRequestConnection `graphql:"reviewRequests(first: 100)"`
+				Milestone         *milestoneNode
+				ReactionGroups    []reactionGroup
+				Comments          connection[commentNode] `graphql:"comments(first: $commentsFirst, after: $commentsCursor)"`
 			} `graphql:"pullRequest(number: $number)"`
 		} `graphql:"repository(owner: $owner, name: $name)"`
 	}
 
 	variables := map[string]interface{}{
-		"owner":  githubv4.String(owner),
-		"name":   githubv4.String(repo),
-		"number": githubv4.Int(number),
+		"owner":          githubv4.String(owner),
+		"name":           githubv4.String(repo),
+		"number":         githubv4.Int(number),
+		"commentsFirst":  githubv4.Int(nextPageSize(c.pageSize, c.maxComments, 0)),
+		"commentsCursor": (*githubv4.String)(nil),
 	}
 
 	err := c.ghClient.Query(ctx, &q, variables)
@@ -180,74 +487,151 @@ func (c *Client) FetchPullRequest(owner, repo string, number int) (*PullRequest,
 
 	prData := q.Repository.PullRequest
 
+	// 翻页获取剩余评论
+	nodes, truncated, err := paginate(c.pageSize, c.maxComments, prData.Comments, func(cursor githubv4.String, first int) (connection[commentNode], error) {
+		var pq struct {
+			Repository struct {
+				PullRequest *struct {
+					Comments connection[commentNode] `graphql:"comments(first: $commentsFirst, after: $commentsCursor)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["commentsFirst"] = githubv4.Int(first)
+		variables["commentsCursor"] = githubv4.NewString(cursor)
+		if err := c.ghClient.Query(ctx, &pq, variables); err != nil {
+			return connection[commentNode]{}, err
+		}
+		if pq.Repository.PullRequest == nil {
+			return connection[commentNode]{}, fmt.Errorf("resource not found: %s/%s/pull/%d", owner, repo, number)
+		}
+		return pq.Repository.PullRequest.Comments, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request comments: %w", err)
+	}
+
 	// 构建 PullRequest 对象
 	pr := &PullRequest{
-		Title:     prData.Title,
-		Body:      toString(prData.Body),
-		Author:    toLogin(prData.Author),
-		AuthorURL: toAvatarURL(prData.Author),
-		CreatedAt: toTime(prData.CreatedAt),
-		Status:    toPRStatus(prData.State, prData.Merged),
-		URL:       prData.URL,
+		Thread: Thread{
+			ID:                prData.ID,
+			Title:             prData.Title,
+			Body:              toString(prData.Body),
+			Author:            toLogin(prData.Author),
+			AuthorURL:         toProfileURL(prData.Author),
+			AuthorType:        toActorType(prData.Author),
+			AuthorAssociation: prData.AuthorAssociation,
+			CreatedAt:         toTime(prData.CreatedAt),
+			ClosedAt:          toTime(toString(prData.ClosedAt)),
+			UpdatedAt:         toTime(prData.UpdatedAt),
+			Locked:            prData.Locked,
+			Status:            toPRStatus(prData.State, prData.Merged),
+			URL:               prData.URL,
+			Labels:            toLabels(prData.Labels),
+			Reactions:         toReactions(prData.ReactionGroups),
+			TotalComments:     prData.Comments.TotalCount,
+			CommentsTruncated: truncated,
+		},
+		Assignees: toAssignees(prData.Assignees),
+		Milestone: toMilestone(prData.Milestone),
+
+		IsDraft:            prData.IsDraft,
+		MergedAt:           toTime(toString(prData.MergedAt)),
+		MergedBy:           toLogin(prData.MergedBy),
+		BaseRef:            prData.BaseRefName,
+		HeadRef:            prData.HeadRefName,
+		RequestedReviewers: toRequestedReviewers(prData.ReviewRequests),
 	}
 
 	// Comments
-	for _, node := range prData.Comments.Nodes {
-		comment := Comment{
-			Body:      node.Body,
-			CreatedAt: toTime(node.CreatedAt),
-			Author:    toLogin(node.Author),
-			AuthorURL: toAvatarURL(node.Author),
+	for _, node := range nodes {
+		pr.Comments = append(pr.Comments, toComment(node))
+	}
+
+	// Reviews 和行内代码评审
+	pr.Reviews, err = c.fetchReviews(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request reviews: %w", err)
+	}
+	pr.ReviewThreads, err = c.fetchReviewThreads(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request review threads: %w", err)
+	}
+
+	if c.timeline {
+		pr.Events, err = c.fetchPullRequestTimeline(ctx, owner, repo, number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pull request timeline: %w", err)
 		}
+	}
 
-		pr.Comments = append(pr.Comments, comment)
+	if c.files || c.diff {
+		pr.Files, err = c.fetchFiles(ctx, owner, repo, number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pull request files: %w", err)
+		}
+	}
+	if c.diff {
+		diff, err := c.fetchDiff(ctx, owner, repo, number)
+		switch {
+		case errors.Is(err, errDiffTooLarge):
+			pr.DiffTooLarge = true
+		case err != nil:
+			return nil, fmt.Errorf("failed to fetch pull request diff: %w", err)
+		default:
+			attachPatches(pr.Files, diff)
+		}
+	}
+
+	if c.editHistory {
+		comments := [][]Comment{pr.Comments}
+		for _, thread := range pr.ReviewThreads {
+			comments = append(comments, thread.Comments)
+		}
+		if err := c.fetchThreadEdits(ctx, &pr.Thread, comments...); err != nil {
+			return nil, fmt.Errorf("failed to fetch pull request edit history: %w", err)
+		}
 	}
 
 	return pr, nil
 }
 
 // FetchDiscussion 获取指定 Discussion 的完整数据
-func (c *Client) FetchDiscussion(owner, repo string, number int) (*Discussion, error) {
-	ctx := context.Background()
-
+func (c *Client) FetchDiscussion(ctx context.Context, owner, repo string, number int) (*Discussion, error) {
 	// GraphQL 查询
 	var q struct {
 		Repository struct {
 			Discussion *struct {
-				Title      string
-				Body       string
-				Closed     bool
-				CreatedAt  string
-				URL        string
-				Author     *struct {
-					Login     string
-					AvatarURL string
-				}
-				Reactions *struct {
-					TotalCount int `graphql:"totalCount"`
-				}
-				Comments struct {
-					Nodes []struct {
-						Body      string
-						CreatedAt string
-						Author    *struct {
-							Login     string
-							AvatarURL string
-						}
-						IsAnswer bool `graphql:"isAnswer"`
-						Reactions *struct {
-							TotalCount int `graphql:"totalCount"`
-						}
-					} `graphql:"nodes"`
-				} `graphql:"comments(first: 100)"`
+				ID                string
+				Title             string
+				Body              string
+				Closed            bool
+				CreatedAt         string
+				ClosedAt          *string
+				UpdatedAt         string
+				Locked            bool
+				URL               string
+				Author            *actor
+				AuthorAssociation string
+				Labels            labelConnection `graphql:"labels(first: 100)"`
+				ReactionGroups    []reactionGroup
+				Category          *discussionCategoryNode
+				UpvoteCount       int
+				Answer            *commentNode
+				AnswerChosenAt    *string
+				AnswerChosenBy    *actor
+				Poll              *pollNode
+				Comments          connection[discussionCommentNode] `graphql:"comments(first: $commentsFirst, after: $commentsCursor)"`
 			} `graphql:"discussion(number: $number)"`
 		} `graphql:"repository(owner: $owner, name: $name)"`
 	}
 
 	variables := map[string]interface{}{
-		"owner":  githubv4.String(owner),
-		"name":   githubv4.String(repo),
-		"number": githubv4.Int(number),
+		"owner":          githubv4.String(owner),
+		"name":           githubv4.String(repo),
+		"number":         githubv4.Int(number),
+		"commentsFirst":  githubv4.Int(nextPageSize(c.pageSize, c.maxComments, 0)),
+		"commentsCursor": (*githubv4.String)(nil),
+		"repliesFirst":   githubv4.Int(c.pageSize),
 	}
 
 	err := c.ghClient.Query(ctx, &q, variables)
@@ -261,33 +645,217 @@ func (c *Client) FetchDiscussion(owner, repo string, number int) (*Discussion, e
 
 	discussionData := q.Repository.Discussion
 
+	// 翻页获取剩余评论
+	nodes, truncated, err := paginate(c.pageSize, c.maxComments, discussionData.Comments, func(cursor githubv4.String, first int) (connection[discussionCommentNode], error) {
+		var pq struct {
+			Repository struct {
+				Discussion *struct {
+					Comments connection[discussionCommentNode] `graphql:"comments(first: $commentsFirst, after: $commentsCursor)"`
+				} `graphql:"discussion(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["commentsFirst"] = githubv4.Int(first)
+		variables["commentsCursor"] = githubv4.NewString(cursor)
+		if err := c.ghClient.Query(ctx, &pq, variables); err != nil {
+			return connection[discussionCommentNode]{}, err
+		}
+		if pq.Repository.Discussion == nil {
+			return connection[discussionCommentNode]{}, fmt.Errorf("resource not found: %s/%s/discussions/%d", owner, repo, number)
+		}
+		return pq.Repository.Discussion.Comments, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch discussion comments: %w", err)
+	}
+
 	// 构建 Discussion 对象
 	discussion := &Discussion{
-		Title:     discussionData.Title,
-		Body:      discussionData.Body,
-		Author:    toLogin(discussionData.Author),
-		AuthorURL: toAvatarURL(discussionData.Author),
-		CreatedAt: toTime(discussionData.CreatedAt),
-		Status:    toStatus(discussionData.Closed),
-		URL:       discussionData.URL,
+		Thread: Thread{
+			ID:                discussionData.ID,
+			Title:             discussionData.Title,
+			Body:              discussionData.Body,
+			Author:            toLogin(discussionData.Author),
+			AuthorURL:         toProfileURL(discussionData.Author),
+			AuthorType:        toActorType(discussionData.Author),
+			AuthorAssociation: discussionData.AuthorAssociation,
+			CreatedAt:         toTime(discussionData.CreatedAt),
+			ClosedAt:          toTime(toString(discussionData.ClosedAt)),
+			UpdatedAt:         toTime(discussionData.UpdatedAt),
+			Locked:            discussionData.Locked,
+			Status:            toStatus(discussionData.Closed),
+			URL:               discussionData.URL,
+			Labels:            toLabels(discussionData.Labels),
+			Reactions:         toReactions(discussionData.ReactionGroups),
+			TotalComments:     discussionData.Comments.TotalCount,
+			CommentsTruncated: truncated,
+		},
+		Category:       toCategory(discussionData.Category),
+		UpvoteCount:    discussionData.UpvoteCount,
+		AnswerChosenAt: toTime(toString(discussionData.AnswerChosenAt)),
+		AnswerChosenBy: toLogin(discussionData.AnswerChosenBy),
+		Poll:           toPoll(discussionData.Poll),
 	}
 
-	// Comments
-	for _, node := range discussionData.Comments.Nodes {
-		comment := Comment{
-			Body:      node.Body,
-			CreatedAt: toTime(node.CreatedAt),
-			Author:    toLogin(node.Author),
-			AuthorURL: toAvatarURL(node.Author),
-			IsAnswer:  node.IsAnswer,
+	if discussionData.Answer != nil {
+		answer := toComment(*discussionData.Answer)
+		answer.IsAnswer = true
+		discussion.Answer = &answer
+	}
+
+	// Comments 及其回复
+	for _, node := range nodes {
+		comment := toComment(node.commentNode)
+		comment.IsAnswer = node.IsAnswer
+
+		replies, err := c.fetchDiscussionReplies(ctx, node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch discussion replies: %w", err)
+		}
+		for _, reply := range replies {
+			r := toComment(reply.commentNode)
+			r.IsAnswer = reply.IsAnswer
+			comment.Replies = append(comment.Replies, r)
 		}
 
 		discussion.Comments = append(discussion.Comments, comment)
 	}
 
+	if c.editHistory {
+		comments := [][]Comment{discussion.Comments}
+		if discussion.Answer != nil {
+			comments = append(comments, []Comment{*discussion.Answer})
+		}
+		if err := c.fetchThreadEdits(ctx, &discussion.Thread, comments...); err != nil {
+			return nil, fmt.Errorf("failed to fetch discussion edit history: %w", err)
+		}
+		if discussion.Answer != nil {
+			discussion.Answer = &comments[len(comments)-1][0]
+		}
+	}
+
 	return discussion, nil
 }
 
+// fetchDiscussionReplies 获取评论首页之后的剩余回复，与首页合并返回
+func (c *Client) fetchDiscussionReplies(ctx context.Context, comment discussionCommentNode) ([]discussionReplyNode, error) {
+	// ID 使变量在查询中声明为 ID! 类型（githubv4.ID 为 interface，无法确定类型名）
+	type ID string
+
+	nodes, _, err := paginate(c.pageSize, 0, comment.Replies, func(cursor githubv4.String, first int) (connection[discussionReplyNode], error) {
+		var q struct {
+			Node struct {
+				DiscussionComment struct {
+					Replies connection[discussionReplyNode] `graphql:"replies(first: $repliesFirst, after: $repliesCursor)"`
+				} `graphql:"... on DiscussionComment"`
+			} `graphql:"node(id: $id)"`
+		}
+		variables := map[string]interface{}{
+			"id":            ID(comment.ID),
+			"repliesFirst":  githubv4.Int(first),
+			"repliesCursor": githubv4.NewString(cursor),
+		}
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[discussionReplyNode]{}, err
+		}
+		return q.Node.DiscussionComment.Replies, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// nextPageSize 根据已获取数量计算下一页的数量，避免超出 limit（0 表示不限制）
+func nextPageSize(pageSize, limit, fetched int) int {
+	if limit > 0 && limit-fetched < pageSize {
+		return limit - fetched
+	}
+	return pageSize
+}
+
+// paginate 沿 pageInfo 游标翻页，直到取完所有节点或达到 limit（0 表示不限制）
+// fetchPage 根据游标和页大小获取下一页
+// 返回收集到的节点，以及是否因 limit 限制而截断
+func paginate[T any](pageSize, limit int, first connection[T], fetchPage func(cursor githubv4.String, first int) (connection[T], error)) ([]T, bool, error) {
+	nodes := first.Nodes
+	page := first
+
+	for page.PageInfo.HasNextPage && nextPageSize(pageSize, limit, len(nodes)) > 0 {
+		next, err := fetchPage(page.PageInfo.EndCursor, nextPageSize(pageSize, limit, len(nodes)))
+		if err != nil {
+			return nil, false, err
+		}
+		page = next
+		nodes = append(nodes, page.Nodes...)
+	}
+
+	if limit > 0 && len(nodes) > limit {
+		nodes = nodes[:limit]
+	}
+
+	truncated := limit > 0 && len(nodes) < first.TotalCount
+	return nodes, truncated, nil
+}
+
+// ResolveNumber 返回编号对应的资源类型：issue、pull_request 或 discussion
+// Issue、Pull Request 和 Discussion 共用编号，用于解析 owner/repo#N 这类不带类型的引用
+func (c *Client) ResolveNumber(ctx context.Context, owner, repo string, number int) (string, error) {
+	var q struct {
+		Repository *struct {
+			IssueOrPullRequest *struct {
+				Typename string `graphql:"__typename"`
+			} `graphql:"issueOrPullRequest(number: $number)"`
+			Discussion *struct {
+				Number int
+			} `graphql:"discussion(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(repo),
+		"number": githubv4.Int(number),
+	}
+
+	// 不存在的一方会带有 NOT_FOUND 错误，只要另一方有数据就不算失败
+	err := c.ghClient.Query(ctx, &q, variables)
+	if q.Repository != nil {
+		if node := q.Repository.IssueOrPullRequest; node != nil {
+			switch node.Typename {
+			case "Issue":
+				return "issue", nil
+			case "PullRequest":
+				return "pull_request", nil
+			}
+		}
+		if q.Repository.Discussion != nil {
+			return "discussion", nil
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s/%s#%d: %w", owner, repo, number, err)
+	}
+	return "", fmt.Errorf("resource not found: %s/%s#%d", owner, repo, number)
+}
+
+// toComment 将评论节点转换为 Comment
+func toComment(node commentNode) Comment {
+	return Comment{
+		ID:                node.ID,
+		Body:              node.Body,
+		CreatedAt:         toTime(node.CreatedAt),
+		URL:               node.URL,
+		Author:            toLogin(node.Author),
+		AuthorURL:         toProfileURL(node.Author),
+		AuthorType:        toActorType(node.Author),
+		AuthorAssociation: node.AuthorAssociation,
+		Reactions:         toReactions(node.ReactionGroups),
+		IsMinimized:       node.IsMinimized,
+		MinimizedReason:   toString(node.MinimizedReason),
+	}
+}
+
 // 辅助函数
 
 func toString(s *string) string {
@@ -308,24 +876,126 @@ func toTime(timeStr string) time.Time {
 	return t
 }
 
-func toLogin(author *struct {
-	Login     string
-	AvatarURL string
-}) string {
+func toLogin(author *actor) string {
 	if author == nil {
 		return ""
 	}
 	return author.Login
 }
 
-func toAvatarURL(author *struct {
-	Login     string
-	AvatarURL string
-}) string {
+// toProfileURL 返回用户主页链接
+func toProfileURL(author *actor) string {
+	if author == nil {
+		return ""
+	}
+	return author.URL
+}
+
+// toActorType 返回用户类型：User、Bot、Mannequin 或 Organization
+func toActorType(author *actor) string {
 	if author == nil {
 		return ""
 	}
-	return author.AvatarURL
+	return author.Typename
+}
+
+// toLabels 返回标签名称列表
+func toLabels(labels labelConnection) []string {
+	var names []string
+	for _, label := range labels.Nodes {
+		names = append(names, strings.TrimSpace(label.Name))
+	}
+	return names
+}
+
+// toAssignees 返回指派人登录名列表
+func toAssignees(assignees assigneeConnection) []string {
+	var logins []string
+	for _, assignee := range assignees.Nodes {
+		logins = append(logins, assignee.Login)
+	}
+	return logins
+}
+
+// toRequestedReviewers 返回被请求 Review 的用户登录名和团队 org/team
+func toRequestedReviewers(requests reviewRequestConnection) []string {
+	var reviewers []string
+	for _, node := range requests.Nodes {
+		reviewer := node.RequestedReviewer
+		if reviewer == nil {
+			continue
+		}
+		if reviewer.Actor.Login != "" {
+			reviewers = append(reviewers, reviewer.Actor.Login)
+		} else if reviewer.Team.CombinedSlug != "" {
+			reviewers = append(reviewers, reviewer.Team.CombinedSlug)
+		}
+	}
+	return reviewers
+}
+
+// toMilestone 转换里程碑，未设置时返回 nil
+func toMilestone(node *milestoneNode) *Milestone {
+	if node == nil {
+		return nil
+	}
+	return &Milestone{Title: node.Title, DueOn: toTime(toString(node.DueOn))}
+}
+
+// toCategory 转换 Discussion 分类，未返回时为 nil
+func toCategory(node *discussionCategoryNode) *DiscussionCategory {
+	if node == nil {
+		return nil
+	}
+	return &DiscussionCategory{Name: node.Name, Emoji: node.Emoji, IsAnswerable: node.IsAnswerable}
+}
+
+// toPoll 转换 Discussion 投票，不是投票时为 nil
+func toPoll(node *pollNode) *Poll {
+	if node == nil {
+		return nil
+	}
+	poll := &Poll{Question: node.Question, TotalVotes: node.TotalVoteCount}
+	for _, option := range node.Options.Nodes {
+		poll.Options = append(poll.Options, PollOption{Option: option.Option, Votes: option.TotalVoteCount})
+	}
+	return poll
+}
+
+// toReactions 将 reactionGroups 映射为 Reactions，没有任何反应时返回 nil
+func toReactions(groups []reactionGroup) *Reactions {
+	r := &Reactions{}
+	total := 0
+
+	for _, g := range groups {
+		count := g.Reactors.TotalCount
+		switch g.Content {
+		case "THUMBS_UP":
+			r.ThumbsUp = count
+		case "THUMBS_DOWN":
+			r.ThumbsDown = count
+		case "LAUGH":
+			r.Laugh = count
+		case "HOORAY":
+			r.Hooray = count
+		case "CONFUSED":
+			r.Confused = count
+		case "HEART":
+			r.Heart = count
+		case "ROCKET":
+			r.Rocket = count
+		case "EYES":
+			r.Eyes = count
+		default:
+			continue
+		}
+		total += count
+	}
+
+	if total == 0 {
+		return nil
+	}
+	return r
 }
 
 func toStatus(closed bool) string {
@@ -1,7 +1,17 @@
 package github
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
 )
 
 // TestFetchIssue 测试获取 Issue 数据
@@ -9,10 +19,10 @@ func TestFetchIssue(t *testing.T) {
 	// 这是一个集成测试，需要真实的 GitHub API 访问
 	// 如果没有 GITHUB_TOKEN，可能会遇到限流问题
 
-	client := NewClient()
+	client := NewClient(WithToken(os.Getenv("GITHUB_TOKEN")))
 
 	// 使用公开的 Issue 进行测试
-	issue, err := client.FetchIssue("octocat", "Hello-World", 348)
+	issue, err := client.FetchIssue(context.Background(), "octocat", "Hello-World", 348)
 
 	if err != nil {
 		t.Fatalf("FetchIssue() failed: %v", err)
@@ -44,10 +54,10 @@ func TestFetchIssue(t *testing.T) {
 func TestFetchPullRequest(t *testing.T) {
 	t.Skip("Skipping - requires a known PR ID")
 
-	client := NewClient()
+	client := NewClient(WithToken(os.Getenv("GITHUB_TOKEN")))
 
 	// 使用公开的 PR 进行测试（使用一个确实存在的 PR）
-	pr, err := client.FetchPullRequest("golang", "go", 62140)
+	pr, err := client.FetchPullRequest(context.Background(), "golang", "go", 62140)
 
 	if err != nil {
 		t.Fatalf("FetchPullRequest() failed: %v", err)
@@ -77,11 +87,11 @@ func TestFetchPullRequest(t *testing.T) {
 
 // TestFetchDiscussion 测试获取 Discussion 数据
 func TestFetchDiscussion(t *testing.T) {
-	client := NewClient()
+	client := NewClient(WithToken(os.Getenv("GITHUB_TOKEN")))
 
 	// 使用公开的 Discussion 进行测试
 	// 注意：需要真实的 Discussion ID
-	discussion, err := client.FetchDiscussion("community", "community", 12345)
+	discussion, err := client.FetchDiscussion(context.Background(), "community", "community", 12345)
 
 	if err != nil {
 		t.Fatalf("FetchDiscussion() failed: %v", err)
@@ -111,9 +121,9 @@ func TestFetchDiscussion(t *testing.T) {
 
 // TestFetchNonExistentIssue 测试获取不存在的 Issue
 func TestFetchNonExistentIssue(t *testing.T) {
-	client := NewClient()
+	client := NewClient(WithToken(os.Getenv("GITHUB_TOKEN")))
 
-	_, err := client.FetchIssue("octocat", "Hello-World", 999999)
+	_, err := client.FetchIssue(context.Background(), "octocat", "Hello-World", 999999)
 
 	if err == nil {
 		t.Error("Expected error for non-existent issue, got nil")
@@ -122,9 +132,9 @@ func TestFetchNonExistentIssue(t *testing.T) {
 
 // TestFetchNonExistentPR 测试获取不存在的 PR
 func TestFetchNonExistentPR(t *testing.T) {
-	client := NewClient()
+	client := NewClient(WithToken(os.Getenv("GITHUB_TOKEN")))
 
-	_, err := client.FetchPullRequest("octocat", "Hello-World", 999999)
+	_, err := client.FetchPullRequest(context.Background(), "octocat", "Hello-World", 999999)
 
 	if err == nil {
 		t.Error("Expected error for non-existent PR, got nil")
@@ -133,11 +143,547 @@ func TestFetchNonExistentPR(t *testing.T) {
 
 // TestFetchNonExistentDiscussion 测试获取不存在的 Discussion
 func TestFetchNonExistentDiscussion(t *testing.T) {
-	client := NewClient()
+	client := NewClient(WithToken(os.Getenv("GITHUB_TOKEN")))
 
-	_, err := client.FetchDiscussion("community", "community", 999999)
+	_, err := client.FetchDiscussion(context.Background(), "community", "community", 999999)
 
 	if err == nil {
 		t.Error("Expected error for non-existent discussion, got nil")
 	}
 }
+
+// TestPaginate 测试按游标翻页和 limit 截断
+func TestPaginate(t *testing.T) {
+	// pages 模拟 5 条评论，每页 2 条
+	pages := map[githubv4.String]connection[int]{
+		"":   {TotalCount: 5, PageInfo: pageInfo{HasNextPage: true, EndCursor: "c1"}, Nodes: []int{1, 2}},
+		"c1": {TotalCount: 5, PageInfo: pageInfo{HasNextPage: true, EndCursor: "c2"}, Nodes: []int{3, 4}},
+		"c2": {TotalCount: 5, PageInfo: pageInfo{HasNextPage: false}, Nodes: []int{5}},
+	}
+
+	tests := []struct {
+		name              string
+		limit             int
+		expectedNodes     int
+		expectedTruncated bool
+		expectedRequests  int
+	}{
+		{name: "不限制", limit: 0, expectedNodes: 5, expectedTruncated: false, expectedRequests: 2},
+		{name: "限制为 3", limit: 3, expectedNodes: 3, expectedTruncated: true, expectedRequests: 1},
+		{name: "限制为 2", limit: 2, expectedNodes: 2, expectedTruncated: true, expectedRequests: 0},
+		{name: "限制大于总数", limit: 10, expectedNodes: 5, expectedTruncated: false, expectedRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+
+			nodes, truncated, err := paginate(2, tt.limit, pages[""], func(cursor githubv4.String, first int) (connection[int], error) {
+				requests++
+				return pages[cursor], nil
+			})
+
+			if err != nil {
+				t.Fatalf("paginate() unexpected error: %v", err)
+			}
+			if len(nodes) != tt.expectedNodes {
+				t.Errorf("paginate() returned %d nodes, want %d", len(nodes), tt.expectedNodes)
+			}
+			if truncated != tt.expectedTruncated {
+				t.Errorf("paginate() truncated = %v, want %v", truncated, tt.expectedTruncated)
+			}
+			if requests != tt.expectedRequests {
+				t.Errorf("paginate() made %d requests, want %d", requests, tt.expectedRequests)
+			}
+		})
+	}
+}
+
+// TestToReactions 测试 reactionGroups 到 Reactions 的映射
+func TestToReactions(t *testing.T) {
+	group := func(content string, count int) reactionGroup {
+		g := reactionGroup{Content: content}
+		g.Reactors.TotalCount = count
+		return g
+	}
+
+	tests := []struct {
+		name     string
+		groups   []reactionGroup
+		expected *Reactions
+	}{
+		{
+			name:     "没有 reactionGroups",
+			groups:   nil,
+			expected: nil,
+		},
+		{
+			name:     "全部为 0",
+			groups:   []reactionGroup{group("THUMBS_UP", 0), group("HEART", 0)},
+			expected: nil,
+		},
+		{
+			name: "全部表情类型",
+			groups: []reactionGroup{
+				group("THUMBS_UP", 1), group("THUMBS_DOWN", 2), group("LAUGH", 3), group("HOORAY", 4),
+				group("CONFUSED", 5), group("HEART", 6), group("ROCKET", 7), group("EYES", 8),
+			},
+			expected: &Reactions{ThumbsUp: 1, ThumbsDown: 2, Laugh: 3, Hooray: 4, Confused: 5, Heart: 6, Rocket: 7, Eyes: 8},
+		},
+		{
+			name:     "忽略未知类型",
+			groups:   []reactionGroup{group("UNKNOWN", 9), group("ROCKET", 1)},
+			expected: &Reactions{Rocket: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := toReactions(tt.groups)
+
+			if tt.expected == nil {
+				if result != nil {
+					t.Errorf("toReactions() = %+v, want nil", *result)
+				}
+				return
+			}
+			if result == nil {
+				t.Fatalf("toReactions() = nil, want %+v", *tt.expected)
+			}
+			if *result != *tt.expected {
+				t.Errorf("toReactions() = %+v, want %+v", *result, *tt.expected)
+			}
+		})
+	}
+}
+
+// TestToLine 测试行号回退逻辑
+func TestToLine(t *testing.T) {
+	line, original := 12, 8
+
+	tests := []struct {
+		name         string
+		line         *int
+		originalLine *int
+		expected     int
+	}{
+		{name: "当前行号", line: &line, originalLine: &original, expected: 12},
+		{name: "过期线程回退到原始行号", line: nil, originalLine: &original, expected: 8},
+		{name: "均为空", line: nil, originalLine: nil, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := toLine(tt.line, tt.originalLine); result != tt.expected {
+				t.Errorf("toLine() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestGraphQLEndpoint 测试主机名到 GraphQL API 地址的映射
+func TestGraphQLEndpoint(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{host: "", expected: "https://api.github.com/graphql"},
+		{host: "github.com", expected: "https://api.github.com/graphql"},
+		{host: "GitHub.com", expected: "https://api.github.com/graphql"},
+		{host: "github.example.com", expected: "https://github.example.com/api/graphql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if result := GraphQLEndpoint(tt.host); result != tt.expected {
+				t.Errorf("GraphQLEndpoint(%q) = %q, want %q", tt.host, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFetchIssueWithEndpoint 使用本地 GraphQL 服务测试 Issue 获取与评论翻页
+func TestFetchIssueWithEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["commentsCursor"] == nil {
+			w.Write([]byte(`{"data":{"repository":{"issue":{
+				"title":"Test Issue","body":"Issue body","closed":true,"stateReason":"NOT_PLANNED","locked":true,
+				"createdAt":"2024-01-01T12:00:00Z","closedAt":"2024-01-05T12:00:00Z","updatedAt":"2024-01-06T12:00:00Z","url":"https://github.example.com/owner/repo/issues/1",
+				"assignees":{"nodes":[{"login":"octocat"}]},
+				"milestone":{"title":"v1.0","dueOn":null},
+				"author":{"login":"octocat","url":"https://github.example.com/octocat","__typename":"User"},"authorAssociation":"OWNER",
+				"reactionGroups":[{"content":"THUMBS_UP","reactors":{"totalCount":2}}],
+				"comments":{"totalCount":2,"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+					"nodes":[{"body":"First","createdAt":"2024-01-02T10:00:00Z","author":{"login":"user1","url":""},"isMinimized":true,"minimizedReason":"off-topic","reactionGroups":[]}]}
+			}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"repository":{"issue":{
+			"comments":{"totalCount":2,"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+				"nodes":[{"body":"Second","createdAt":"2024-01-03T10:00:00Z","url":"https://github.example.com/owner/repo/issues/1#issuecomment-2","author":{"login":"dependabot","url":"https://github.example.com/apps/dependabot","__typename":"Bot"},"authorAssociation":"NONE","reactionGroups":[]}]}
+		}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithEndpoint(server.URL), WithPageSize(1))
+
+	issue, err := client.FetchIssue(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue() failed: %v", err)
+	}
+
+	if issue.Title != "Test Issue" {
+		t.Errorf("Issue.Title = %q, want %q", issue.Title, "Test Issue")
+	}
+	if issue.AuthorURL != "https://github.example.com/octocat" || issue.AuthorType != "User" || issue.AuthorAssociation != "OWNER" {
+		t.Errorf("Issue author = %q, %q, %q, want profile URL, User and OWNER", issue.AuthorURL, issue.AuthorType, issue.AuthorAssociation)
+	}
+	if issue.Reactions == nil || issue.Reactions.ThumbsUp != 2 {
+		t.Errorf("Issue.Reactions = %+v, want ThumbsUp 2", issue.Reactions)
+	}
+	if !issue.ClosedAt.Equal(time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Issue.ClosedAt = %v, want 2024-01-05T12:00:00Z", issue.ClosedAt)
+	}
+	if !issue.UpdatedAt.Equal(time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Issue.UpdatedAt = %v, want 2024-01-06T12:00:00Z", issue.UpdatedAt)
+	}
+	if issue.StateReason != "NOT_PLANNED" || !issue.Locked {
+		t.Errorf("Issue.StateReason = %q, Locked = %v, want NOT_PLANNED and locked", issue.StateReason, issue.Locked)
+	}
+	if len(issue.Assignees) != 1 || issue.Assignees[0] != "octocat" {
+		t.Errorf("Issue.Assignees = %v, want [octocat]", issue.Assignees)
+	}
+	if issue.Milestone == nil || issue.Milestone.Title != "v1.0" || !issue.Milestone.DueOn.IsZero() {
+		t.Errorf("Issue.Milestone = %+v, want v1.0 without due date", issue.Milestone)
+	}
+	if len(issue.Comments) != 2 {
+		t.Fatalf("len(Issue.Comments) = %d, want 2", len(issue.Comments))
+	}
+	if !issue.Comments[0].IsMinimized || issue.Comments[0].MinimizedReason != "off-topic" {
+		t.Errorf("Issue.Comments[0] minimized = %v, %q, want off-topic", issue.Comments[0].IsMinimized, issue.Comments[0].MinimizedReason)
+	}
+	if issue.Comments[1].IsMinimized {
+		t.Error("Issue.Comments[1].IsMinimized = true, want false")
+	}
+	if issue.Comments[1].Body != "Second" {
+		t.Errorf("Issue.Comments[1].Body = %q, want %q", issue.Comments[1].Body, "Second")
+	}
+	if issue.Comments[1].URL != "https://github.example.com/owner/repo/issues/1#issuecomment-2" {
+		t.Errorf("Issue.Comments[1].URL = %q, want the comment permalink", issue.Comments[1].URL)
+	}
+	if issue.Comments[1].AuthorType != "Bot" || issue.Comments[1].AuthorURL != "https://github.example.com/apps/dependabot" {
+		t.Errorf("Issue.Comments[1] author = %q, %q, want Bot with its profile URL", issue.Comments[1].AuthorType, issue.Comments[1].AuthorURL)
+	}
+	if issue.CommentsTruncated {
+		t.Error("Issue.CommentsTruncated = true, want false")
+	}
+}
+
+// TestNewClientOptions 测试 Token、User-Agent 注入以及不修改调用方的 HTTP 客户端
+func TestNewClientOptions(t *testing.T) {
+	var gotAuth, gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotUserAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":{"issue":null}}}`))
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{}
+	client := NewClient(
+		WithEndpoint(server.URL),
+		WithToken("ghp_test"),
+		WithUserAgent("issue2md-test"),
+		WithHTTPClient(httpClient),
+		WithTimeout(5*time.Second),
+	)
+
+	_, err := client.FetchIssue(context.Background(), "owner", "repo", 1)
+	if err == nil {
+		t.Error("FetchIssue() expected not found error, got nil")
+	}
+
+	if gotAuth != "Bearer ghp_test" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer ghp_test")
+	}
+	if gotUserAgent != "issue2md-test" {
+		t.Errorf("User-Agent = %q, want %q", gotUserAgent, "issue2md-test")
+	}
+	if httpClient.Transport != nil || httpClient.Timeout != 0 {
+		t.Error("NewClient() modified the caller's http.Client")
+	}
+}
+
+// TestFetchIssueContextCanceled 测试取消 context 后请求立即失败
+func TestFetchIssueContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient(WithEndpoint(server.URL))
+	_, err := client.FetchIssue(ctx, "owner", "repo", 1)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchIssue() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestResolveNumber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Variables["number"] {
+		case float64(1):
+			w.Write([]byte(`{"data":{"repository":{"issueOrPullRequest":{"__typename":"Issue"},"discussion":null}},
+				"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Discussion with the number of 1."}]}`))
+		case float64(2):
+			w.Write([]byte(`{"data":{"repository":{"issueOrPullRequest":{"__typename":"PullRequest"},"discussion":null}},
+				"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Discussion with the number of 2."}]}`))
+		case float64(3):
+			w.Write([]byte(`{"data":{"repository":{"issueOrPullRequest":null,"discussion":{"number":3}}},
+				"errors":[{"type":"NOT_FOUND","message":"Could not resolve to an issue or pull request with the number of 3."}]}`))
+		default:
+			w.Write([]byte(`{"data":{"repository":{"issueOrPullRequest":null,"discussion":null}},
+				"errors":[{"type":"NOT_FOUND","message":"Could not resolve to an issue or pull request with the number of 4."}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithEndpoint(server.URL))
+
+	tests := []struct {
+		number      int
+		expected    string
+		expectedErr bool
+	}{
+		{number: 1, expected: "issue"},
+		{number: 2, expected: "pull_request"},
+		{number: 3, expected: "discussion"},
+		{number: 4, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		result, err := client.ResolveNumber(context.Background(), "owner", "repo", tt.number)
+		if tt.expectedErr {
+			if err == nil {
+				t.Errorf("ResolveNumber(%d) expected error, got %q", tt.number, result)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ResolveNumber(%d) unexpected error: %v", tt.number, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("ResolveNumber(%d) = %q, want %q", tt.number, result, tt.expected)
+		}
+	}
+}
+
+// TestFetchIssueTimeline 测试启用时间线后获取并转换各类事件
+func TestFetchIssueTimeline(t *testing.T) {
+	var timelineQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(req.Query, "timelineItems") {
+			w.Write([]byte(`{"data":{"repository":{"issue":{
+				"title":"Test Issue","body":"","closed":true,"createdAt":"2024-01-01T12:00:00Z","url":"https://github.com/owner/repo/issues/1",
+				"author":{"login":"octocat","url":""},"reactionGroups":[],
+				"comments":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}
+			}}}}`))
+			return
+		}
+		timelineQuery = req.Query
+		w.Write([]byte(`{"data":{"repository":{"issue":{"timelineItems":{"totalCount":5,"pageInfo":{"hasNextPage":false,"endCursor":"t5"},"nodes":[
+			{"__typename":"LabeledEvent","actor":{"login":"octocat","url":""},"createdAt":"2024-01-02T10:00:00Z","label":{"name":"bug"}},
+			{"__typename":"AssignedEvent","actor":{"login":"octocat","url":""},"createdAt":"2024-01-02T11:00:00Z","assignee":{"login":"hubot"}},
+			{"__typename":"CrossReferencedEvent","actor":{"login":"hubot","url":""},"createdAt":"2024-01-03T10:00:00Z","source":{"title":"Fix bug","url":"https://github.com/owner/repo/pull/2"}},
+			{"__typename":"ClosedEvent","actor":{"login":"hubot","url":""},"createdAt":"2024-01-04T10:00:00Z","stateReason":"COMPLETED","closer":{"title":"Fix bug","url":"https://github.com/owner/repo/pull/2"}},
+			{"__typename":"RenamedTitleEvent","actor":null,"createdAt":"2024-01-05T10:00:00Z","previousTitle":"Old","currentTitle":"Test Issue"}
+		]}}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithEndpoint(server.URL), WithTimeline(true))
+
+	issue, err := client.FetchIssue(context.Background(), "owner", "repo", 1)
+	if err != nil {
+		t.Fatalf("FetchIssue() failed: %v", err)
+	}
+
+	if strings.Contains(timelineQuery, "MergedEvent") {
+		t.Errorf("issue timeline query should not request MergedEvent: %s", timelineQuery)
+	}
+
+	expected := []Event{
+		{Type: EventLabeled, Actor: "octocat", CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), Label: "bug"},
+		{Type: EventAssigned, Actor: "octocat", CreatedAt: time.Date(2024, 1, 2, 11, 0, 0, 0, time.UTC), Assignee: "hubot"},
+		{Type: EventCrossReferenced, Actor: "hubot", CreatedAt: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+			Source: &EventSource{Title: "Fix bug", URL: "https://github.com/owner/repo/pull/2"}},
+		{Type: EventClosed, Actor: "hubot", CreatedAt: time.Date(2024, 1, 4, 10, 0, 0, 0, time.UTC), StateReason: "COMPLETED",
+			Source: &EventSource{Title: "Fix bug", URL: "https://github.com/owner/repo/pull/2"}},
+		{Type: EventRenamed, CreatedAt: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), PreviousTitle: "Old", CurrentTitle: "Test Issue"},
+	}
+	if len(issue.Events) != len(expected) {
+		t.Fatalf("len(Issue.Events) = %d, want %d: %+v", len(issue.Events), len(expected), issue.Events)
+	}
+	for i, want := range expected {
+		got := issue.Events[i]
+		if got.Type != want.Type || got.Actor != want.Actor || !got.CreatedAt.Equal(want.CreatedAt) ||
+			got.Label != want.Label || got.Assignee != want.Assignee || got.StateReason != want.StateReason ||
+			got.PreviousTitle != want.PreviousTitle || got.CurrentTitle != want.CurrentTitle {
+			t.Errorf("Issue.Events[%d] = %+v, want %+v", i, got, want)
+		}
+		if (got.Source == nil) != (want.Source == nil) || (got.Source != nil && *got.Source != *want.Source) {
+			t.Errorf("Issue.Events[%d].Source = %+v, want %+v", i, got.Source, want.Source)
+		}
+	}
+}
+
+// TestFetchPullRequestWithEndpoint 测试 Pull Request 的合并信息、分支和 Review 请求
+func TestFetchPullRequestWithEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(req.Query, "reviewThreads"):
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviewThreads":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}}}}}`))
+		case strings.Contains(req.Query, "reviews("):
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviews":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}}}}}`))
+		default:
+			w.Write([]byte(`{"data":{"repository":{"pullRequest":{
+				"title":"Add retry","body":"","state":"MERGED","merged":true,"isDraft":false,"locked":false,
+				"createdAt":"2024-02-01T12:00:00Z","closedAt":"2024-02-03T12:00:00Z","mergedAt":"2024-02-03T12:00:00Z","updatedAt":"2024-02-04T12:00:00Z",
+				"url":"https://github.com/owner/repo/pull/2","author":{"login":"octocat","url":""},"mergedBy":{"login":"maintainer","url":""},
+				"baseRefName":"main","headRefName":"feature/retry",
+				"reviewRequests":{"nodes":[{"requestedReviewer":{"login":"reviewer"}},{"requestedReviewer":{"combinedSlug":"owner/core"}},{"requestedReviewer":null}]},
+				"reactionGroups":[],
+				"comments":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}
+			}}}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithEndpoint(server.URL))
+
+	pr, err := client.FetchPullRequest(context.Background(), "owner", "repo", 2)
+	if err != nil {
+		t.Fatalf("FetchPullRequest() failed: %v", err)
+	}
+
+	if pr.Status != "merged" || pr.MergedBy != "maintainer" || !pr.MergedAt.Equal(time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("PullRequest status = %q, merged by %q at %v, want merged by maintainer at 2024-02-03T12:00:00Z", pr.Status, pr.MergedBy, pr.MergedAt)
+	}
+	if pr.BaseRef != "main" || pr.HeadRef != "feature/retry" {
+		t.Errorf("PullRequest refs = %q <- %q, want main <- feature/retry", pr.BaseRef, pr.HeadRef)
+	}
+	if strings.Join(pr.RequestedReviewers, ",") != "reviewer,owner/core" {
+		t.Errorf("PullRequest.RequestedReviewers = %v, want [reviewer owner/core]", pr.RequestedReviewers)
+	}
+	if !pr.UpdatedAt.Equal(time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("PullRequest.UpdatedAt = %v, want 2024-02-04T12:00:00Z", pr.UpdatedAt)
+	}
+}
+
+// TestFetchDiscussionReplies 测试 Discussion 回复的翻页、被采纳的答案、分类和投票
+func TestFetchDiscussionReplies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(req.Query, "node(id: $id)") {
+			if req.Variables["id"] != "DC_1" || req.Variables["repliesCursor"] != "R1" {
+				t.Errorf("unexpected replies variables: %v", req.Variables)
+			}
+			w.Write([]byte(`{"data":{"node":{"replies":{"totalCount":2,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[
+				{"body":"Second reply","createdAt":"2024-04-02T11:00:00Z","url":"https://github.com/owner/repo/discussions/4#discussioncomment-3","author":{"login":"expert","url":""},"reactionGroups":[],"isAnswer":true}
+			]}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"repository":{"discussion":{
+			"title":"How to configure?","body":"","closed":false,"createdAt":"2024-04-01T12:00:00Z","updatedAt":"2024-04-02T11:00:00Z","locked":false,
+			"url":"https://github.com/owner/repo/discussions/4","author":{"login":"octocat","url":""},"labels":{"nodes":[]},"reactionGroups":[],
+			"category":{"name":"Q&A","emoji":":pray:","isAnswerable":true},"upvoteCount":7,
+			"answerChosenAt":"2024-04-03T08:00:00Z","answerChosenBy":{"login":"octocat","url":""},
+			"poll":{"question":"Which format?","totalVoteCount":5,"options":{"nodes":[{"option":"YAML","totalVoteCount":3},{"option":"TOML","totalVoteCount":2}]}},
+			"answer":{"body":"Second reply","createdAt":"2024-04-02T11:00:00Z","url":"https://github.com/owner/repo/discussions/4#discussioncomment-3","author":{"login":"expert","url":""},"reactionGroups":[]},
+			"comments":{"totalCount":1,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[
+				{"id":"DC_1","body":"Question detail","createdAt":"2024-04-02T09:00:00Z","url":"https://github.com/owner/repo/discussions/4#discussioncomment-1","author":{"login":"octocat","url":""},"reactionGroups":[],"isAnswer":false,
+				 "replies":{"totalCount":2,"pageInfo":{"hasNextPage":true,"endCursor":"R1"},"nodes":[
+					{"body":"First reply","createdAt":"2024-04-02T10:00:00Z","url":"https://github.com/owner/repo/discussions/4#discussioncomment-2","author":{"login":"helper","url":""},"reactionGroups":[],"isAnswer":false}
+				 ]}}
+			]}
+		}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithEndpoint(server.URL))
+
+	discussion, err := client.FetchDiscussion(context.Background(), "owner", "repo", 4)
+	if err != nil {
+		t.Fatalf("FetchDiscussion() failed: %v", err)
+	}
+
+	if len(discussion.Comments) != 1 {
+		t.Fatalf("len(Discussion.Comments) = %d, want 1", len(discussion.Comments))
+	}
+	replies := discussion.Comments[0].Replies
+	if len(replies) != 2 || replies[0].Body != "First reply" || replies[1].Body != "Second reply" {
+		t.Fatalf("Discussion.Comments[0].Replies = %+v, want First reply and Second reply", replies)
+	}
+	if replies[0].IsAnswer || !replies[1].IsAnswer {
+		t.Errorf("Replies IsAnswer = %v, %v, want false, true", replies[0].IsAnswer, replies[1].IsAnswer)
+	}
+	if discussion.Answer == nil || discussion.Answer.Author != "expert" || !discussion.Answer.IsAnswer {
+		t.Errorf("Discussion.Answer = %+v, want answer by expert", discussion.Answer)
+	}
+	if discussion.AnswerChosenBy != "octocat" || !discussion.AnswerChosenAt.Equal(time.Date(2024, 4, 3, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Discussion answer chosen by %q at %v, want octocat at 2024-04-03T08:00:00Z", discussion.AnswerChosenBy, discussion.AnswerChosenAt)
+	}
+	if discussion.Category == nil || *discussion.Category != (DiscussionCategory{Name: "Q&A", Emoji: ":pray:", IsAnswerable: true}) {
+		t.Errorf("Discussion.Category = %+v, want Q&A", discussion.Category)
+	}
+	if discussion.UpvoteCount != 7 {
+		t.Errorf("Discussion.UpvoteCount = %d, want 7", discussion.UpvoteCount)
+	}
+	if discussion.Poll == nil || discussion.Poll.Question != "Which format?" || discussion.Poll.TotalVotes != 5 ||
+		len(discussion.Poll.Options) != 2 || discussion.Poll.Options[0] != (PollOption{Option: "YAML", Votes: 3}) {
+		t.Errorf("Discussion.Poll = %+v, want Which format? with YAML 3 and TOML 2", discussion.Poll)
+	}
+}
@@ -0,0 +1,145 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// editsBatchSize 每次请求获取修订记录的节点数，nodes(ids:) 最多接受 100 个 ID
+const editsBatchSize = 100
+
+// editsPageSize 每页获取的修订记录数
+const editsPageSize = 100
+
+// editNode 正文修订记录节点，Diff 为该次修订后的完整正文
+type editNode struct {
+	EditedAt  string
+	Editor    *actor
+	Diff      *string
+	DeletedAt *string
+}
+
+// fetchThreadEdits 获取主题帖及 comments 中全部评论（含回复）的修订记录，填入各自的 Edits
+func (c *Client) fetchThreadEdits(ctx context.Context, thread *Thread, comments ...[]Comment) error {
+	each := func(fn func(*Comment)) {
+		for _, list := range comments {
+			for i := range list {
+				fn(&list[i])
+				for j := range list[i].Replies {
+					fn(&list[i].Replies[j])
+				}
+			}
+		}
+	}
+
+	ids := []string{thread.ID}
+	each(func(comment *Comment) {
+		ids = append(ids, comment.ID)
+	})
+
+	edits, err := c.fetchEdits(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	thread.Edits = edits[thread.ID]
+	each(func(comment *Comment) {
+		comment.Edits = edits[comment.ID]
+	})
+	return nil
+}
+
+// fetchEdits 按节点 ID 批量获取正文的修订记录，返回节点 ID 到修订记录的映射
+// 每批节点的第一页一起获取，超过一页的节点再单独翻页，保证包含最早的原始版本；
+// 没有修订的节点不在映射中，空 ID 被忽略
+func (c *Client) fetchEdits(ctx context.Context, ids []string) (map[string][]Edit, error) {
+	// ID 使变量在查询中声明为 [ID!]! 类型（githubv4.ID 为 interface，无法确定类型名）
+	type ID string
+
+	var pending []ID
+	for _, id := range ids {
+		if id != "" {
+			pending = append(pending, ID(id))
+		}
+	}
+
+	result := make(map[string][]Edit)
+	for len(pending) > 0 {
+		batch := pending[:min(editsBatchSize, len(pending))]
+		pending = pending[len(batch):]
+
+		var q struct {
+			Nodes []struct {
+				ID       string
+				Editable struct {
+					UserContentEdits *connection[editNode] `graphql:"userContentEdits(first: $editsFirst)"`
+				} `graphql:"... on UserContentEditable"`
+			} `graphql:"nodes(ids: $ids)"`
+		}
+		variables := map[string]interface{}{"ids": batch, "editsFirst": githubv4.Int(editsPageSize)}
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return nil, err
+		}
+
+		for _, node := range q.Nodes {
+			first := node.Editable.UserContentEdits
+			if first == nil || len(first.Nodes) == 0 {
+				continue
+			}
+			nodes, _, err := paginate(editsPageSize, 0, *first, func(cursor githubv4.String, n int) (connection[editNode], error) {
+				return c.fetchEditsPage(ctx, node.ID, cursor, n)
+			})
+			if err != nil {
+				return nil, err
+			}
+			result[node.ID] = toEdits(nodes)
+		}
+	}
+	return result, nil
+}
+
+// fetchEditsPage 获取单个节点在 cursor 之后的一页修订记录
+func (c *Client) fetchEditsPage(ctx context.Context, id string, cursor githubv4.String, first int) (connection[editNode], error) {
+	// ID 使变量在查询中声明为 ID! 类型
+	type ID string
+
+	var q struct {
+		Node *struct {
+			Editable struct {
+				UserContentEdits *connection[editNode] `graphql:"userContentEdits(first: $editsFirst, after: $editsCursor)"`
+			} `graphql:"... on UserContentEditable"`
+		} `graphql:"node(id: $id)"`
+	}
+	variables := map[string]interface{}{
+		"id":          ID(id),
+		"editsFirst":  githubv4.Int(first),
+		"editsCursor": cursor,
+	}
+	if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+		return connection[editNode]{}, err
+	}
+	if q.Node == nil || q.Node.Editable.UserContentEdits == nil {
+		return connection[editNode]{}, fmt.Errorf("resource not found: node %s", id)
+	}
+	return *q.Node.Editable.UserContentEdits, nil
+}
+
+// toEdits 转换修订记录并按修订时间正序排列（API 按时间倒序返回）
+func toEdits(nodes []editNode) []Edit {
+	var edits []Edit
+	for _, node := range nodes {
+		edits = append(edits, Edit{
+			Editor:   toLogin(node.Editor),
+			EditedAt: toTime(node.EditedAt),
+			Body:     toString(node.Diff),
+			Deleted:  node.DeletedAt != nil,
+		})
+	}
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].EditedAt.Before(edits[j].EditedAt)
+	})
+	return edits
+}
@@ -0,0 +1,157 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFetchIssueEditHistory 测试批量获取正文和评论的修订记录
+func TestFetchIssueEditHistory(t *testing.T) {
+	editsRequested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(req.Query, "nodes(ids: $ids)") {
+			editsRequested = true
+			if !strings.Contains(req.Query, "$ids:[ID!]!") {
+				t.Errorf("unexpected ids declaration in query: %s", req.Query)
+			}
+			ids, _ := req.Variables["ids"].([]interface{})
+			if len(ids) != 3 || ids[0] != "I_1" || ids[1] != "IC_1" || ids[2] != "IC_2" {
+				t.Errorf("unexpected ids: %v", req.Variables["ids"])
+			}
+			w.Write([]byte(`{"data":{"nodes":[
+				{"id":"I_1","userContentEdits":{"nodes":[
+					{"editedAt":"2024-01-02T10:00:00Z","editor":{"login":"hubot","url":""},"diff":"Edited body","deletedAt":null},
+					{"editedAt":"2024-01-01T12:00:00Z","editor":{"login":"octocat","url":""},"diff":"Original body","deletedAt":null}
+				]}},
+				{"id":"IC_1","userContentEdits":{"nodes":[]}},
+				{"id":"IC_2","userContentEdits":{"nodes":[
+					{"editedAt":"2024-01-03T10:00:00Z","editor":null,"diff":null,"deletedAt":"2024-01-04T10:00:00Z"}
+				]}}
+			]}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"repository":{"issue":{
+			"id":"I_1","title":"Test Issue","body":"Edited body","closed":false,"createdAt":"2024-01-01T12:00:00Z","updatedAt":"2024-01-02T10:00:00Z",
+			"url":"https://github.com/owner/repo/issues/1","author":{"login":"octocat","url":""},"reactionGroups":[],
+			"comments":{"totalCount":2,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[
+				{"id":"IC_1","body":"First","createdAt":"2024-01-02T10:00:00Z","author":{"login":"user1","url":""},"reactionGroups":[]},
+				{"id":"IC_2","body":"Second","createdAt":"2024-01-03T10:00:00Z","author":{"login":"user2","url":""},"reactionGroups":[]}
+			]}
+		}}}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		editHistory bool
+	}{
+		{name: "未启用", editHistory: false},
+		{name: "启用", editHistory: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editsRequested = false
+			client := NewClient(WithEndpoint(server.URL), WithEditHistory(tt.editHistory))
+
+			issue, err := client.FetchIssue(context.Background(), "owner", "repo", 1)
+			if err != nil {
+				t.Fatalf("FetchIssue() failed: %v", err)
+			}
+			if editsRequested != tt.editHistory {
+				t.Fatalf("edit history requested = %v, want %v", editsRequested, tt.editHistory)
+			}
+			if !tt.editHistory {
+				if issue.Edits != nil {
+					t.Errorf("Issue.Edits = %+v, want nil", issue.Edits)
+				}
+				return
+			}
+
+			expected := []Edit{
+				{Editor: "octocat", EditedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), Body: "Original body"},
+				{Editor: "hubot", EditedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), Body: "Edited body"},
+			}
+			if len(issue.Edits) != len(expected) {
+				t.Fatalf("Issue.Edits = %+v, want %+v", issue.Edits, expected)
+			}
+			for i := range expected {
+				if issue.Edits[i] != expected[i] {
+					t.Errorf("Issue.Edits[%d] = %+v, want %+v", i, issue.Edits[i], expected[i])
+				}
+			}
+			if issue.Comments[0].Edits != nil {
+				t.Errorf("Issue.Comments[0].Edits = %+v, want nil", issue.Comments[0].Edits)
+			}
+			if edits := issue.Comments[1].Edits; len(edits) != 1 || !edits[0].Deleted || edits[0].Body != "" {
+				t.Errorf("Issue.Comments[1].Edits = %+v, want one deleted revision", edits)
+			}
+		})
+	}
+}
+
+// TestFetchEditsPagination 测试修订记录超过一页时继续翻页，保留最早的原始版本
+func TestFetchEditsPagination(t *testing.T) {
+	var pageRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(req.Query, "node(id: $id)") {
+			pageRequests++
+			if req.Variables["id"] != "I_1" || req.Variables["editsCursor"] != "e2" || !strings.Contains(req.Query, "$id:ID!") {
+				t.Errorf("unexpected page request: %s %v", req.Query, req.Variables)
+			}
+			w.Write([]byte(`{"data":{"node":{"userContentEdits":{"totalCount":3,"pageInfo":{"hasNextPage":false,"endCursor":"e3"},"nodes":[
+				{"editedAt":"2024-01-01T12:00:00Z","editor":{"login":"octocat","url":""},"diff":"Original body","deletedAt":null}
+			]}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"nodes":[
+			{"id":"I_1","userContentEdits":{"totalCount":3,"pageInfo":{"hasNextPage":true,"endCursor":"e2"},"nodes":[
+				{"editedAt":"2024-01-03T10:00:00Z","editor":{"login":"hubot","url":""},"diff":"Latest body","deletedAt":null},
+				{"editedAt":"2024-01-02T10:00:00Z","editor":{"login":"hubot","url":""},"diff":"Edited body","deletedAt":null}
+			]}},
+			{"id":"IC_1","userContentEdits":{"totalCount":1,"pageInfo":{"hasNextPage":false,"endCursor":"x"},"nodes":[
+				{"editedAt":"2024-01-02T10:00:00Z","editor":{"login":"user1","url":""},"diff":"Comment","deletedAt":null}
+			]}}
+		]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithEndpoint(server.URL))
+	edits, err := client.fetchEdits(context.Background(), []string{"I_1", "IC_1"})
+	if err != nil {
+		t.Fatalf("fetchEdits() failed: %v", err)
+	}
+
+	if pageRequests != 1 {
+		t.Errorf("fetchEdits() made %d page requests, want 1", pageRequests)
+	}
+	if got := edits["I_1"]; len(got) != 3 || got[0].Body != "Original body" || got[2].Body != "Latest body" {
+		t.Errorf("edits[I_1] = %+v, want 3 revisions starting with the original body", got)
+	}
+	if got := edits["IC_1"]; len(got) != 1 {
+		t.Errorf("edits[IC_1] = %+v, want 1 revision", got)
+	}
+}
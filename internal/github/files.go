@@ -0,0 +1,174 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// errDiffTooLarge GitHub 拒绝生成过大的 diff（406 Not Acceptable）
+var errDiffTooLarge = errors.New("diff too large")
+
+// changedFileNode Pull Request 变更文件节点
+type changedFileNode struct {
+	Path       string
+	ChangeType string
+	Additions  int
+	Deletions  int
+}
+
+// fetchFiles 分页获取 Pull Request 的全部变更文件
+func (c *Client) fetchFiles(ctx context.Context, owner, repo string, number int) ([]ChangedFile, error) {
+	variables := map[string]interface{}{
+		"owner":       githubv4.String(owner),
+		"name":        githubv4.String(repo),
+		"number":      githubv4.Int(number),
+		"filesFirst":  githubv4.Int(c.pageSize),
+		"filesCursor": (*githubv4.String)(nil),
+	}
+
+	fetchPage := func(cursor *githubv4.String, first int) (connection[changedFileNode], error) {
+		var q struct {
+			Repository struct {
+				PullRequest *struct {
+					Files connection[changedFileNode] `graphql:"files(first: $filesFirst, after: $filesCursor)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["filesFirst"] = githubv4.Int(first)
+		variables["filesCursor"] = cursor
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[changedFileNode]{}, err
+		}
+		if q.Repository.PullRequest == nil {
+			return connection[changedFileNode]{}, fmt.Errorf("resource not found: %s/%s/pull/%d", owner, repo, number)
+		}
+		return q.Repository.PullRequest.Files, nil
+	}
+
+	first, err := fetchPage(nil, c.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	nodes, _, err := paginate(c.pageSize, 0, first, func(cursor githubv4.String, n int) (connection[changedFileNode], error) {
+		return fetchPage(&cursor, n)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []ChangedFile
+	for _, node := range nodes {
+		files = append(files, ChangedFile{
+			Path:       node.Path,
+			ChangeType: node.ChangeType,
+			Additions:  node.Additions,
+			Deletions:  node.Deletions,
+		})
+	}
+	return files, nil
+}
+
+// fetchDiff 通过 REST API 获取 Pull Request 的 unified diff
+// GraphQL API 不提供 diff 内容；diff 过大时 GitHub 返回 406，此时返回 errDiffTooLarge
+func (c *Client) fetchDiff(ctx context.Context, owner, repo string, number int) (string, error) {
+	u := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", restEndpoint(c.endpoint), url.PathEscape(owner), url.PathEscape(repo), number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github.diff")
+
+	resp, err := c.restClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotAcceptable:
+		return "", errDiffTooLarge
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("resource not found: %s/%s/pull/%d", owner, repo, number)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// restEndpoint 根据 GraphQL API 地址推导 REST API 地址
+// https://api.github.com/graphql → https://api.github.com，
+// GitHub Enterprise Server 的 https://host/api/graphql → https://host/api/v3
+func restEndpoint(graphqlEndpoint string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(graphqlEndpoint, "/"), "/graphql")
+	if strings.HasSuffix(base, "/api") {
+		return base + "/v3"
+	}
+	return base
+}
+
+// attachPatches 将 unified diff 按文件拆分后填入对应 ChangedFile 的 Patch
+func attachPatches(files []ChangedFile, diff string) {
+	patches := splitDiff(diff)
+	for i := range files {
+		files[i].Patch = patches[files[i].Path]
+	}
+}
+
+// splitDiff 将 unified diff 按文件拆分，返回路径到补丁内容的映射
+// 补丁从第一个 @@ 开始，不含 diff --git、index 等文件头；二进制文件和纯重命名没有补丁
+func splitDiff(diff string) map[string]string {
+	patches := make(map[string]string)
+
+	var path string
+	var hunks []string
+	flush := func() {
+		if path != "" && len(hunks) > 0 {
+			patches[path] = strings.Join(hunks, "\n")
+		}
+		path, hunks = "", nil
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			// 没有 +++ 和 rename to 行时（如二进制文件）使用 b/ 之后的路径
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				path = diffPath(line[i+1:])
+			}
+		case len(hunks) > 0 || strings.HasPrefix(line, "@@"):
+			hunks = append(hunks, line)
+		case strings.HasPrefix(line, "+++ ") && line != "+++ /dev/null":
+			path = diffPath(line[len("+++ "):])
+		case strings.HasPrefix(line, "rename to "):
+			path = diffPath("b/" + line[len("rename to "):])
+		}
+	}
+	flush()
+
+	return patches
+}
+
+// diffPath 去除 diff 文件头中路径的 a/、b/ 前缀，并还原带引号的路径
+func diffPath(s string) string {
+	s = strings.TrimRight(s, "\t")
+	if strings.HasPrefix(s, `"`) {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = unquoted
+		}
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(s, "b/"), "a/")
+}
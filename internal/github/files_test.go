@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testDiff = `diff --git a/client.go b/client.go
+index 1111111..2222222 100644
+--- a/client.go
++++ b/client.go
+@@ -1,3 +1,4 @@
+ package github
+--- removed line that looks like a header
++
++// retry
+diff --git a/old.go b/old.go
+deleted file mode 100644
+index 3333333..0000000
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..4444444
+Binary files /dev/null and b/logo.png differ
+diff --git "a/docs/read me.md" "b/docs/read me.md"
+new file mode 100644
+index 0000000..5555555
+--- /dev/null
++++ "b/docs/read me.md"
+@@ -0,0 +1 @@
++# Docs
+`
+
+func TestSplitDiff(t *testing.T) {
+	patches := splitDiff(testDiff)
+
+	expected := map[string]string{
+		"client.go":       "@@ -1,3 +1,4 @@\n package github\n--- removed line that looks like a header\n+\n+// retry",
+		"old.go":          "@@ -1 +0,0 @@\n-package old",
+		"docs/read me.md": "@@ -0,0 +1 @@\n+# Docs",
+	}
+	if len(patches) != len(expected) {
+		t.Errorf("splitDiff() returned %d patches, want %d: %v", len(patches), len(expected), patches)
+	}
+	for path, want := range expected {
+		if patches[path] != want {
+			t.Errorf("splitDiff()[%q] = %q, want %q", path, patches[path], want)
+		}
+	}
+}
+
+func TestRestEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		expected string
+	}{
+		{endpoint: DefaultEndpoint, expected: "https://api.github.com"},
+		{endpoint: GraphQLEndpoint("github.example.com"), expected: "https://github.example.com/api/v3"},
+		{endpoint: "http://127.0.0.1:8080", expected: "http://127.0.0.1:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			if result := restEndpoint(tt.endpoint); result != tt.expected {
+				t.Errorf("restEndpoint(%q) = %q, want %q", tt.endpoint, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFetchPullRequestFiles 测试获取变更文件列表和 diff
+func TestFetchPullRequestFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		opt          Option
+		diffStatus   int
+		expectPatch  bool
+		diffTooLarge bool
+	}{
+		{name: "仅文件列表", opt: WithFiles(true)},
+		{name: "包含 diff", opt: WithDiff(true), diffStatus: http.StatusOK, expectPatch: true},
+		{name: "diff 过大", opt: WithDiff(true), diffStatus: http.StatusNotAcceptable, diffTooLarge: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffRequested := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					diffRequested = true
+					if r.URL.Path != "/repos/owner/repo/pulls/2" || r.Header.Get("Accept") != "application/vnd.github.diff" {
+						t.Errorf("unexpected diff request: %s %s (Accept: %s)", r.Method, r.URL.Path, r.Header.Get("Accept"))
+					}
+					w.WriteHeader(tt.diffStatus)
+					if tt.diffStatus == http.StatusOK {
+						w.Write([]byte(testDiff))
+					}
+					return
+				}
+
+				var req struct {
+					Query string `json:"query"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("invalid request body: %v", err)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case strings.Contains(req.Query, "files("):
+					w.Write([]byte(`{"data":{"repository":{"pullRequest":{"files":{"totalCount":2,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[
+						{"path":"client.go","changeType":"MODIFIED","additions":2,"deletions":0},
+						{"path":"logo.png","changeType":"ADDED","additions":0,"deletions":0}
+					]}}}}}`))
+				case strings.Contains(req.Query, "reviewThreads"):
+					w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviewThreads":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}}}}}`))
+				case strings.Contains(req.Query, "reviews("):
+					w.Write([]byte(`{"data":{"repository":{"pullRequest":{"reviews":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}}}}}`))
+				default:
+					w.Write([]byte(`{"data":{"repository":{"pullRequest":{
+						"title":"Add retry","body":"","state":"OPEN","merged":false,"createdAt":"2024-02-01T12:00:00Z","updatedAt":"2024-02-01T12:00:00Z",
+						"url":"https://github.com/owner/repo/pull/2","author":{"login":"octocat","url":""},
+						"reviewRequests":{"nodes":[]},"reactionGroups":[],
+						"comments":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}
+					}}}}`))
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(WithEndpoint(server.URL), tt.opt)
+
+			pr, err := client.FetchPullRequest(context.Background(), "owner", "repo", 2)
+			if err != nil {
+				t.Fatalf("FetchPullRequest() failed: %v", err)
+			}
+
+			if len(pr.Files) != 2 || pr.Files[0].Path != "client.go" || pr.Files[0].ChangeType != "MODIFIED" || pr.Files[0].Additions != 2 {
+				t.Fatalf("PullRequest.Files = %+v, want client.go and logo.png", pr.Files)
+			}
+			if diffRequested != (tt.diffStatus != 0) {
+				t.Errorf("diff requested = %v, want %v", diffRequested, tt.diffStatus != 0)
+			}
+			if (pr.Files[0].Patch != "") != tt.expectPatch {
+				t.Errorf("PullRequest.Files[0].Patch = %q, want patch: %v", pr.Files[0].Patch, tt.expectPatch)
+			}
+			if pr.Files[1].Patch != "" {
+				t.Errorf("binary file patch = %q, want empty", pr.Files[1].Patch)
+			}
+			if pr.DiffTooLarge != tt.diffTooLarge {
+				t.Errorf("PullRequest.DiffTooLarge = %v, want %v", pr.DiffTooLarge, tt.diffTooLarge)
+			}
+		})
+	}
+}
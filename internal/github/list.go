@@ -0,0 +1,257 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// ListOptions 仓库列表的筛选条件，交给 GraphQL API 在服务端筛选，零值或 nil 表示不筛选
+// API 无法精确表达的条件（如 Pull Request 的作者和时间范围）不在这里处理，调用方需要在本地再次筛选
+type ListOptions struct {
+	States []string  // open, closed, merged；closed 同时匹配已合并的 Pull Request
+	Labels []string  // 标签名称
+	Author string    // 作者登录名，仅 Issue 支持
+	Since  time.Time // 更新时间不早于该时间，仅 Issue 支持
+}
+
+// itemNode 仓库列表中的条目节点
+type itemNode struct {
+	Number    int
+	Title     string
+	CreatedAt string
+	UpdatedAt string
+	URL       string
+	Author    *actor
+	Labels    labelConnection `graphql:"labels(first: 100)"`
+}
+
+// stateItemNode 带 state 字段的条目节点（Issue / Pull Request）
+type stateItemNode struct {
+	itemNode
+	State string
+}
+
+// discussionItemNode Discussion 条目节点
+type discussionItemNode struct {
+	itemNode
+	Closed bool
+}
+
+// ListIssues 列出仓库中满足 opts 的 Issue 摘要，按创建时间正序；状态、标签、作者和更新时间在服务端筛选
+func (c *Client) ListIssues(ctx context.Context, owner, repo string, opts *ListOptions) ([]ItemSummary, error) {
+	filters := map[string]interface{}{
+		"states":   issueStates(opts),
+		"filterBy": issueFilters(opts),
+	}
+	nodes, err := listItems(ctx, c, owner, repo, filters, func(variables map[string]interface{}) (connection[stateItemNode], error) {
+		var q struct {
+			Repository *struct {
+				Issues connection[stateItemNode] `graphql:"issues(first: $first, after: $cursor, orderBy: {field: CREATED_AT, direction: ASC}, states: $states, filterBy: $filterBy)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[stateItemNode]{}, err
+		}
+		if q.Repository == nil {
+			return connection[stateItemNode]{}, fmt.Errorf("resource not found: %s/%s", owner, repo)
+		}
+		return q.Repository.Issues, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	var items []ItemSummary
+	for _, node := range nodes {
+		items = append(items, toItemSummary(node.itemNode, "issue", toStatus(node.State == "CLOSED")))
+	}
+	return items, nil
+}
+
+// ListPullRequests 列出仓库中满足 opts 的 Pull Request 摘要，按创建时间正序；状态和标签在服务端筛选
+func (c *Client) ListPullRequests(ctx context.Context, owner, repo string, opts *ListOptions) ([]ItemSummary, error) {
+	filters := map[string]interface{}{
+		"states": pullRequestStates(opts),
+		"labels": listLabels(opts),
+	}
+	nodes, err := listItems(ctx, c, owner, repo, filters, func(variables map[string]interface{}) (connection[stateItemNode], error) {
+		var q struct {
+			Repository *struct {
+				PullRequests connection[stateItemNode] `graphql:"pullRequests(first: $first, after: $cursor, orderBy: {field: CREATED_AT, direction: ASC}, states: $states, labels: $labels)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[stateItemNode]{}, err
+		}
+		if q.Repository == nil {
+			return connection[stateItemNode]{}, fmt.Errorf("resource not found: %s/%s", owner, repo)
+		}
+		return q.Repository.PullRequests, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	var items []ItemSummary
+	for _, node := range nodes {
+		items = append(items, toItemSummary(node.itemNode, "pull_request", toPRStatus(node.State, node.State == "MERGED")))
+	}
+	return items, nil
+}
+
+// ListDiscussions 列出仓库中满足 opts 的 Discussion 摘要，按创建时间正序；状态在服务端筛选
+func (c *Client) ListDiscussions(ctx context.Context, owner, repo string, opts *ListOptions) ([]ItemSummary, error) {
+	filters := map[string]interface{}{
+		"states": discussionStates(opts),
+	}
+	nodes, err := listItems(ctx, c, owner, repo, filters, func(variables map[string]interface{}) (connection[discussionItemNode], error) {
+		var q struct {
+			Repository *struct {
+				Discussions connection[discussionItemNode] `graphql:"discussions(first: $first, after: $cursor, orderBy: {field: CREATED_AT, direction: ASC}, states: $states)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[discussionItemNode]{}, err
+		}
+		if q.Repository == nil {
+			return connection[discussionItemNode]{}, fmt.Errorf("resource not found: %s/%s", owner, repo)
+		}
+		return q.Repository.Discussions, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discussions: %w", err)
+	}
+
+	var items []ItemSummary
+	for _, node := range nodes {
+		items = append(items, toItemSummary(node.itemNode, "discussion", toStatus(node.Closed)))
+	}
+	return items, nil
+}
+
+// listItems 使用 fetchPage 分页获取仓库列表的全部节点
+// fetchPage 接收包含 owner、name、first、cursor 以及 filters 中筛选条件的查询变量
+func listItems[T any](ctx context.Context, c *Client, owner, repo string, filters map[string]interface{}, fetchPage func(variables map[string]interface{}) (connection[T], error)) ([]T, error) {
+	variables := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(repo),
+		"first":  githubv4.Int(c.pageSize),
+		"cursor": (*githubv4.String)(nil),
+	}
+	for name, value := range filters {
+		variables[name] = value
+	}
+
+	first, err := fetchPage(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, _, err := paginate(c.pageSize, 0, first, func(cursor githubv4.String, n int) (connection[T], error) {
+		variables["first"] = githubv4.Int(n)
+		variables["cursor"] = githubv4.NewString(cursor)
+		return fetchPage(variables)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// listStates 将 opts.States 按 mapping 转换为 GraphQL 枚举值并去重
+// 没有状态条件，或其中有无法表达的状态（如 Issue 的 merged）时返回 nil，不在服务端筛选
+func listStates[S ~string](opts *ListOptions, mapping map[string][]S) *[]S {
+	if opts == nil || len(opts.States) == 0 {
+		return nil
+	}
+	var states []S
+	seen := make(map[S]bool)
+	for _, state := range opts.States {
+		values, ok := mapping[strings.ToLower(state)]
+		if !ok {
+			return nil
+		}
+		for _, v := range values {
+			if !seen[v] {
+				seen[v] = true
+				states = append(states, v)
+			}
+		}
+	}
+	return &states
+}
+
+// issueStates 返回 Issue 的状态筛选条件
+func issueStates(opts *ListOptions) *[]githubv4.IssueState {
+	return listStates(opts, map[string][]githubv4.IssueState{
+		"open":   {githubv4.IssueStateOpen},
+		"closed": {githubv4.IssueStateClosed},
+	})
+}
+
+// pullRequestStates 返回 Pull Request 的状态筛选条件，closed 同时包括已合并
+func pullRequestStates(opts *ListOptions) *[]githubv4.PullRequestState {
+	return listStates(opts, map[string][]githubv4.PullRequestState{
+		"open":   {githubv4.PullRequestStateOpen},
+		"closed": {githubv4.PullRequestStateClosed, githubv4.PullRequestStateMerged},
+		"merged": {githubv4.PullRequestStateMerged},
+	})
+}
+
+// discussionStates 返回 Discussion 的状态筛选条件
+func discussionStates(opts *ListOptions) *[]githubv4.DiscussionState {
+	return listStates(opts, map[string][]githubv4.DiscussionState{
+		"open":   {githubv4.DiscussionStateOpen},
+		"closed": {githubv4.DiscussionStateClosed},
+	})
+}
+
+// listLabels 返回标签筛选条件，没有标签时返回 nil
+func listLabels(opts *ListOptions) *[]githubv4.String {
+	if opts == nil || len(opts.Labels) == 0 {
+		return nil
+	}
+	var labels []githubv4.String
+	for _, label := range opts.Labels {
+		labels = append(labels, githubv4.String(label))
+	}
+	return &labels
+}
+
+// issueFilters 返回 Issue 的标签、作者和更新时间筛选条件，都为空时返回 nil
+func issueFilters(opts *ListOptions) *githubv4.IssueFilters {
+	if opts == nil {
+		return nil
+	}
+	filters := &githubv4.IssueFilters{Labels: listLabels(opts)}
+	if opts.Author != "" {
+		filters.CreatedBy = githubv4.NewString(githubv4.String(opts.Author))
+	}
+	if !opts.Since.IsZero() {
+		filters.Since = &githubv4.DateTime{Time: opts.Since}
+	}
+	if filters.Labels == nil && filters.CreatedBy == nil && filters.Since == nil {
+		return nil
+	}
+	return filters
+}
+
+// toItemSummary 将条目节点转换为 ItemSummary
+func toItemSummary(node itemNode, typ, status string) ItemSummary {
+	return ItemSummary{
+		Type:      typ,
+		Number:    node.Number,
+		Title:     node.Title,
+		Author:    toLogin(node.Author),
+		Status:    status,
+		CreatedAt: toTime(node.CreatedAt),
+		UpdatedAt: toTime(node.UpdatedAt),
+		URL:       node.URL,
+		Labels:    toLabels(node.Labels),
+	}
+}
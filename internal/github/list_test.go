@@ -0,0 +1,174 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestListIssuesWithEndpoint 使用本地 GraphQL 服务测试 Issue 列表翻页
+func TestListIssuesWithEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["cursor"] == nil {
+			w.Write([]byte(`{"data":{"repository":{"issues":{"totalCount":2,
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+				"nodes":[{"number":1,"title":"First","state":"OPEN","createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-01-02T00:00:00Z",
+					"url":"https://github.com/owner/repo/issues/1","author":{"login":"octocat","url":""},
+					"labels":{"nodes":[{"name":"bug"}]}}]}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"repository":{"issues":{"totalCount":2,
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+			"nodes":[{"number":2,"title":"Second","state":"CLOSED","createdAt":"2024-02-01T00:00:00Z","updatedAt":"2024-02-02T00:00:00Z",
+				"url":"https://github.com/owner/repo/issues/2","author":null,"labels":{"nodes":[]}}]}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithEndpoint(server.URL), WithPageSize(1))
+
+	items, err := client.ListIssues(context.Background(), "owner", "repo", nil)
+	if err != nil {
+		t.Fatalf("ListIssues() failed: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("len(ListIssues()) = %d, want 2", len(items))
+	}
+	if items[0].Type != "issue" || items[0].Status != "open" || items[0].Author != "octocat" {
+		t.Errorf("items[0] = %+v, want open issue by octocat", items[0])
+	}
+	if len(items[0].Labels) != 1 || items[0].Labels[0] != "bug" {
+		t.Errorf("items[0].Labels = %v, want [bug]", items[0].Labels)
+	}
+	if items[1].Number != 2 || items[1].Status != "closed" {
+		t.Errorf("items[1] = %+v, want closed issue #2", items[1])
+	}
+}
+
+// TestListFilters 测试筛选条件作为查询参数发送给 GraphQL API
+func TestListFilters(t *testing.T) {
+	opts := &ListOptions{
+		States: []string{"closed"},
+		Labels: []string{"bug"},
+		Author: "octocat",
+		Since:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name      string
+		list      func(c *Client) ([]ItemSummary, error)
+		field     string
+		arguments string
+		variables map[string]string // 变量名 -> JSON
+	}{
+		{
+			name: "Issue",
+			list: func(c *Client) ([]ItemSummary, error) {
+				return c.ListIssues(context.Background(), "owner", "repo", opts)
+			},
+			field:     "issues",
+			arguments: "states: $states, filterBy: $filterBy",
+			variables: map[string]string{
+				"states":   `["CLOSED"]`,
+				"filterBy": `{"createdBy":"octocat","labels":["bug"],"since":"2024-03-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Pull Request",
+			list: func(c *Client) ([]ItemSummary, error) {
+				return c.ListPullRequests(context.Background(), "owner", "repo", opts)
+			},
+			field:     "pullRequests",
+			arguments: "states: $states, labels: $labels",
+			variables: map[string]string{"states": `["CLOSED","MERGED"]`, "labels": `["bug"]`},
+		},
+		{
+			name: "Discussion",
+			list: func(c *Client) ([]ItemSummary, error) {
+				return c.ListDiscussions(context.Background(), "owner", "repo", opts)
+			},
+			field:     "discussions",
+			arguments: "states: $states",
+			variables: map[string]string{"states": `["CLOSED"]`},
+		},
+		{
+			name: "不筛选",
+			list: func(c *Client) ([]ItemSummary, error) {
+				return c.ListIssues(context.Background(), "owner", "repo", nil)
+			},
+			field:     "issues",
+			arguments: "states: $states, filterBy: $filterBy",
+			variables: map[string]string{"states": "null", "filterBy": "null"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string                     `json:"query"`
+					Variables map[string]json.RawMessage `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("invalid request body: %v", err)
+				}
+				if !strings.Contains(req.Query, tt.arguments) {
+					t.Errorf("query = %s, want arguments %q", req.Query, tt.arguments)
+				}
+				for name, want := range tt.variables {
+					if got := string(req.Variables[name]); got != want {
+						t.Errorf("variable %s = %s, want %s", name, got, want)
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"data":{"repository":{"` + tt.field + `":{"totalCount":0,"pageInfo":{"hasNextPage":false,"endCursor":null},"nodes":[]}}}}`))
+			}))
+			defer server.Close()
+
+			if _, err := tt.list(NewClient(WithEndpoint(server.URL))); err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestListStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		states   []string
+		expected string
+	}{
+		{name: "不筛选", expected: "<nil>"},
+		{name: "open", states: []string{"OPEN"}, expected: "[OPEN]"},
+		{name: "merged 无法表达", states: []string{"open", "merged"}, expected: "<nil>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := "<nil>"
+			if states := issueStates(&ListOptions{States: tt.states}); states != nil {
+				var parts []string
+				for _, s := range *states {
+					parts = append(parts, string(s))
+				}
+				result = "[" + strings.Join(parts, ",") + "]"
+			}
+			if result != tt.expected {
+				t.Errorf("issueStates(%v) = %s, want %s", tt.states, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries 默认最大重试次数
+	DefaultMaxRetries = 3
+	// DefaultMaxRetryWait 默认单次重试最长等待时间，超过时直接返回限流错误
+	DefaultMaxRetryWait = time.Minute
+	// defaultRetryBaseDelay 指数退避的初始等待时间
+	defaultRetryBaseDelay = time.Second
+)
+
+// RateLimitError 重试后仍被限流（或需要等待的时间超过上限）时返回的错误
+type RateLimitError struct {
+	RetryAfter time.Duration // 建议等待的时间
+	ResetAt    time.Time     // 限流解除的时间
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded: wait %s (until %s) before retrying",
+		e.RetryAfter.Round(time.Second), e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// retryTransport 对限流和临时性服务端错误进行带抖动的指数退避重试
+//
+// 可重试的情况:
+//   - 429，或带 Retry-After / X-RateLimit-Remaining: 0 的 403（主要和次要限流）
+//   - 200 但 GraphQL errors 中包含 RATE_LIMITED
+//   - 502、503、504
+//
+// 同一个 Client 的所有请求共享限流状态：任一请求被限流后，
+// 并发的其他请求也会等到限流解除再发出，避免继续消耗配额
+type retryTransport struct {
+	transport  http.RoundTripper
+	maxRetries int
+	maxWait    time.Duration
+	baseDelay  time.Duration
+	now        func() time.Time
+	sleep      func(ctx context.Context, d time.Duration) error
+
+	mu          sync.Mutex
+	pausedUntil time.Time // 限流解除前不发出新请求
+}
+
+// newRetryTransport 创建重试 Transport
+func newRetryTransport(transport http.RoundTripper, maxRetries int, maxWait time.Duration) *retryTransport {
+	return &retryTransport{
+		transport:  transport,
+		maxRetries: maxRetries,
+		maxWait:    maxWait,
+		baseDelay:  defaultRetryBaseDelay,
+		now:        time.Now,
+		sleep:      sleepContext,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.waitPause(req.Context()); err != nil {
+			return nil, err
+		}
+
+		r, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.transport.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, retryable, rateLimited, err := t.classify(resp, attempt)
+		if err != nil {
+			return nil, err
+		}
+		if !retryable {
+			return resp, nil
+		}
+
+		if attempt >= t.maxRetries || wait > t.maxWait {
+			if rateLimited {
+				closeBody(resp)
+				return nil, &RateLimitError{RetryAfter: wait, ResetAt: t.now().Add(wait)}
+			}
+			return resp, nil
+		}
+
+		closeBody(resp)
+		if rateLimited {
+			// 限流等待由下一轮的 waitPause 完成，并发请求同样会等待
+			t.pause(wait)
+			continue
+		}
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// pause 在 wait 时间内暂停所有请求
+func (t *retryTransport) pause(wait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if until := t.now().Add(wait); until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// waitPause 等待到限流解除，未被限流时立即返回
+func (t *retryTransport) waitPause(ctx context.Context) error {
+	t.mu.Lock()
+	wait := t.pausedUntil.Sub(t.now())
+	t.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	return t.sleep(ctx, wait)
+}
+
+// classify 判断响应是否需要重试，并返回需要等待的时间以及是否为限流
+func (t *retryTransport) classify(resp *http.Response, attempt int) (time.Duration, bool, bool, error) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return t.rateLimitWait(resp, attempt), true, true, nil
+	case http.StatusForbidden:
+		if resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return t.rateLimitWait(resp, attempt), true, true, nil
+		}
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return t.backoff(attempt), true, false, nil
+	case http.StatusOK:
+		limited, err := isGraphQLRateLimited(resp)
+		if err != nil {
+			return 0, false, false, err
+		}
+		if limited {
+			return t.rateLimitWait(resp, attempt), true, true, nil
+		}
+	}
+
+	return 0, false, false, nil
+}
+
+// rateLimitWait 根据 Retry-After 或 X-RateLimit-Reset 计算等待时间，均缺失时使用指数退避
+func (t *retryTransport) rateLimitWait(resp *http.Response, attempt int) time.Duration {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return max(at.Sub(t.now()), 0)
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			// 多等待 1 秒，避免时钟误差导致刚好在重置前重试
+			return max(time.Unix(reset, 0).Sub(t.now())+time.Second, 0)
+		}
+	}
+
+	return t.backoff(attempt)
+}
+
+// backoff 返回第 attempt 次重试的指数退避时间，附加最多一半的随机抖动，且不超过 maxWait
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.baseDelay << attempt
+	if d <= 0 || d > t.maxWait {
+		d = t.maxWait
+	}
+	if half := int64(d / 2); half > 0 {
+		d += time.Duration(rand.Int64N(half))
+	}
+	return min(d, t.maxWait)
+}
+
+// isGraphQLRateLimited 检查 GraphQL 响应中是否包含 RATE_LIMITED 错误
+// 只读取 JSON 响应，读取后会恢复响应体，保证后续解析不受影响；
+// 其他响应（如 REST API 返回的 diff）原样返回，不读入内存
+func isGraphQLRateLimited(resp *http.Response) (bool, error) {
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "application/json" {
+		return false, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return false, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var out struct {
+		Errors []struct {
+			Type string `json:"type"`
+		} `json:"errors"`
+	}
+	// 非 JSON 响应交由 GraphQL 客户端处理
+	if err := json.Unmarshal(body, &out); err != nil {
+		return false, nil
+	}
+	for _, e := range out.Errors {
+		if strings.EqualFold(e.Type, "RATE_LIMITED") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rewindRequest 为第 attempt 次请求准备请求对象，重试时重新生成请求体
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("cannot retry request: body is not rewindable")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+// closeBody 丢弃并关闭响应体，以便复用连接
+func closeBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// sleepContext 等待 d，context 取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
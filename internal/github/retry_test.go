@@ -0,0 +1,245 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc 将函数适配为 http.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// fakeResponse 构造测试用响应，默认 Content-Type 为 GraphQL API 使用的 application/json
+func fakeResponse(status int, headers map[string]string, body string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestRetryTransport(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	okBody := `{"data":{"viewer":{"login":"octocat"}}}`
+
+	tests := []struct {
+		name              string
+		responses         []*http.Response
+		expectedCalls     int
+		expectedWaits     []time.Duration
+		expectedRateLimit bool
+		expectedStatus    int
+	}{
+		{
+			name:           "成功不重试",
+			responses:      []*http.Response{fakeResponse(200, nil, okBody)},
+			expectedCalls:  1,
+			expectedStatus: 200,
+		},
+		{
+			name: "502 后重试成功",
+			responses: []*http.Response{
+				fakeResponse(502, nil, "bad gateway"),
+				fakeResponse(200, nil, okBody),
+			},
+			expectedCalls:  2,
+			expectedStatus: 200,
+		},
+		{
+			name: "遵循 Retry-After",
+			responses: []*http.Response{
+				fakeResponse(429, map[string]string{"Retry-After": "2"}, ""),
+				fakeResponse(200, nil, okBody),
+			},
+			expectedCalls:  2,
+			expectedWaits:  []time.Duration{2 * time.Second},
+			expectedStatus: 200,
+		},
+		{
+			name: "遵循 X-RateLimit-Reset",
+			responses: []*http.Response{
+				fakeResponse(403, map[string]string{
+					"X-RateLimit-Remaining": "0",
+					"X-RateLimit-Reset":     strconv.FormatInt(now.Add(9*time.Second).Unix(), 10),
+				}, ""),
+				fakeResponse(200, nil, okBody),
+			},
+			expectedCalls:  2,
+			expectedWaits:  []time.Duration{10 * time.Second},
+			expectedStatus: 200,
+		},
+		{
+			name: "等待时间超过上限时立即失败",
+			responses: []*http.Response{
+				fakeResponse(403, map[string]string{
+					"X-RateLimit-Remaining": "0",
+					"X-RateLimit-Reset":     strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+				}, ""),
+			},
+			expectedCalls:     1,
+			expectedRateLimit: true,
+		},
+		{
+			name: "GraphQL RATE_LIMITED 重试耗尽",
+			responses: []*http.Response{
+				fakeResponse(200, map[string]string{"Retry-After": "1"}, `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`),
+				fakeResponse(200, map[string]string{"Retry-After": "1"}, `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`),
+				fakeResponse(200, map[string]string{"Retry-After": "1"}, `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`),
+			},
+			expectedCalls:     3,
+			expectedWaits:     []time.Duration{time.Second, time.Second},
+			expectedRateLimit: true,
+		},
+		{
+			name:           "非限流的 403 不重试",
+			responses:      []*http.Response{fakeResponse(403, nil, "forbidden")},
+			expectedCalls:  1,
+			expectedStatus: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var bodies []string
+			var waits []time.Duration
+
+			transport := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body, _ := io.ReadAll(req.Body)
+				bodies = append(bodies, string(body))
+				resp := tt.responses[calls]
+				calls++
+				return resp, nil
+			}), 2, time.Minute)
+			transport.baseDelay = time.Millisecond
+			transport.now = func() time.Time { return now }
+			transport.sleep = func(ctx context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query":"{viewer{login}}"}`))
+			resp, err := transport.RoundTrip(req)
+
+			if calls != tt.expectedCalls {
+				t.Errorf("RoundTrip() made %d calls, want %d", calls, tt.expectedCalls)
+			}
+			for i, body := range bodies {
+				if body != `{"query":"{viewer{login}}"}` {
+					t.Errorf("request %d body = %q, want original body", i, body)
+				}
+			}
+			if tt.expectedWaits != nil {
+				if len(waits) != len(tt.expectedWaits) {
+					t.Fatalf("RoundTrip() waited %v, want %v", waits, tt.expectedWaits)
+				}
+				for i := range waits {
+					if waits[i] != tt.expectedWaits[i] {
+						t.Errorf("wait %d = %v, want %v", i, waits[i], tt.expectedWaits[i])
+					}
+				}
+			}
+
+			if tt.expectedRateLimit {
+				var rateLimitErr *RateLimitError
+				if !errors.As(err, &rateLimitErr) {
+					t.Fatalf("RoundTrip() error = %v, want RateLimitError", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("RoundTrip() unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, tt.expectedStatus)
+			}
+			if resp.StatusCode == 200 {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != okBody {
+					t.Errorf("RoundTrip() body = %q, want %q", body, okBody)
+				}
+			}
+		})
+	}
+}
+
+// TestRetryTransportNonJSON 测试非 JSON 响应（如 REST API 返回的 diff）不被读入内存检查限流
+func TestRetryTransportNonJSON(t *testing.T) {
+	body := io.NopCloser(strings.NewReader(`{"errors":[{"type":"RATE_LIMITED"}]}`))
+	transport := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp := fakeResponse(200, map[string]string{"Content-Type": "application/vnd.github.diff; charset=utf-8"}, "")
+		resp.Body = body
+		return resp, nil
+	}), 2, time.Minute)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/owner/repo/pulls/1", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() unexpected error: %v", err)
+	}
+	if resp.Body != body {
+		t.Error("RoundTrip() replaced the body of a non-JSON response, want it passed through unread")
+	}
+}
+
+func TestRateLimitErrorMessage(t *testing.T) {
+	err := &RateLimitError{
+		RetryAfter: 12*time.Minute + 30*time.Second,
+		ResetAt:    time.Date(2024, 1, 1, 12, 12, 30, 0, time.UTC),
+	}
+
+	expected := "rate limit exceeded: wait 12m30s (until 2024-01-01T12:12:30Z) before retrying"
+	if err.Error() != expected {
+		t.Errorf("RateLimitError.Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestRetryTransportSharedPause(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var waits []time.Duration
+
+	transport := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return fakeResponse(200, nil, `{"data":{}}`), nil
+	}), 2, time.Minute)
+	transport.now = func() time.Time { return now }
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	// 其他 worker 的请求被限流 5 秒，2 秒后发出的请求需要再等待 3 秒
+	transport.pause(5 * time.Second)
+	now = now.Add(2 * time.Second)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/graphql", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() unexpected error: %v", err)
+	}
+	if len(waits) != 1 || waits[0] != 3*time.Second {
+		t.Errorf("RoundTrip() waited %v, want [3s]", waits)
+	}
+
+	// 限流解除后不再等待
+	waits = nil
+	now = now.Add(5 * time.Second)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() unexpected error: %v", err)
+	}
+	if len(waits) != 0 {
+		t.Errorf("RoundTrip() waited %v after pause expired, want none", waits)
+	}
+}
@@ -0,0 +1,201 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// reviewNode Pull Request Review 节点
+type reviewNode struct {
+	Author            *actor
+	AuthorAssociation string
+	Body              string
+	State             string
+	SubmittedAt       *string
+}
+
+// reviewCommentNode 行内代码评审评论节点
+type reviewCommentNode struct {
+	commentNode
+	DiffHunk string
+}
+
+// reviewThreadNode 行内代码评审讨论节点
+type reviewThreadNode struct {
+	ID                string
+	Path              string
+	Line              *int
+	StartLine         *int
+	OriginalLine      *int
+	OriginalStartLine *int
+	IsResolved        bool
+	IsOutdated        bool
+	Comments          connection[reviewCommentNode] `graphql:"comments(first: $repliesFirst)"`
+}
+
+// fetchReviews 分页获取 Pull Request 的全部 Review
+func (c *Client) fetchReviews(ctx context.Context, owner, repo string, number int) ([]Review, error) {
+	variables := map[string]interface{}{
+		"owner":         githubv4.String(owner),
+		"name":          githubv4.String(repo),
+		"number":        githubv4.Int(number),
+		"reviewsFirst":  githubv4.Int(c.pageSize),
+		"reviewsCursor": (*githubv4.String)(nil),
+	}
+
+	fetchPage := func(cursor *githubv4.String, first int) (connection[reviewNode], error) {
+		var q struct {
+			Repository struct {
+				PullRequest *struct {
+					Reviews connection[reviewNode] `graphql:"reviews(first: $reviewsFirst, after: $reviewsCursor)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["reviewsFirst"] = githubv4.Int(first)
+		variables["reviewsCursor"] = cursor
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[reviewNode]{}, err
+		}
+		if q.Repository.PullRequest == nil {
+			return connection[reviewNode]{}, fmt.Errorf("resource not found: %s/%s/pull/%d", owner, repo, number)
+		}
+		return q.Repository.PullRequest.Reviews, nil
+	}
+
+	first, err := fetchPage(nil, c.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	nodes, _, err := paginate(c.pageSize, 0, first, func(cursor githubv4.String, n int) (connection[reviewNode], error) {
+		return fetchPage(&cursor, n)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []Review
+	for _, node := range nodes {
+		reviews = append(reviews, Review{
+			Author:            toLogin(node.Author),
+			AuthorURL:         toProfileURL(node.Author),
+			AuthorType:        toActorType(node.Author),
+			AuthorAssociation: node.AuthorAssociation,
+			Body:              node.Body,
+			State:             node.State,
+			SubmittedAt:       toTime(toString(node.SubmittedAt)),
+		})
+	}
+
+	return reviews, nil
+}
+
+// fetchReviewThreads 分页获取 Pull Request 的全部行内代码评审讨论及其回复
+func (c *Client) fetchReviewThreads(ctx context.Context, owner, repo string, number int) ([]ReviewThread, error) {
+	variables := map[string]interface{}{
+		"owner":         githubv4.String(owner),
+		"name":          githubv4.String(repo),
+		"number":        githubv4.Int(number),
+		"threadsFirst":  githubv4.Int(c.pageSize),
+		"threadsCursor": (*githubv4.String)(nil),
+		"repliesFirst":  githubv4.Int(c.pageSize),
+	}
+
+	fetchPage := func(cursor *githubv4.String, first int) (connection[reviewThreadNode], error) {
+		var q struct {
+			Repository struct {
+				PullRequest *struct {
+					ReviewThreads connection[reviewThreadNode] `graphql:"reviewThreads(first: $threadsFirst, after: $threadsCursor)"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["threadsFirst"] = githubv4.Int(first)
+		variables["threadsCursor"] = cursor
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[reviewThreadNode]{}, err
+		}
+		if q.Repository.PullRequest == nil {
+			return connection[reviewThreadNode]{}, fmt.Errorf("resource not found: %s/%s/pull/%d", owner, repo, number)
+		}
+		return q.Repository.PullRequest.ReviewThreads, nil
+	}
+
+	first, err := fetchPage(nil, c.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	nodes, _, err := paginate(c.pageSize, 0, first, func(cursor githubv4.String, n int) (connection[reviewThreadNode], error) {
+		return fetchPage(&cursor, n)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var threads []ReviewThread
+	for _, node := range nodes {
+		replies, err := c.fetchThreadReplies(ctx, node)
+		if err != nil {
+			return nil, err
+		}
+
+		thread := ReviewThread{
+			Path:       node.Path,
+			Line:       toLine(node.Line, node.OriginalLine),
+			StartLine:  toLine(node.StartLine, node.OriginalStartLine),
+			IsResolved: node.IsResolved,
+			IsOutdated: node.IsOutdated,
+		}
+		for _, reply := range replies {
+			if thread.DiffHunk == "" {
+				thread.DiffHunk = reply.DiffHunk
+			}
+			thread.Comments = append(thread.Comments, toComment(reply.commentNode))
+		}
+
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
+
+// fetchThreadReplies 获取讨论中首页之后的剩余评论
+func (c *Client) fetchThreadReplies(ctx context.Context, thread reviewThreadNode) ([]reviewCommentNode, error) {
+	// ID 使变量在查询中声明为 ID! 类型（githubv4.ID 为 interface，无法确定类型名）
+	type ID string
+
+	nodes, _, err := paginate(c.pageSize, 0, thread.Comments, func(cursor githubv4.String, first int) (connection[reviewCommentNode], error) {
+		var q struct {
+			Node struct {
+				PullRequestReviewThread struct {
+					Comments connection[reviewCommentNode] `graphql:"comments(first: $repliesFirst, after: $repliesCursor)"`
+				} `graphql:"... on PullRequestReviewThread"`
+			} `graphql:"node(id: $id)"`
+		}
+		variables := map[string]interface{}{
+			"id":            ID(thread.ID),
+			"repliesFirst":  githubv4.Int(first),
+			"repliesCursor": githubv4.NewString(cursor),
+		}
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[reviewCommentNode]{}, err
+		}
+		return q.Node.PullRequestReviewThread.Comments, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// toLine 返回当前 diff 中的行号，线程过期时回退到原始行号，均为空时返回 0
+func toLine(line, originalLine *int) int {
+	if line != nil {
+		return *line
+	}
+	if originalLine != nil {
+		return *originalLine
+	}
+	return 0
+}
@@ -0,0 +1,301 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// eventNode 时间线事件的公共字段
+type eventNode struct {
+	Actor     *actor
+	CreatedAt string
+}
+
+// commitNode 事件关联的 commit
+type commitNode struct {
+	AbbreviatedOid string
+	URL            string
+}
+
+// subjectNode 事件关联的 Issue 或 Pull Request
+type subjectNode struct {
+	Issue struct {
+		Title string
+		URL   string
+	} `graphql:"... on Issue"`
+	PullRequest struct {
+		Title string
+		URL   string
+	} `graphql:"... on PullRequest"`
+}
+
+// assigneeNode 被指派的用户（User、Bot 等都实现了 Actor 接口）
+type assigneeNode struct {
+	Actor struct {
+		Login string
+	} `graphql:"... on Actor"`
+}
+
+// timelineItemNode Issue 时间线条目，按 Typename 取对应的字段
+type timelineItemNode struct {
+	Typename string `graphql:"__typename"`
+	Labeled  struct {
+		eventNode
+		Label struct{ Name string }
+	} `graphql:"... on LabeledEvent"`
+	Unlabeled struct {
+		eventNode
+		Label struct{ Name string }
+	} `graphql:"... on UnlabeledEvent"`
+	Assigned struct {
+		eventNode
+		Assignee *assigneeNode
+	} `graphql:"... on AssignedEvent"`
+	Unassigned struct {
+		eventNode
+		Assignee *assigneeNode
+	} `graphql:"... on UnassignedEvent"`
+	Closed struct {
+		eventNode
+		StateReason *string
+		Closer      *struct {
+			Commit      commitNode `graphql:"... on Commit"`
+			PullRequest struct {
+				Title string
+				URL   string
+			} `graphql:"... on PullRequest"`
+		}
+	} `graphql:"... on ClosedEvent"`
+	Reopened struct {
+		eventNode
+	} `graphql:"... on ReopenedEvent"`
+	Referenced struct {
+		eventNode
+		Commit *commitNode
+	} `graphql:"... on ReferencedEvent"`
+	CrossReferenced struct {
+		eventNode
+		Source subjectNode
+	} `graphql:"... on CrossReferencedEvent"`
+	Renamed struct {
+		eventNode
+		PreviousTitle string
+		CurrentTitle  string
+	} `graphql:"... on RenamedTitleEvent"`
+	Milestoned struct {
+		eventNode
+		MilestoneTitle string
+	} `graphql:"... on MilestonedEvent"`
+	Demilestoned struct {
+		eventNode
+		MilestoneTitle string
+	} `graphql:"... on DemilestonedEvent"`
+}
+
+// pullRequestTimelineItemNode Pull Request 时间线条目，比 Issue 多出合并事件
+type pullRequestTimelineItemNode struct {
+	timelineItemNode
+	Merged struct {
+		eventNode
+		Commit       *commitNode
+		MergeRefName string
+	} `graphql:"... on MergedEvent"`
+}
+
+// fetchIssueTimeline 分页获取 Issue 的时间线事件
+func (c *Client) fetchIssueTimeline(ctx context.Context, owner, repo string, number int) ([]Event, error) {
+	variables := timelineVariables(owner, repo, number, c.pageSize)
+
+	fetchPage := func(cursor *githubv4.String, first int) (connection[timelineItemNode], error) {
+		var q struct {
+			Repository struct {
+				Issue *struct {
+					TimelineItems connection[timelineItemNode] `graphql:"timelineItems(first: $timelineFirst, after: $timelineCursor, itemTypes: [LABELED_EVENT, UNLABELED_EVENT, ASSIGNED_EVENT, UNASSIGNED_EVENT, CLOSED_EVENT, REOPENED_EVENT, REFERENCED_EVENT, CROSS_REFERENCED_EVENT, RENAMED_TITLE_EVENT, MILESTONED_EVENT, DEMILESTONED_EVENT])"`
+				} `graphql:"issue(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["timelineFirst"] = githubv4.Int(first)
+		variables["timelineCursor"] = cursor
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[timelineItemNode]{}, err
+		}
+		if q.Repository.Issue == nil {
+			return connection[timelineItemNode]{}, fmt.Errorf("resource not found: %s/%s/issues/%d", owner, repo, number)
+		}
+		return q.Repository.Issue.TimelineItems, nil
+	}
+
+	first, err := fetchPage(nil, c.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	nodes, _, err := paginate(c.pageSize, 0, first, func(cursor githubv4.String, n int) (connection[timelineItemNode], error) {
+		return fetchPage(&cursor, n)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, node := range nodes {
+		if event, ok := toEvent(node); ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// fetchPullRequestTimeline 分页获取 Pull Request 的时间线事件
+func (c *Client) fetchPullRequestTimeline(ctx context.Context, owner, repo string, number int) ([]Event, error) {
+	variables := timelineVariables(owner, repo, number, c.pageSize)
+
+	fetchPage := func(cursor *githubv4.String, first int) (connection[pullRequestTimelineItemNode], error) {
+		var q struct {
+			Repository struct {
+				PullRequest *struct {
+					TimelineItems connection[pullRequestTimelineItemNode] `graphql:"timelineItems(first: $timelineFirst, after: $timelineCursor, itemTypes: [LABELED_EVENT, UNLABELED_EVENT, ASSIGNED_EVENT, UNASSIGNED_EVENT, CLOSED_EVENT, REOPENED_EVENT, REFERENCED_EVENT, CROSS_REFERENCED_EVENT, RENAMED_TITLE_EVENT, MILESTONED_EVENT, DEMILESTONED_EVENT, MERGED_EVENT])"`
+				} `graphql:"pullRequest(number: $number)"`
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		variables["timelineFirst"] = githubv4.Int(first)
+		variables["timelineCursor"] = cursor
+		if err := c.ghClient.Query(ctx, &q, variables); err != nil {
+			return connection[pullRequestTimelineItemNode]{}, err
+		}
+		if q.Repository.PullRequest == nil {
+			return connection[pullRequestTimelineItemNode]{}, fmt.Errorf("resource not found: %s/%s/pull/%d", owner, repo, number)
+		}
+		return q.Repository.PullRequest.TimelineItems, nil
+	}
+
+	first, err := fetchPage(nil, c.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	nodes, _, err := paginate(c.pageSize, 0, first, func(cursor githubv4.String, n int) (connection[pullRequestTimelineItemNode], error) {
+		return fetchPage(&cursor, n)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, node := range nodes {
+		if node.Typename == "MergedEvent" {
+			events = append(events, Event{
+				Type:      EventMerged,
+				Actor:     toLogin(node.Merged.Actor),
+				ActorURL:  toProfileURL(node.Merged.Actor),
+				CreatedAt: toTime(node.Merged.CreatedAt),
+				Ref:       node.Merged.MergeRefName,
+				Source:    toCommitSource(node.Merged.Commit),
+			})
+			continue
+		}
+		if event, ok := toEvent(node.timelineItemNode); ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// timelineVariables 返回时间线查询的变量
+func timelineVariables(owner, repo string, number, pageSize int) map[string]interface{} {
+	return map[string]interface{}{
+		"owner":          githubv4.String(owner),
+		"name":           githubv4.String(repo),
+		"number":         githubv4.Int(number),
+		"timelineFirst":  githubv4.Int(pageSize),
+		"timelineCursor": (*githubv4.String)(nil),
+	}
+}
+
+// toEvent 将时间线条目转换为 Event，不支持的类型返回 false
+func toEvent(node timelineItemNode) (Event, bool) {
+	var base eventNode
+	event := Event{}
+
+	switch node.Typename {
+	case "LabeledEvent":
+		base = node.Labeled.eventNode
+		event.Type = EventLabeled
+		event.Label = node.Labeled.Label.Name
+	case "UnlabeledEvent":
+		base = node.Unlabeled.eventNode
+		event.Type = EventUnlabeled
+		event.Label = node.Unlabeled.Label.Name
+	case "AssignedEvent":
+		base = node.Assigned.eventNode
+		event.Type = EventAssigned
+		event.Assignee = toAssignee(node.Assigned.Assignee)
+	case "UnassignedEvent":
+		base = node.Unassigned.eventNode
+		event.Type = EventUnassigned
+		event.Assignee = toAssignee(node.Unassigned.Assignee)
+	case "ClosedEvent":
+		base = node.Closed.eventNode
+		event.Type = EventClosed
+		event.StateReason = toString(node.Closed.StateReason)
+		if closer := node.Closed.Closer; closer != nil {
+			switch {
+			case closer.PullRequest.URL != "":
+				event.Source = &EventSource{Title: closer.PullRequest.Title, URL: closer.PullRequest.URL}
+			case closer.Commit.URL != "":
+				event.Source = toCommitSource(&closer.Commit)
+			}
+		}
+	case "ReopenedEvent":
+		base = node.Reopened.eventNode
+		event.Type = EventReopened
+	case "ReferencedEvent":
+		base = node.Referenced.eventNode
+		event.Type = EventReferenced
+		event.Source = toCommitSource(node.Referenced.Commit)
+	case "CrossReferencedEvent":
+		base = node.CrossReferenced.eventNode
+		event.Type = EventCrossReferenced
+		if source := node.CrossReferenced.Source; source.PullRequest.URL != "" {
+			event.Source = &EventSource{Title: source.PullRequest.Title, URL: source.PullRequest.URL}
+		} else if source.Issue.URL != "" {
+			event.Source = &EventSource{Title: source.Issue.Title, URL: source.Issue.URL}
+		}
+	case "RenamedTitleEvent":
+		base = node.Renamed.eventNode
+		event.Type = EventRenamed
+		event.PreviousTitle = node.Renamed.PreviousTitle
+		event.CurrentTitle = node.Renamed.CurrentTitle
+	case "MilestonedEvent":
+		base = node.Milestoned.eventNode
+		event.Type = EventMilestoned
+		event.Milestone = node.Milestoned.MilestoneTitle
+	case "DemilestonedEvent":
+		base = node.Demilestoned.eventNode
+		event.Type = EventDemilestoned
+		event.Milestone = node.Demilestoned.MilestoneTitle
+	default:
+		return Event{}, false
+	}
+
+	event.Actor = toLogin(base.Actor)
+	event.ActorURL = toProfileURL(base.Actor)
+	event.CreatedAt = toTime(base.CreatedAt)
+	return event, true
+}
+
+// toAssignee 返回被指派用户的登录名
+func toAssignee(node *assigneeNode) string {
+	if node == nil {
+		return ""
+	}
+	return node.Actor.Login
+}
+
+// toCommitSource 将 commit 转换为 EventSource，标题为缩写的 SHA
+func toCommitSource(commit *commitNode) *EventSource {
+	if commit == nil || commit.URL == "" {
+		return nil
+	}
+	return &EventSource{Title: commit.AbbreviatedOid, URL: commit.URL}
+}
@@ -2,53 +2,194 @@ package github
 
 import "time"
 
+// Thread Issue、Pull Request 和 Discussion 共有的主题帖数据
+type Thread struct {
+	ID                string // GraphQL 节点 ID
+	Title             string
+	Body              string
+	Author            string
+	AuthorURL         string // 用户主页链接
+	AuthorType        string // User, Bot, Mannequin, Organization
+	AuthorAssociation string // 作者与仓库的关系，如 OWNER, MEMBER, CONTRIBUTOR, FIRST_TIME_CONTRIBUTOR
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ClosedAt          time.Time // 未关闭时为零值
+	Status            string    // open, closed, merged（仅 Pull Request）
+	URL               string
+	Locked            bool // 是否已锁定，锁定后只有协作者可以评论
+	Labels            []string
+	Reactions         *Reactions
+	Comments          []Comment // 普通评论，按时间正序
+	Events            []Event   // 时间线事件，按时间正序；仅 Issue 和 Pull Request，需启用 WithTimeline
+
+	TotalComments     int  // API 返回的评论总数
+	CommentsTruncated bool // 是否因 MaxComments 限制而截断
+
+	Edits []Edit // 正文的修订记录，按时间正序；仅在启用 WithEditHistory 时获取
+}
+
 // Issue GitHub Issue 数据
 type Issue struct {
-	Title     string
-	Body      string
-	Author    string
-	AuthorURL string
-	CreatedAt time.Time
-	Status    string // open, closed
-	URL       string
-	Reactions *Reactions
-	Comments  []Comment
+	Thread
+
+	Assignees   []string
+	Milestone   *Milestone // 未设置时为 nil
+	StateReason string     // COMPLETED, NOT_PLANNED, DUPLICATE, REOPENED；从未关闭时为空
 }
 
 // PullRequest GitHub Pull Request 数据
 type PullRequest struct {
-	Title     string
-	Body      string
-	Author    string
-	AuthorURL string
-	CreatedAt time.Time
-	Status    string // open, closed, merged
-	URL       string
-	Reactions *Reactions
-	Comments  []Comment // 包含普通评论和 Review Comments
+	Thread
+
+	Assignees []string
+	Milestone *Milestone // 未设置时为 nil
+
+	IsDraft            bool
+	MergedAt           time.Time // 未合并时为零值
+	MergedBy           string    // 未合并时为空
+	BaseRef            string    // 目标分支
+	HeadRef            string    // 源分支
+	RequestedReviewers []string  // 尚未完成 Review 的请求，用户为登录名，团队为 org/team
+
+	Reviews       []Review       // Review 总结（批准、请求修改等）
+	ReviewThreads []ReviewThread // 行内代码评审讨论
+
+	Files        []ChangedFile // 变更文件，仅在启用 WithFiles 或 WithDiff 时获取
+	DiffTooLarge bool          // 启用 WithDiff 但 diff 超出 GitHub 的大小限制，Files 不含 Patch
 }
 
 // Discussion GitHub Discussion 数据
 type Discussion struct {
-	Title     string
-	Body      string
-	Author    string
-	AuthorURL string
-	CreatedAt time.Time
-	Status    string // open, closed
-	URL       string
-	Reactions *Reactions
-	Comments  []Comment
+	Thread
+
+	Category       *DiscussionCategory
+	UpvoteCount    int
+	Answer         *Comment  // 被采纳的答案，没有时为 nil
+	AnswerChosenAt time.Time // 没有答案时为零值
+	AnswerChosenBy string    // 没有答案时为空
+	Poll           *Poll     // 不是投票时为 nil
+}
+
+// DiscussionCategory Discussion 分类
+type DiscussionCategory struct {
+	Name         string
+	Emoji        string // 表情短代码，如 :pray:
+	IsAnswerable bool   // 是否可以采纳答案（如 Q&A 分类）
+}
+
+// Poll Discussion 投票
+type Poll struct {
+	Question   string
+	TotalVotes int
+	Options    []PollOption
+}
+
+// PollOption 投票选项及得票数
+type PollOption struct {
+	Option string
+	Votes  int
+}
+
+// Milestone 里程碑
+type Milestone struct {
+	Title string
+	DueOn time.Time // 未设置截止日期时为零值
 }
 
 // Comment 评论数据
 type Comment struct {
-	Author    string
-	AuthorURL string
-	Body      string
+	ID                string // GraphQL 节点 ID
+	Author            string
+	AuthorURL         string
+	AuthorType        string // 同 Thread.AuthorType
+	AuthorAssociation string // 同 Thread.AuthorAssociation
+	Body              string
+	CreatedAt         time.Time
+	URL               string // 评论链接，片段为评论锚点，如 https://github.com/owner/repo/issues/1#issuecomment-456
+	Reactions         *Reactions
+	IsAnswer          bool      // Discussion 特有
+	Replies           []Comment // Discussion 顶层评论的回复，按时间正序
+	IsMinimized       bool      // 是否被隐藏（折叠）
+	MinimizedReason   string    // 隐藏原因，如 spam、off-topic、outdated、resolved、duplicate、abuse
+	Edits             []Edit    // 同 Thread.Edits
+}
+
+// Edit 正文的一次修订
+type Edit struct {
+	Editor   string
+	EditedAt time.Time
+	Body     string // 该次修订后的完整正文
+	Deleted  bool   // 修订内容已被删除，Body 为空
+}
+
+// 时间线事件类型
+const (
+	EventLabeled         = "labeled"
+	EventUnlabeled       = "unlabeled"
+	EventAssigned        = "assigned"
+	EventUnassigned      = "unassigned"
+	EventClosed          = "closed"
+	EventReopened        = "reopened"
+	EventReferenced      = "referenced"
+	EventCrossReferenced = "cross_referenced"
+	EventRenamed         = "renamed"
+	EventMilestoned      = "milestoned"
+	EventDemilestoned    = "demilestoned"
+	EventMerged          = "merged"
+)
+
+// Event 时间线事件
+type Event struct {
+	Type      string // 见 Event 开头的常量
+	Actor     string
+	ActorURL  string
 	CreatedAt time.Time
-	Reactions *Reactions
-	IsAnswer  bool // Discussion 特有
+
+	Label         string       // labeled, unlabeled
+	Assignee      string       // assigned, unassigned
+	Milestone     string       // milestoned, demilestoned
+	StateReason   string       // closed：COMPLETED, NOT_PLANNED, DUPLICATE，Pull Request 为空
+	PreviousTitle string       // renamed
+	CurrentTitle  string       // renamed
+	Ref           string       // merged：合并到的分支
+	Source        *EventSource // closed 的关闭者、referenced 和 merged 的 commit、cross_referenced 的来源，可能为 nil
+}
+
+// EventSource 事件关联的 Issue、Pull Request 或 commit
+type EventSource struct {
+	Title string // commit 为缩写的 SHA
+	URL   string
+}
+
+// Review Pull Request Review 数据
+type Review struct {
+	Author            string
+	AuthorURL         string
+	AuthorType        string // 同 Thread.AuthorType
+	AuthorAssociation string // 同 Thread.AuthorAssociation
+	Body              string
+	State             string // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED, PENDING
+	SubmittedAt       time.Time
+}
+
+// ReviewThread 行内代码评审讨论
+type ReviewThread struct {
+	Path       string
+	Line       int // 结束行号，0 表示未知
+	StartLine  int // 多行评论的起始行号，单行评论为 0
+	DiffHunk   string
+	IsResolved bool
+	IsOutdated bool
+	Comments   []Comment // 第一条为发起评论，其余为回复
+}
+
+// ChangedFile Pull Request 的变更文件
+type ChangedFile struct {
+	Path       string
+	ChangeType string // ADDED, DELETED, MODIFIED, RENAMED, COPIED, CHANGED
+	Additions  int
+	Deletions  int
+	Patch      string // 该文件的 unified diff（从第一个 @@ 开始），仅在启用 WithDiff 时获取，二进制文件为空
 }
 
 // Reactions 反应统计
@@ -62,3 +203,16 @@ type Reactions struct {
 	Rocket     int
 	Eyes       int
 }
+
+// ItemSummary 仓库列表中的条目摘要，用于筛选和批量导出
+type ItemSummary struct {
+	Type      string // issue, pull_request, discussion
+	Number    int
+	Title     string
+	Author    string
+	Status    string // open, closed, merged
+	Labels    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	URL       string
+}
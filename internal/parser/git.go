@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// scpRemotePattern 匹配 scp 风格的 git 远程地址，如 git@github.com:owner/repo.git
+var scpRemotePattern = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// OriginRepository 读取 dir 所在 git 仓库的 .git/config，返回 origin remote 指向的 GitHub 仓库
+// dir 为空时使用当前目录，会向上查找 .git；hosts 的含义与 ParseURL 相同
+// 返回的 Repository.Types 为空
+func OriginRepository(dir string, hosts ...string) (*Repository, error) {
+	if dir == "" {
+		dir = "."
+	}
+
+	gitDir, err := findGitDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := readOriginURL(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRemoteURL(remote, hosts)
+}
+
+// findGitDir 从 dir 向上查找 git 目录
+// .git 为文件时（worktree、submodule）按其中的 gitdir 和 commondir 找到保存 config 的目录
+func findGitDir(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find git repository: %w", err)
+	}
+
+	for {
+		path := filepath.Join(abs, ".git")
+		info, err := os.Stat(path)
+		if err == nil {
+			if info.IsDir() {
+				return path, nil
+			}
+			return readGitFile(path)
+		}
+
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", fmt.Errorf("not a git repository: %s", dir)
+		}
+		abs = parent
+	}
+}
+
+// readGitFile 解析内容为 "gitdir: PATH" 的 .git 文件
+func readGitFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("invalid git file: %s", path)
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+
+	// worktree 的 config 保存在主仓库的 git 目录中
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		return commonDir, nil
+	}
+	return gitDir, nil
+}
+
+// readOriginURL 读取 git config 中 [remote "origin"] 的 url
+func readOriginURL(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git config: %w", err)
+	}
+	defer f.Close()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "url") {
+			return strings.Trim(strings.TrimSpace(value), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read git config: %w", err)
+	}
+
+	return "", fmt.Errorf("no origin remote in %s", path)
+}
+
+// parseRemoteURL 解析 git 远程地址
+//
+// 支持的格式:
+//   - https://github.com/owner/repo.git
+//   - ssh://git@github.com/owner/repo.git
+//   - git@github.com:owner/repo.git
+func parseRemoteURL(remote string, hosts []string) (*Repository, error) {
+	var host, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return nil, fmt.Errorf("invalid git remote: %s", remote)
+		}
+		host, path = u.Host, u.Path
+		if u.Scheme != "http" && u.Scheme != "https" {
+			// SSH 端口与网页地址无关
+			host = u.Hostname()
+		}
+	} else if matches := scpRemotePattern.FindStringSubmatch(remote); matches != nil {
+		host, path = matches[1], matches[2]
+	} else {
+		return nil, fmt.Errorf("invalid git remote: %s", remote)
+	}
+
+	host = strings.ToLower(host)
+	if !isKnownHost(host, hosts) {
+		return nil, fmt.Errorf("origin remote is not a GitHub repository: %s", remote)
+	}
+
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(path, "/"), ".git"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid git remote: %s", remote)
+	}
+
+	return &Repository{
+		Host:     host,
+		Owner:    parts[0],
+		Repo:     parts[1],
+		Original: remote,
+	}, nil
+}
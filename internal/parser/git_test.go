@@ -0,0 +1,178 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeGitConfig 在 dir 下创建 .git/config，origin 指向 remote
+func writeGitConfig(t *testing.T, dir, remote string) {
+	t.Helper()
+	gitDir := filepath.Join(dir, ".git")
+	if err := os.MkdirAll(gitDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "[core]\n\tbare = false\n" +
+		"[remote \"upstream\"]\n\turl = https://github.com/upstream/other.git\n" +
+		"[remote \"origin\"]\n\turl = " + remote + "\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n"
+	if err := os.WriteFile(filepath.Join(gitDir, "config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		remote        string
+		expectedHost  string
+		expectedOwner string
+		expectedRepo  string
+		expectError   bool
+	}{
+		{name: "HTTPS", remote: "https://github.com/owner/repo.git", expectedHost: "github.com", expectedOwner: "owner", expectedRepo: "repo"},
+		{name: "HTTPS 无 .git", remote: "https://github.com/owner/repo", expectedHost: "github.com", expectedOwner: "owner", expectedRepo: "repo"},
+		{name: "scp 风格", remote: "git@github.com:owner/repo.git", expectedHost: "github.com", expectedOwner: "owner", expectedRepo: "repo"},
+		{name: "SSH URL 带端口", remote: "ssh://git@github.example.com:2222/owner/repo.git", expectedHost: "github.example.com", expectedOwner: "owner", expectedRepo: "repo"},
+		{name: "Enterprise HTTP 带端口", remote: "http://localhost:8080/owner/repo.git", expectedHost: "localhost:8080", expectedOwner: "owner", expectedRepo: "repo"},
+		{name: "其他站点", remote: "git@gitlab.com:owner/repo.git", expectError: true},
+		{name: "路径层级不对", remote: "https://github.com/owner/group/repo.git", expectError: true},
+		{name: "本地路径", remote: "/srv/git/repo.git", expectError: true},
+	}
+
+	hosts := []string{"github.example.com", "localhost:8080"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseRemoteURL(tt.remote, hosts)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("parseRemoteURL(%q) expected error, got %+v", tt.remote, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRemoteURL(%q) unexpected error: %v", tt.remote, err)
+			}
+			if result.Host != tt.expectedHost || result.Owner != tt.expectedOwner || result.Repo != tt.expectedRepo {
+				t.Errorf("parseRemoteURL(%q) = %s/%s/%s, want %s/%s/%s", tt.remote,
+					result.Host, result.Owner, result.Repo, tt.expectedHost, tt.expectedOwner, tt.expectedRepo)
+			}
+		})
+	}
+}
+
+func TestOriginRepository(t *testing.T) {
+	t.Run("子目录向上查找", func(t *testing.T) {
+		root := t.TempDir()
+		writeGitConfig(t, root, "git@github.com:owner/repo.git")
+		sub := filepath.Join(root, "a", "b")
+		if err := os.MkdirAll(sub, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		repo, err := OriginRepository(sub)
+		if err != nil {
+			t.Fatalf("OriginRepository() error = %v", err)
+		}
+		if repo.Owner != "owner" || repo.Repo != "repo" {
+			t.Errorf("OriginRepository() = %s/%s, want owner/repo", repo.Owner, repo.Repo)
+		}
+	})
+
+	t.Run("worktree", func(t *testing.T) {
+		root := t.TempDir()
+		mainRepo := filepath.Join(root, "main")
+		writeGitConfig(t, mainRepo, "https://github.com/owner/repo.git")
+
+		worktreeGitDir := filepath.Join(mainRepo, ".git", "worktrees", "feature")
+		if err := os.MkdirAll(worktreeGitDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(worktreeGitDir, "commondir"), []byte("../..\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		worktree := filepath.Join(root, "feature")
+		if err := os.MkdirAll(worktree, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+worktreeGitDir+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		repo, err := OriginRepository(worktree)
+		if err != nil {
+			t.Fatalf("OriginRepository() error = %v", err)
+		}
+		if repo.Owner != "owner" || repo.Repo != "repo" {
+			t.Errorf("OriginRepository() = %s/%s, want owner/repo", repo.Owner, repo.Repo)
+		}
+	})
+
+	t.Run("没有 origin", func(t *testing.T) {
+		root := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(root, ".git"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, ".git", "config"), []byte("[core]\n\tbare = false\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := OriginRepository(root); err == nil {
+			t.Error("OriginRepository() expected error, got nil")
+		}
+	})
+}
+
+func TestParseURLReference(t *testing.T) {
+	root := t.TempDir()
+	writeGitConfig(t, root, "git@github.example.com:team/project.git")
+	t.Chdir(root)
+
+	tests := []struct {
+		name           string
+		ref            string
+		expectedType   ResourceType
+		expectedHost   string
+		expectedOwner  string
+		expectedRepo   string
+		expectedNumber int
+		expectError    bool
+	}{
+		{name: "owner/repo#N", ref: "owner/repo#123", expectedType: ResourceTypeAmbiguous, expectedHost: "github.com", expectedOwner: "owner", expectedRepo: "repo", expectedNumber: 123},
+		{name: "owner/repo!N", ref: "owner/repo!42", expectedType: ResourceTypePullRequest, expectedHost: "github.com", expectedOwner: "owner", expectedRepo: "repo", expectedNumber: 42},
+		{name: "#N 使用 origin", ref: "#7", expectedType: ResourceTypeAmbiguous, expectedHost: "github.example.com", expectedOwner: "team", expectedRepo: "project", expectedNumber: 7},
+		{name: "!N 使用 origin", ref: "!8", expectedType: ResourceTypePullRequest, expectedHost: "github.example.com", expectedOwner: "team", expectedRepo: "project", expectedNumber: 8},
+		{name: "缺少编号", ref: "owner/repo#", expectError: true},
+		{name: "多级路径", ref: "a/b/c#1", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseURL(tt.ref, "github.example.com")
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseURL(%q) expected error, got %+v", tt.ref, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseURL(%q) unexpected error: %v", tt.ref, err)
+			}
+			if result.Type != tt.expectedType {
+				t.Errorf("ParseURL(%q).Type = %q, want %q", tt.ref, result.Type, tt.expectedType)
+			}
+			if result.Host != tt.expectedHost || result.Owner != tt.expectedOwner || result.Repo != tt.expectedRepo {
+				t.Errorf("ParseURL(%q) = %s/%s/%s, want %s/%s/%s", tt.ref,
+					result.Host, result.Owner, result.Repo, tt.expectedHost, tt.expectedOwner, tt.expectedRepo)
+			}
+			if result.Number != tt.expectedNumber {
+				t.Errorf("ParseURL(%q).Number = %d, want %d", tt.ref, result.Number, tt.expectedNumber)
+			}
+			if result.Original != tt.ref {
+				t.Errorf("ParseURL(%q).Original = %q, want %q", tt.ref, result.Original, tt.ref)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -14,57 +15,185 @@ const (
 	ResourceTypeIssue       ResourceType = "issue"
 	ResourceTypePullRequest ResourceType = "pull_request"
 	ResourceTypeDiscussion  ResourceType = "discussion"
+
+	// ResourceTypeAmbiguous 简写引用 owner/repo#N 的类型
+	// Issue、Pull Request 和 Discussion 共用编号，需要通过 API 确定实际类型
+	ResourceTypeAmbiguous ResourceType = "ambiguous"
 )
 
+// DefaultHost github.com 主机名，始终被接受
+const DefaultHost = "github.com"
+
 // Resource 解析后的 GitHub 资源信息
 type Resource struct {
 	Type     ResourceType
+	Host     string // 主机名，如 github.com 或 GitHub Enterprise Server 的主机名
 	Owner    string
 	Repo     string
 	Number   int
+	Anchor   string // URL 片段中的评论锚点，如 issuecomment-456、discussion_r123、discussioncomment-789；未指定时为空
 	Original string // 原始 URL
 }
 
-// GitHub URL 正则表达式
+// Repository 解析后的仓库 URL 信息，用于导出仓库中的多个资源
+type Repository struct {
+	Host     string
+	Owner    string
+	Repo     string
+	Types    []ResourceType // 要导出的资源类型
+	Original string         // 原始 URL
+}
+
+// GitHub URL 路径正则表达式
 var (
-	issueURLPattern       = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/issues/(\d+)`)
-	pullRequestURLPattern = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
-	discussionURLPattern  = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/discussions/(\d+)`)
+	issuePathPattern       = regexp.MustCompile(`^/([^/]+)/([^/]+)/issues/(\d+)`)
+	pullRequestPathPattern = regexp.MustCompile(`^/([^/]+)/([^/]+)/pull/(\d+)`)
+	discussionPathPattern  = regexp.MustCompile(`^/([^/]+)/([^/]+)/discussions/(\d+)`)
+	repositoryPathPattern  = regexp.MustCompile(`^/([^/]+)/([^/]+?)(?:\.git)?(?:/(issues|pulls|discussions))?/?$`)
+	commentAnchorPattern   = regexp.MustCompile(`^(?:issuecomment-|discussion_r|discussioncomment-)\d+$`)
+	referencePattern       = regexp.MustCompile(`^(?:([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+))?([#!])(\d+)$`)
 )
 
 // ParseURL 解析 GitHub URL 并返回 Resource 信息
 // 如果 URL 格式无效或不是支持的类型，返回错误
 //
+// hosts 为额外接受的 GitHub Enterprise Server 主机名（可包含端口），
+// github.com 始终被接受且必须使用 https；额外主机同时接受 http 和 https。
+//
 // 支持的 URL 格式:
 //   - https://github.com/owner/repo/issues/{number}
 //   - https://github.com/owner/repo/pull/{number}
 //   - https://github.com/owner/repo/discussions/{number}
-func ParseURL(url string) (*Resource, error) {
+//
+// 片段为评论锚点（#issuecomment-N、#discussion_rN、#discussioncomment-N）时记录到 Resource.Anchor，
+// 其他片段被忽略
+//
+// 同时支持简写引用:
+//   - owner/repo#{number}: github.com 上的仓库，类型为 ResourceTypeAmbiguous
+//   - owner/repo!{number}: github.com 上的 Pull Request
+//   - #{number}、!{number}: 当前目录所在 git 仓库的 origin remote 指向的仓库
+func ParseURL(rawURL string, hosts ...string) (*Resource, error) {
+	if matches := referencePattern.FindStringSubmatch(rawURL); matches != nil {
+		return parseReference(rawURL, matches, hosts)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || !isAllowedHost(u, hosts) {
+		return nil, fmt.Errorf("invalid GitHub URL: %s", rawURL)
+	}
+
+	host := strings.ToLower(u.Host)
+
+	var anchor string
+	if commentAnchorPattern.MatchString(u.Fragment) {
+		anchor = u.Fragment
+	}
+
 	// 尝试匹配 Issue URL
-	if matches := issueURLPattern.FindStringSubmatch(url); matches != nil {
-		return parseMatches(url, matches, ResourceTypeIssue)
+	if matches := issuePathPattern.FindStringSubmatch(u.Path); matches != nil {
+		return parseMatches(rawURL, host, anchor, matches, ResourceTypeIssue)
 	}
 
 	// 尝试匹配 Pull Request URL
-	if matches := pullRequestURLPattern.FindStringSubmatch(url); matches != nil {
-		return parseMatches(url, matches, ResourceTypePullRequest)
+	if matches := pullRequestPathPattern.FindStringSubmatch(u.Path); matches != nil {
+		return parseMatches(rawURL, host, anchor, matches, ResourceTypePullRequest)
 	}
 
 	// 尝试匹配 Discussion URL
-	if matches := discussionURLPattern.FindStringSubmatch(url); matches != nil {
-		return parseMatches(url, matches, ResourceTypeDiscussion)
+	if matches := discussionPathPattern.FindStringSubmatch(u.Path); matches != nil {
+		return parseMatches(rawURL, host, anchor, matches, ResourceTypeDiscussion)
 	}
 
-	// 检查是否为 GitHub URL 但不是支持的资源类型
-	if strings.HasPrefix(url, "https://github.com/") {
-		return nil, fmt.Errorf("unsupported resource type: %s", url)
+	// 是 GitHub URL 但不是支持的资源类型
+	return nil, fmt.Errorf("unsupported resource type: %s", rawURL)
+}
+
+// ParseRepositoryURL 解析仓库 URL 并返回 Repository 信息
+// hosts 的含义与 ParseURL 相同
+//
+// 支持的 URL 格式:
+//   - https://github.com/owner/repo（Issue、Pull Request 和 Discussion）
+//   - https://github.com/owner/repo/issues
+//   - https://github.com/owner/repo/pulls
+//   - https://github.com/owner/repo/discussions
+func ParseRepositoryURL(rawURL string, hosts ...string) (*Repository, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !isAllowedHost(u, hosts) {
+		return nil, fmt.Errorf("invalid GitHub URL: %s", rawURL)
 	}
 
-	return nil, fmt.Errorf("invalid GitHub URL: %s", url)
+	matches := repositoryPathPattern.FindStringSubmatch(u.Path)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid repository URL: %s", rawURL)
+	}
+
+	var types []ResourceType
+	switch matches[3] {
+	case "issues":
+		types = []ResourceType{ResourceTypeIssue}
+	case "pulls":
+		types = []ResourceType{ResourceTypePullRequest}
+	case "discussions":
+		types = []ResourceType{ResourceTypeDiscussion}
+	default:
+		types = []ResourceType{ResourceTypeIssue, ResourceTypePullRequest, ResourceTypeDiscussion}
+	}
+
+	return &Repository{
+		Host:     strings.ToLower(u.Host),
+		Owner:    matches[1],
+		Repo:     matches[2],
+		Types:    types,
+		Original: rawURL,
+	}, nil
+}
+
+// parseReference 解析简写引用，matches 为 referencePattern 的匹配结果
+func parseReference(ref string, matches []string, hosts []string) (*Resource, error) {
+	repo := &Repository{Host: DefaultHost, Owner: matches[1], Repo: matches[2]}
+	if repo.Owner == "" {
+		origin, err := OriginRepository("", hosts...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %s: %w", ref, err)
+		}
+		repo = origin
+	}
+
+	typ := ResourceTypeAmbiguous
+	if matches[3] == "!" {
+		typ = ResourceTypePullRequest
+	}
+
+	return parseMatches(ref, repo.Host, "", []string{ref, repo.Owner, repo.Repo, matches[4]}, typ)
+}
+
+// isAllowedHost 判断 URL 的 scheme 和主机名是否被接受
+func isAllowedHost(u *url.URL, hosts []string) bool {
+	host := strings.ToLower(u.Host)
+	if host == DefaultHost {
+		return u.Scheme == "https"
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return false
+	}
+	return isKnownHost(host, hosts)
+}
+
+// isKnownHost 判断主机名是否为 github.com 或 hosts 中的主机
+func isKnownHost(host string, hosts []string) bool {
+	if host == DefaultHost {
+		return true
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(strings.TrimSpace(h), host) {
+			return true
+		}
+	}
+	return false
 }
 
 // parseMatches 解析正则匹配结果
-func parseMatches(url string, matches []string, typ ResourceType) (*Resource, error) {
+func parseMatches(rawURL, host, anchor string, matches []string, typ ResourceType) (*Resource, error) {
 	owner := matches[1]
 	repo := matches[2]
 	numberStr := matches[3]
@@ -76,9 +205,11 @@ func parseMatches(url string, matches []string, typ ResourceType) (*Resource, er
 
 	return &Resource{
 		Type:     typ,
+		Host:     host,
 		Owner:    owner,
 		Repo:     repo,
 		Number:   number,
-		Original: url,
+		Anchor:   anchor,
+		Original: rawURL,
 	}, nil
 }
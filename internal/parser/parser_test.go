@@ -12,6 +12,7 @@ func TestParseURL(t *testing.T) {
 		expectedOwner  string
 		expectedRepo   string
 		expectedNumber int
+		expectedAnchor string
 		expectError    bool
 	}{
 		{
@@ -30,6 +31,7 @@ func TestParseURL(t *testing.T) {
 			expectedOwner:  "owner",
 			expectedRepo:   "repo",
 			expectedNumber: 123,
+			expectedAnchor: "issuecomment-456",
 			expectError:    false,
 		},
 		{
@@ -48,6 +50,7 @@ func TestParseURL(t *testing.T) {
 			expectedOwner:  "owner",
 			expectedRepo:   "repo",
 			expectedNumber: 42,
+			expectedAnchor: "discussion_r123",
 			expectError:    false,
 		},
 		{
@@ -66,6 +69,16 @@ func TestParseURL(t *testing.T) {
 			expectedOwner:  "owner",
 			expectedRepo:   "repo",
 			expectedNumber: 7,
+			expectedAnchor: "discussioncomment-890",
+			expectError:    false,
+		},
+		{
+			name:           "Issue URL with other fragment",
+			url:            "https://github.com/owner/repo/issues/123#event-789",
+			expectedType:   ResourceTypeIssue,
+			expectedOwner:  "owner",
+			expectedRepo:   "repo",
+			expectedNumber: 123,
 			expectError:    false,
 		},
 		{
@@ -113,9 +126,192 @@ func TestParseURL(t *testing.T) {
 			if result.Number != tt.expectedNumber {
 				t.Errorf("ParseURL(%q).Number = %d, want %d", tt.url, result.Number, tt.expectedNumber)
 			}
+			if result.Anchor != tt.expectedAnchor {
+				t.Errorf("ParseURL(%q).Anchor = %q, want %q", tt.url, result.Anchor, tt.expectedAnchor)
+			}
 			if result.Original != tt.url {
 				t.Errorf("ParseURL(%q).Original = %q, want %q", tt.url, result.Original, tt.url)
 			}
 		})
 	}
 }
+
+func TestParseURLEnterpriseHosts(t *testing.T) {
+	hosts := []string{"github.example.com", "localhost:8080"}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedType   ResourceType
+		expectedHost   string
+		expectedNumber int
+		expectError    bool
+	}{
+		{
+			name:           "github.com 始终被接受",
+			url:            "https://github.com/owner/repo/issues/1",
+			expectedType:   ResourceTypeIssue,
+			expectedHost:   "github.com",
+			expectedNumber: 1,
+		},
+		{
+			name:           "Enterprise Issue URL",
+			url:            "https://github.example.com/owner/repo/issues/123",
+			expectedType:   ResourceTypeIssue,
+			expectedHost:   "github.example.com",
+			expectedNumber: 123,
+		},
+		{
+			name:           "Enterprise 主机名大小写不敏感",
+			url:            "https://GitHub.Example.com/owner/repo/pull/42",
+			expectedType:   ResourceTypePullRequest,
+			expectedHost:   "github.example.com",
+			expectedNumber: 42,
+		},
+		{
+			name:           "本地 http 服务",
+			url:            "http://localhost:8080/owner/repo/discussions/7",
+			expectedType:   ResourceTypeDiscussion,
+			expectedHost:   "localhost:8080",
+			expectedNumber: 7,
+		},
+		{
+			name:        "未配置的主机",
+			url:         "https://git.other.com/owner/repo/issues/1",
+			expectError: true,
+		},
+		{
+			name:        "github.com 不接受 http",
+			url:         "http://github.com/owner/repo/issues/1",
+			expectError: true,
+		},
+		{
+			name:        "Enterprise 不支持的资源类型",
+			url:         "https://github.example.com/owner/repo/wiki",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseURL(tt.url, hosts...)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseURL(%q) expected error, got nil", tt.url)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseURL(%q) unexpected error: %v", tt.url, err)
+			}
+
+			if result.Type != tt.expectedType {
+				t.Errorf("ParseURL(%q).Type = %q, want %q", tt.url, result.Type, tt.expectedType)
+			}
+			if result.Host != tt.expectedHost {
+				t.Errorf("ParseURL(%q).Host = %q, want %q", tt.url, result.Host, tt.expectedHost)
+			}
+			if result.Number != tt.expectedNumber {
+				t.Errorf("ParseURL(%q).Number = %d, want %d", tt.url, result.Number, tt.expectedNumber)
+			}
+		})
+	}
+}
+
+func TestParseRepositoryURL(t *testing.T) {
+	all := []ResourceType{ResourceTypeIssue, ResourceTypePullRequest, ResourceTypeDiscussion}
+
+	tests := []struct {
+		name          string
+		url           string
+		expectedOwner string
+		expectedRepo  string
+		expectedTypes []ResourceType
+		expectError   bool
+	}{
+		{
+			name:          "仓库 URL",
+			url:           "https://github.com/owner/repo",
+			expectedOwner: "owner",
+			expectedRepo:  "repo",
+			expectedTypes: all,
+		},
+		{
+			name:          "带 .git 后缀和结尾斜杠",
+			url:           "https://github.com/owner/repo.git/",
+			expectedOwner: "owner",
+			expectedRepo:  "repo",
+			expectedTypes: all,
+		},
+		{
+			name:          "Issue 列表",
+			url:           "https://github.com/owner/repo/issues",
+			expectedOwner: "owner",
+			expectedRepo:  "repo",
+			expectedTypes: []ResourceType{ResourceTypeIssue},
+		},
+		{
+			name:          "Pull Request 列表",
+			url:           "https://github.com/owner/repo/pulls/",
+			expectedOwner: "owner",
+			expectedRepo:  "repo",
+			expectedTypes: []ResourceType{ResourceTypePullRequest},
+		},
+		{
+			name:          "Discussion 列表",
+			url:           "https://github.com/owner/repo/discussions",
+			expectedOwner: "owner",
+			expectedRepo:  "repo",
+			expectedTypes: []ResourceType{ResourceTypeDiscussion},
+		},
+		{
+			name:        "单个 Issue 不是仓库 URL",
+			url:         "https://github.com/owner/repo/issues/123",
+			expectError: true,
+		},
+		{
+			name:        "其他页面",
+			url:         "https://github.com/owner/repo/tree/main",
+			expectError: true,
+		},
+		{
+			name:        "非 GitHub URL",
+			url:         "https://example.com/owner/repo",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRepositoryURL(tt.url)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseRepositoryURL(%q) expected error, got nil", tt.url)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseRepositoryURL(%q) unexpected error: %v", tt.url, err)
+			}
+
+			if result.Owner != tt.expectedOwner {
+				t.Errorf("ParseRepositoryURL(%q).Owner = %q, want %q", tt.url, result.Owner, tt.expectedOwner)
+			}
+			if result.Repo != tt.expectedRepo {
+				t.Errorf("ParseRepositoryURL(%q).Repo = %q, want %q", tt.url, result.Repo, tt.expectedRepo)
+			}
+			if len(result.Types) != len(tt.expectedTypes) {
+				t.Fatalf("ParseRepositoryURL(%q).Types = %v, want %v", tt.url, result.Types, tt.expectedTypes)
+			}
+			for i := range result.Types {
+				if result.Types[i] != tt.expectedTypes[i] {
+					t.Errorf("ParseRepositoryURL(%q).Types[%d] = %q, want %q", tt.url, i, result.Types[i], tt.expectedTypes[i])
+				}
+			}
+		})
+	}
+}